@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/coinbase/watchdog/controller"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+)
+
+// listFlag is a flag which could be repeated or hold comma separated values.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+
+	return nil
+}
+
+// parseAdoptArgs parses the arguments of the adopt command. Component IDs are passed with a flag
+// named after the config key of every registered component type, e.g. --dashboards 1,2 --monitors 3.
+func parseAdoptArgs(args []string, registry *types.Registry) (*controller.AdoptOptions, error) {
+	fs := flag.NewFlagSet("adopt", flag.ContinueOnError)
+
+	var (
+		opts = &controller.AdoptOptions{Components: make(map[types.Component][]string)}
+		tags listFlag
+		ids  = make(map[types.Component]*listFlag)
+	)
+
+	fs.StringVar(&opts.Team, "team", "", "team which adopts the components, required")
+	fs.StringVar(&opts.Project, "project", "", "project of the team, optional")
+	fs.StringVar(&opts.Slack, "slack", "", "slack channel of a new user config, optional")
+	fs.Var(&tags, "tag", "adopt components with all the given datadog tags, could be repeated")
+	fs.StringVar(&opts.TitlePrefix, "title-prefix", "", "adopt components with the title prefix")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "print the planned files without opening a pull request")
+
+	for _, ct := range registry.ComponentTypes() {
+		ids[ct.Type()] = &listFlag{}
+		fs.Var(ids[ct.Type()], ct.ConfigKey(), fmt.Sprintf("comma separated IDs of %s to adopt", ct.ConfigKey()))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if opts.Team == "" {
+		return nil, errors.New("--team is required")
+	}
+
+	opts.Tags = tags
+	for component, list := range ids {
+		if len(*list) > 0 {
+			opts.Components[component] = *list
+		}
+	}
+
+	if len(opts.Components) == 0 && len(opts.Tags) == 0 && opts.TitlePrefix == "" {
+		return nil, errors.New("no components to adopt, use component ID flags, --tag or --title-prefix")
+	}
+
+	return opts, nil
+}
+
+// runAdopt adopts the components and prints the planned files, or the pull request for a real run.
+func runAdopt(ctx context.Context, c *controller.Controller, opts *controller.AdoptOptions, out io.Writer) error {
+	plan, err := c.Adopt(ctx, *opts)
+	if err != nil {
+		return errors.Wrap(err, "unable to adopt components")
+	}
+
+	for _, skipped := range plan.Skipped {
+		fmt.Fprintf(out, "skipped: %s\n", skipped)
+	}
+
+	for _, file := range plan.FilePaths() {
+		fmt.Fprintf(out, "file: %s\n", file)
+	}
+
+	fmt.Fprintf(out, "user config: %s\n%s", plan.ConfigFile, plan.Config)
+
+	if !opts.DryRun {
+		fmt.Fprintf(out, "onboarding pull request: %d\n", plan.PullRequest)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/coinbase/watchdog/primitives/datadog/types"
@@ -10,6 +11,14 @@ import (
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrNilUserConfig is returned if a user config is not provided.
+	ErrNilUserConfig = errors.New("user config is nil")
+
+	// ErrNilRegistry is returned if a component type registry is not provided.
+	ErrNilRegistry = errors.New("component type registry is nil")
+)
+
 // NewConfig returns a new instance of Config object.
 func NewConfig(ctx context.Context, sysCfg SystemConfig, userCfg UserConfig) (*Config, error) {
 	var err error
@@ -21,11 +30,9 @@ func NewConfig(ctx context.Context, sysCfg SystemConfig, userCfg UserConfig) (*C
 		}
 	}
 
+	// user config depends on the registered component types, so it cannot be created with defaults.
 	if userCfg == nil {
-		userCfg, err = NewUserConfigFromGit(ctx)
-		if err != nil {
-			return nil, errors.Wrap(err, "unable to create a new user config")
-		}
+		return nil, ErrNilUserConfig
 	}
 
 	return &Config{
@@ -41,13 +48,46 @@ type Config struct {
 }
 
 // ComponentPath returns a path to a component json representation.
-func (c *Config) ComponentPath(component types.Component, team, project string, id int) string {
-	destDir := strings.Join([]string{c.SystemConfig.GetDatadogDataPath(), team}, "/")
-	filename := fmt.Sprintf("%s/%s-%d.json", destDir, component, id)
+func (c *Config) ComponentPath(component types.Component, team, project, id string) string {
+	return ComponentPath(c.SystemConfig.GetDatadogDataPath(), component, team, project, id)
+}
+
+// ComponentPath returns a path to a component json representation under a data path.
+func ComponentPath(dataPath string, component types.Component, team, project, id string) string {
+	destDir := strings.Join([]string{dataPath, team}, "/")
+	filename := fmt.Sprintf("%s/%s-%s.json", destDir, component, id)
 
 	if project != "" {
-		filename = fmt.Sprintf("%s/%s/%s-%d.json", destDir, project, component, id)
+		filename = fmt.Sprintf("%s/%s/%s-%s.json", destDir, project, component, id)
 	}
 
 	return filename
 }
+
+// ParseComponentPath returns a component type and ID from a component file name built by ComponentPath.
+// A file name without an ID, e.g. of a component to be created, is not parsed.
+func ParseComponentPath(registry *types.Registry, file string) (types.Component, string, bool) {
+	name := path.Base(file)
+	if !strings.HasSuffix(name, ".json") {
+		return "", "", false
+	}
+	name = strings.TrimSuffix(name, ".json")
+
+	var (
+		component types.Component
+		id        string
+	)
+	for _, ct := range registry.ComponentTypes() {
+		prefix := string(ct.Type()) + "-"
+		// prefer the longest type name, so a type is not matched by another type which is its prefix.
+		if strings.HasPrefix(name, prefix) && len(ct.Type()) > len(component) {
+			component, id = ct.Type(), strings.TrimPrefix(name, prefix)
+		}
+	}
+
+	if component == "" || id == "" {
+		return "", "", false
+	}
+
+	return component, id, true
+}
@@ -64,6 +64,23 @@ func TestEnterpriseGitURL(t *testing.T) {
 	}
 }
 
+func TestGithubReviewer(t *testing.T) {
+	cfg := &envVarSysConfig{
+		GithubReviewers: []string{"jane@example.com=jane", " John@example.com = john "},
+	}
+
+	for user, expected := range map[string]string{
+		"jane@example.com": "jane",
+		"john@example.com": "john",
+		"ann@example.com":  "",
+		"":                 "",
+	} {
+		if reviewer := cfg.GetGithubReviewer(user); reviewer != expected {
+			t.Fatalf("expect reviewer %q of %q. Got %q", expected, user, reviewer)
+		}
+	}
+}
+
 func TestPrivateKey(t *testing.T) {
 	cfg := &envVarSysConfig{
 		GithubAppPrivateKey: privateKey,
@@ -119,12 +136,13 @@ func TestFindUserConfigs(t *testing.T) {
 
 func TestTeamByID(t *testing.T) {
 	cfg := &userGitConfig{
+		registry:     newTestRegistry(t),
 		readDirFn:    ioutil.ReadDir,
 		readFileFn:   ioutil.ReadFile,
 		basePath:     "./fixtures/configs",
 		pullMasterFn: func() error { return nil },
 	}
-	err := cfg.Reload()
+	err := cfg.Reload(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -137,17 +155,22 @@ func TestComponentPath(t *testing.T) {
 	}
 
 	expectedPath := "data/foo/bar/test/dashboard-42.json"
-	if path := cfg.ComponentPath(types.ComponentDashboard, "foo/bar", "test", 42); path != expectedPath {
+	if path := cfg.ComponentPath(types.ComponentDashboard, "foo/bar", "test", "42"); path != expectedPath {
 		t.Fatalf("expect path %s. Got %s", expectedPath, path)
 	}
 
 	expectedPath = "data/infra/sre/screenboard-52.json"
-	if path := cfg.ComponentPath(types.ComponentScreenboard, "infra/sre", "", 52); path != expectedPath {
+	if path := cfg.ComponentPath(types.ComponentScreenboard, "infra/sre", "", "52"); path != expectedPath {
 		t.Fatalf("expect path %s. Got %s", expectedPath, path)
 	}
 
 	expectedPath = "data/hello/world/monitor-55.json"
-	if path := cfg.ComponentPath(types.ComponentMonitor, "hello/world", "", 55); path != expectedPath {
+	if path := cfg.ComponentPath(types.ComponentMonitor, "hello/world", "", "55"); path != expectedPath {
+		t.Fatalf("expect path %s. Got %s", expectedPath, path)
+	}
+
+	expectedPath = "data/hello/world/unified_dashboard-abc-def-ghi.json"
+	if path := cfg.ComponentPath(types.ComponentUnifiedDashboard, "hello/world", "", "abc-def-ghi"); path != expectedPath {
 		t.Fatalf("expect path %s. Got %s", expectedPath, path)
 	}
 }
@@ -155,11 +178,15 @@ func TestComponentPath(t *testing.T) {
 // mock user config
 type fakeUserConfig struct{}
 
-func (f fakeUserConfig) Reload() error {
+func (f fakeUserConfig) Reload(ctx context.Context) error {
 	return nil
 }
 
-func (f fakeUserConfig) UserConfigFilesByComponentID(c types.Component, id int) []*UserConfigFile {
+func (f fakeUserConfig) RefreshSelectors(ctx context.Context) (map[*UserConfigFile]map[types.Component][]string, error) {
+	return nil, nil
+}
+
+func (f fakeUserConfig) UserConfigFilesByComponentID(c types.Component, id string) []*UserConfigFile {
 	return nil
 }
 
@@ -195,10 +222,62 @@ func (f fakeSystemsConfig) GetDatadogPollingScheduler() string {
 	return ""
 }
 
+func (f fakeSystemsConfig) GetDatadogPollingStatePath() string {
+	return ""
+}
+
 func (f fakeSystemsConfig) GetDatadogPollingInterval() time.Duration {
 	return time.Second
 }
 
+func (f fakeSystemsConfig) GetDatadogPollingHashInterval() time.Duration {
+	return time.Second
+}
+
+func (f fakeSystemsConfig) GetDatadogSite() string {
+	return "US1"
+}
+
+func (f fakeSystemsConfig) GetDatadogAPIURL() string {
+	return ""
+}
+
+func (f fakeSystemsConfig) GetDatadogRequestTimeout() time.Duration {
+	return time.Second
+}
+
+func (f fakeSystemsConfig) GetDatadogMaxDeletions() int {
+	return 2
+}
+
+func (f fakeSystemsConfig) GetDatadogDeletedCheckInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogSelectorRefreshInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogDiscoveryInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogQuietPeriod() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogQuietPeriodPerTeam() bool {
+	return false
+}
+
+func (f fakeSystemsConfig) GetGithubReviewer(datadogUser string) string {
+	return ""
+}
+
+func (f fakeSystemsConfig) GetGithubUpdatePullRequests() bool {
+	return false
+}
+
 func (f fakeSystemsConfig) GetGithubDatadogDataPath() string {
 	return ""
 }
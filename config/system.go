@@ -33,7 +33,39 @@ type SystemConfig interface {
 	GetDatadogAPIKey() string
 	GetDatadogAPPKey() string
 	GetDatadogPollingScheduler() string
+	GetDatadogPollingStatePath() string
 	GetDatadogPollingInterval() time.Duration
+	GetDatadogPollingHashInterval() time.Duration
+
+	// GetDatadogSite returns a Datadog site the organization is hosted on, e.g. US1, US3, US5, EU, gov.
+	GetDatadogSite() string
+
+	// GetDatadogAPIURL returns a custom base URL of Datadog API. If set, it takes precedence over the site.
+	GetDatadogAPIURL() string
+
+	// GetDatadogRequestTimeout returns a timeout of a single request to Datadog API.
+	GetDatadogRequestTimeout() time.Duration
+
+	// GetDatadogMaxDeletions returns the maximum number of components deleted by a single pull request.
+	GetDatadogMaxDeletions() int
+
+	// GetDatadogDeletedCheckInterval returns an interval to check if managed components were deleted in datadog.
+	GetDatadogDeletedCheckInterval() time.Duration
+
+	// GetDatadogSelectorRefreshInterval returns an interval to resolve user config selectors against datadog.
+	GetDatadogSelectorRefreshInterval() time.Duration
+
+	// GetDatadogDiscoveryInterval returns an interval to send the discovery summary to team channels.
+	GetDatadogDiscoveryInterval() time.Duration
+
+	// GetDatadogQuietPeriod returns a period without changes of a component in datadog after which the pending
+	// changes of its user config file are merged into a single pull request. Zero disables the coalescing.
+	GetDatadogQuietPeriod() time.Duration
+
+	// GetDatadogQuietPeriodPerTeam returns true if the pending changes are held until no component of the team
+	// changed for the quiet period.
+	GetDatadogQuietPeriodPerTeam() bool
+
 	GetGithubDatadogDataPath() string
 	GetGithubBaseURL() string
 	GetGithubProjectOwner() string
@@ -41,6 +73,15 @@ type SystemConfig interface {
 	GetGithubIntegrationID() int
 	GetGithubAppInstallationID() int
 	GetGithubWebhookSecret() string
+
+	// GetGithubReviewer returns a github login of a datadog user, requested to review the pull requests
+	// of the changes made by the user. Empty is returned if the user is not mapped.
+	GetGithubReviewer(datadogUser string) string
+
+	// GetGithubUpdatePullRequests returns true if an open pull request with an outdated change is updated
+	// with the new change instead of being closed in favor of a new pull request.
+	GetGithubUpdatePullRequests() bool
+
 	GetLoggingLevel() string
 	GetLoggingJSON() bool
 	GetIgnoreKnownHosts() bool
@@ -95,14 +136,55 @@ type envVarSysConfig struct {
 	// DatadogAPPKey is an APP key from datadog.
 	DatadogAPPKey string `env:"DD_APP_KEY,required"`
 
+	// DatadogSite is a Datadog site of the organization, either a short name (US1, US3, US5, EU, gov)
+	// or a site domain like datadoghq.eu.
+	DatadogSite string `env:"DD_SITE" envDefault:"US1"`
+
+	// DatadogAPIURL is a custom base URL of Datadog API, for example a proxy in front of Datadog.
+	// If set, DatadogSite is ignored.
+	DatadogAPIURL string `env:"DD_API_URL"`
+
+	// DatadogRequestTimeout limits the time of a single request to Datadog API, zero disables the timeout.
+	DatadogRequestTimeout time.Duration `env:"DD_REQUEST_TIMEOUT" envDefault:"30s"`
+
+	// DatadogMaxDeletions limits the number of components deleted when files are removed in a single pull request.
+	// If a pull request removes more component files, nothing is deleted.
+	DatadogMaxDeletions int `env:"DATADOG_MAX_DELETIONS" envDefault:"5"`
+
+	// DatadogDeletedCheckInterval sets an interval to check if managed components still exist in datadog.
+	// Every managed component is requested once per interval, the check is disabled unless set.
+	DatadogDeletedCheckInterval time.Duration `env:"DATADOG_DELETED_CHECK_INTERVAL"`
+
+	// DatadogSelectorRefreshInterval sets an interval to resolve user config selectors again, so new components
+	// matched by selectors are adopted. The refresh is disabled unless set.
+	DatadogSelectorRefreshInterval time.Duration `env:"DATADOG_SELECTOR_REFRESH_INTERVAL"`
+
+	// DatadogDiscoveryInterval sets an interval to report managed and unmanaged components to the slack
+	// channel of every team. The report is disabled unless set.
+	DatadogDiscoveryInterval time.Duration `env:"DATADOG_DISCOVERY_INTERVAL"`
+
+	// DatadogQuietPeriod sets a period to wait for more changes of a component before a pull request is created,
+	// the changes of a user config file detected meanwhile are merged into one pull request. Unset disables the wait.
+	DatadogQuietPeriod time.Duration `env:"DATADOG_QUIET_PERIOD"`
+
+	// DatadogQuietPeriodPerTeam extends the quiet period to all the components of a team.
+	DatadogQuietPeriodPerTeam bool `env:"DATADOG_QUIET_PERIOD_PER_TEAM"`
+
 	// DatadogPollingScheduler is used to define a datadog polling scheduler.
-	// The default is simple pollster.
+	// The default is simple pollster, hash pollster compares the content hashes of the components instead.
 	DatadogPollingScheduler string `env:"DATADOG_POLLING_SCHEDULER" envDefault:"simple"`
 
+	// DatadogPollingStatePath is a path to a file which keeps the content hashes of the hash pollster.
+	DatadogPollingStatePath string `env:"DATADOG_POLLING_STATE_PATH" envDefault:"/var/lib/watchdog/hashes.json"`
+
 	// DatadogPollingInterval sets an interval for datadog to poll the dashboards/monitors.
 	// TODO: This parameter should be a part of datadog simple polling scheduler.
 	DatadogPollingInterval time.Duration `env:"DATADOG_POLLING_INTERVAL" envDefault:"20s"`
 
+	// DatadogPollingHashInterval sets an interval for the hash pollster to request the components listed in user config.
+	// Every listed component is requested once per interval, so it is longer than the polling interval.
+	DatadogPollingHashInterval time.Duration `env:"DATADOG_POLLING_HASH_INTERVAL" envDefault:"5m"`
+
 	// IgnoreKnownHosts is an option to ignore or respect the ssh known hosts when cloning repo over ssh.
 	// If set to false, the file from `SSH_KNOWN_HOSTS` env variable will be used.
 	// Default to ignore
@@ -133,6 +215,14 @@ type envVarSysConfig struct {
 	// GithubWebhookSecret a webhook can be configured with the secret.
 	GithubWebhookSecret string `env:"GITHUB_WEBHOOK_SECRET"`
 
+	// GithubReviewers maps datadog users to github logins, e.g. jane@example.com=jane. A mapped user who
+	// changed a component in datadog is requested to review the pull request.
+	GithubReviewers []string `env:"GITHUB_REVIEWERS" envSeparator:","`
+
+	// GithubUpdatePullRequests enables force-pushing a new change to the branch of an open pull request
+	// of the same components, which keeps the review comments and approvals of the pull request.
+	GithubUpdatePullRequests bool `env:"GITHUB_UPDATE_PULL_REQUESTS"`
+
 	// LoggingLevel sets a logging level for a given application.
 	LoggingLevel string `env:"LOGGING_LEVEL"`
 
@@ -165,14 +255,58 @@ func (e envVarSysConfig) GetDatadogAPPKey() string {
 	return e.DatadogAPPKey
 }
 
+func (e envVarSysConfig) GetDatadogSite() string {
+	return e.DatadogSite
+}
+
+func (e envVarSysConfig) GetDatadogAPIURL() string {
+	return e.DatadogAPIURL
+}
+
+func (e envVarSysConfig) GetDatadogRequestTimeout() time.Duration {
+	return e.DatadogRequestTimeout
+}
+
+func (e envVarSysConfig) GetDatadogMaxDeletions() int {
+	return e.DatadogMaxDeletions
+}
+
+func (e envVarSysConfig) GetDatadogDeletedCheckInterval() time.Duration {
+	return e.DatadogDeletedCheckInterval
+}
+
+func (e envVarSysConfig) GetDatadogSelectorRefreshInterval() time.Duration {
+	return e.DatadogSelectorRefreshInterval
+}
+
+func (e envVarSysConfig) GetDatadogDiscoveryInterval() time.Duration {
+	return e.DatadogDiscoveryInterval
+}
+
+func (e envVarSysConfig) GetDatadogQuietPeriod() time.Duration {
+	return e.DatadogQuietPeriod
+}
+
+func (e envVarSysConfig) GetDatadogQuietPeriodPerTeam() bool {
+	return e.DatadogQuietPeriodPerTeam
+}
+
 func (e envVarSysConfig) GetDatadogPollingScheduler() string {
 	return e.DatadogPollingScheduler
 }
 
+func (e envVarSysConfig) GetDatadogPollingStatePath() string {
+	return e.DatadogPollingStatePath
+}
+
 func (e envVarSysConfig) GetDatadogPollingInterval() time.Duration {
 	return e.DatadogPollingInterval
 }
 
+func (e envVarSysConfig) GetDatadogPollingHashInterval() time.Duration {
+	return e.DatadogPollingHashInterval
+}
+
 func (e envVarSysConfig) GetGithubDatadogDataPath() string {
 	return e.GithubDatadogDataPath
 }
@@ -201,6 +335,26 @@ func (e envVarSysConfig) GetGithubWebhookSecret() string {
 	return e.GithubWebhookSecret
 }
 
+func (e envVarSysConfig) GetGithubUpdatePullRequests() bool {
+	return e.GithubUpdatePullRequests
+}
+
+// GetGithubReviewer returns a github login mapped to a datadog user in GithubReviewers.
+func (e envVarSysConfig) GetGithubReviewer(datadogUser string) string {
+	if datadogUser == "" {
+		return ""
+	}
+
+	for _, reviewer := range e.GithubReviewers {
+		parts := strings.SplitN(reviewer, "=", 2)
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), datadogUser) {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+
+	return ""
+}
+
 func (e envVarSysConfig) GetLoggingLevel() string {
 	return e.LoggingLevel
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"sync"
@@ -17,12 +18,17 @@ import (
 
 // UserConfig defines an interface to load the user configuration.
 type UserConfig interface {
-	// Reload the user config if the config as updated
-	Reload() error
+	// Reload the user config if the config as updated. Selectors are resolved against datadog.
+	Reload(ctx context.Context) error
+
+	// RefreshSelectors resolves the selectors of user config files against datadog again and returns
+	// the component IDs which were not selected before, grouped by a user config file. The IDs newly
+	// selected by a reload since the previous refresh are returned as well.
+	RefreshSelectors(ctx context.Context) (map[*UserConfigFile]map[types.Component][]string, error)
 
 	// UserConfigFilesByComponentID takes a component, id and returns a list of user config files
 	// which contain this data.
-	UserConfigFilesByComponentID(component types.Component, id int) []*UserConfigFile
+	UserConfigFilesByComponentID(component types.Component, id string) []*UserConfigFile
 
 	// UserConfigFiles returns a slice of user config files.
 	UserConfigFiles() []*UserConfigFile
@@ -35,7 +41,13 @@ type UserConfig interface {
 }
 
 // NewUserConfigFromGit returns a new instance of a user config from a git repository.
-func NewUserConfigFromGit(ctx context.Context) (UserConfig, error) {
+// The registry defines which component types could be listed in user config files and
+// under which keys.
+func NewUserConfigFromGit(ctx context.Context, registry *types.Registry) (UserConfig, error) {
+	if registry == nil {
+		return nil, ErrNilRegistry
+	}
+
 	cfg := &fromEnvVar{}
 	err := env.Parse(cfg)
 	if err != nil {
@@ -58,46 +70,241 @@ func NewUserConfigFromGit(ctx context.Context) (UserConfig, error) {
 		readFileFn:   git.ReadFile,
 		pullMasterFn: git.PullMaster,
 
-		dashboards:   make(map[int][]*UserConfigFile),
-		monitors:     make(map[int][]*UserConfigFile),
-		screenboards: make(map[int][]*UserConfigFile),
-		downtimes:    make(map[int][]*UserConfigFile),
+		registry:   registry,
+		components: make(map[types.Component]map[string][]*UserConfigFile),
+	}
+
+	return userCfg, userCfg.Reload(ctx)
+}
+
+// NewUserConfigFromPath returns a new instance of a user config from a local checkout, e.g. a repository
+// checked out in CI. The checkout is read as it is, it is never pulled. Only USER_CONFIG_PATH is used
+// from the user config parameters.
+func NewUserConfigFromPath(ctx context.Context, registry *types.Registry, path string) (UserConfig, error) {
+	if registry == nil {
+		return nil, ErrNilRegistry
+	}
+
+	cfg := &struct {
+		BaseConfigPath string `env:"USER_CONFIG_PATH" envDefault:"/config"`
+	}{}
+	err := env.Parse(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse user config parameters from environment variables")
+	}
+
+	git, err := git.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open a local checkout %s", path)
+	}
+
+	userCfg := &userGitConfig{
+		url:          path,
+		basePath:     cfg.BaseConfigPath,
+		readDirFn:    git.ReadDir,
+		readFileFn:   git.ReadFile,
+		pullMasterFn: func() error { return nil },
+
+		registry:   registry,
+		components: make(map[types.Component]map[string][]*UserConfigFile),
 	}
 
-	return userCfg, userCfg.Reload()
+	return userCfg, userCfg.Reload(ctx)
 }
 
 // UserConfigFile represents a watchdog config file by a user.
+// Besides the meta section, a file lists component IDs under the config key of each
+// registered component type, e.g. `dashboards: [1, 2]` or `synthetics: [abc-def-ghi]`.
+// Components could also be selected by datadog tags or a title prefix under the selector key
+// of a component type, e.g. `monitorSelectors: [{tags: [team:payments]}]`.
 type UserConfigFile struct {
 	Meta MetaData
 
-	Dashboards   []int
-	Monitors     []int
-	Downtimes    []int
-	ScreenBoards []int
+	components map[types.Component][]string
+	selectors  map[types.Component][]types.Selector
+
+	// selected holds the component IDs matched by the selectors.
+	selected map[types.Component][]string
+}
+
+// NewUserConfigFile returns a new user config file with a given metadata and component IDs.
+func NewUserConfigFile(meta MetaData, components map[types.Component][]string) *UserConfigFile {
+	cfgFile := &UserConfigFile{
+		Meta:       meta,
+		components: make(map[types.Component][]string),
+	}
+
+	for component, ids := range components {
+		cfgFile.components[component] = append([]string{}, ids...)
+	}
+
+	return cfgFile
+}
+
+// Components return a mapping of a component to its IDs from a user config file, including the IDs
+// matched by selectors. Integer IDs are represented as strings, so all components could be handled the same way.
+func (u UserConfigFile) Components() map[types.Component][]string {
+	out := make(map[types.Component][]string)
+	for component, ids := range u.components {
+		out[component] = append([]string{}, ids...)
+	}
+
+	for component, ids := range u.selected {
+		listed := make(map[string]bool)
+		for _, id := range out[component] {
+			listed[id] = true
+		}
+
+		for _, id := range ids {
+			if !listed[id] {
+				out[component] = append(out[component], id)
+			}
+		}
+	}
+
+	return out
 }
 
-// Components return a mapping of a component to its IDs from a user config file.
-func (u UserConfigFile) Components() map[types.Component][]int {
-	return map[types.Component][]int{
-		types.ComponentDashboard:   u.Dashboards,
-		types.ComponentMonitor:     u.Monitors,
-		types.ComponentScreenboard: u.ScreenBoards,
-		types.ComponentDowntime:    u.Downtimes,
+// Selectors return a mapping of a component to its selectors from a user config file.
+func (u UserConfigFile) Selectors() map[types.Component][]types.Selector {
+	out := make(map[types.Component][]types.Selector)
+	for component, selectors := range u.selectors {
+		out[component] = append([]types.Selector{}, selectors...)
 	}
+
+	return out
+}
+
+// SelectorKey returns a key of selectors in a user config file for a component type config key,
+// e.g. monitorSelectors for monitors.
+func SelectorKey(configKey string) string {
+	return strings.TrimSuffix(configKey, "s") + "Selectors"
+}
+
+// componentSelectors is used to decode a list of selectors under a selector key. Like componentIDs,
+// decoding errors are kept, so unrelated keys do not fail parsing.
+type componentSelectors struct {
+	selectors []types.Selector
+	err       error
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (c *componentSelectors) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	c.err = unmarshal(&c.selectors)
+	return nil
+}
+
+// componentIDs is used to decode a list of IDs under a config key. Decoding errors are
+// kept instead of returned, because only the keys of registered component types are
+// required to hold a list of IDs.
+type componentIDs struct {
+	ids []string
+	err error
 }
 
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (c *componentIDs) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	c.err = unmarshal(&c.ids)
+	return nil
+}
+
+// ParseUserConfig parses and validates the body of a user config file. Selectors are not resolved.
+func ParseUserConfig(body []byte, registry *types.Registry) (*UserConfigFile, error) {
+	return parseUserConfigFile(body, registry)
+}
+
+func parseUserConfigFile(body []byte, registry *types.Registry) (*UserConfigFile, error) {
+	cfgFile := &UserConfigFile{}
+	if err := yaml.Unmarshal(body, cfgFile); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]componentIDs)
+	if err := yaml.Unmarshal(body, &keys); err != nil {
+		return nil, err
+	}
+
+	switch cfgFile.Meta.OnDelete {
+	case "", OnDeleteRemove, OnDeleteRecreate:
+	default:
+		return nil, errors.Errorf("invalid onDelete policy %s, expect %s or %s", cfgFile.Meta.OnDelete, OnDeleteRemove, OnDeleteRecreate)
+	}
+
+	selectorKeys := make(map[string]componentSelectors)
+	if err := yaml.Unmarshal(body, &selectorKeys); err != nil {
+		return nil, err
+	}
+
+	cfgFile.components = make(map[types.Component][]string)
+	cfgFile.selectors = make(map[types.Component][]types.Selector)
+	for _, ct := range registry.ComponentTypes() {
+		if value, ok := selectorKeys[SelectorKey(ct.ConfigKey())]; ok {
+			if value.err != nil {
+				return nil, errors.Wrapf(value.err, "invalid list of %s", SelectorKey(ct.ConfigKey()))
+			}
+
+			for _, selector := range value.selectors {
+				if selector.Empty() {
+					return nil, errors.Errorf("empty selector in %s, expect tags or titlePrefix", SelectorKey(ct.ConfigKey()))
+				}
+			}
+
+			cfgFile.selectors[ct.Type()] = value.selectors
+		}
+
+		value, ok := keys[ct.ConfigKey()]
+		if !ok {
+			continue
+		}
+
+		if value.err != nil {
+			return nil, errors.Wrapf(value.err, "invalid list of %s", ct.ConfigKey())
+		}
+
+		cfgFile.components[ct.Type()] = value.ids
+	}
+
+	return cfgFile, nil
+}
+
+const (
+	// OnDeleteRemove opens a pull request which removes a component deleted in datadog from git. It is the default policy.
+	OnDeleteRemove = "remove"
+
+	// OnDeleteRecreate recreates a component deleted in datadog from git and opens a pull request with the new ID.
+	OnDeleteRecreate = "recreate"
+)
+
 // MetaData is a field which holds a user provided metadata.
 // Team is a name of a team responsible for a config.
 // Project is an name of a project, used in component name, optional.
+// AllowDelete allows to delete components in datadog when their files are removed from git, optional.
+// OnDelete is a policy for components deleted in datadog: "remove" or "recreate", optional.
 type MetaData struct {
-	Team    string
-	Project string
-	Slack   string
+	Team        string
+	Project     string
+	Slack       string
+	AllowDelete bool   `yaml:"allowDelete"`
+	OnDelete    string `yaml:"onDelete"`
+
+	// Reviewers and TeamReviewers are github logins and team slugs requested to review the pull requests
+	// created by watchdog, Labels are applied to those pull requests.
+	Reviewers     []string
+	TeamReviewers []string `yaml:"teamReviewers"`
+	Labels        []string
 
 	FilePath string
 }
 
+// OnDeletePolicy returns a policy for components deleted in datadog.
+func (m MetaData) OnDeletePolicy() string {
+	if m.OnDelete == "" {
+		return OnDeleteRemove
+	}
+
+	return m.OnDelete
+}
+
 type fromEnvVar struct {
 	// BaseConfigPath is a base path in git repository where users store config files.
 	BaseConfigPath string `env:"USER_CONFIG_PATH" envDefault:"/config"`
@@ -123,18 +330,27 @@ type fromEnvVar struct {
 // userGitConfig is an implementation of a UserConfig interface which has
 // will retrieve the user configuration from git repo.
 type userGitConfig struct {
+	// Mutex serializes the reloads and the selector refreshes.
 	sync.Mutex
 
 	url      string
 	basePath string
 
-	dashboards   map[int][]*UserConfigFile
-	screenboards map[int][]*UserConfigFile
-	monitors     map[int][]*UserConfigFile
-	downtimes    map[int][]*UserConfigFile
+	registry *types.Registry
+
+	// state guards the user config files and the index. Both are replaced as a whole and the user config
+	// files are never modified once they are stored, so the callers could read them without a lock.
+	state sync.RWMutex
+
+	// components maps a component type to its IDs and the user config files they are listed in.
+	components map[types.Component]map[string][]*UserConfigFile
 
 	userConfigFiles []*UserConfigFile
 
+	// pending holds the component IDs newly selected by a reload by user config file path, until they are
+	// returned by the next selector refresh.
+	pending map[string]map[types.Component][]string
+
 	readFileFn   func(string) ([]byte, error)
 	readDirFn    func(string) ([]os.FileInfo, error)
 	pullMasterFn func() error
@@ -147,13 +363,12 @@ func (u *userGitConfig) UserConfigFromFile(path string, pullMaster bool) (*UserC
 			return nil, err
 		}
 	}
-	cfg := &UserConfigFile{}
 	body, err := u.readFileFn(path)
 	if err != nil {
 		return nil, err
 	}
 
-	err = yaml.Unmarshal(body, cfg)
+	cfg, err := parseUserConfigFile(body, u.registry)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to unmarshal user config %s", path)
 	}
@@ -168,6 +383,9 @@ func (u *userGitConfig) GetUserConfigBasePath() string {
 
 // UserConfigFiles returns a slice of user config files
 func (u *userGitConfig) UserConfigFiles() []*UserConfigFile {
+	u.state.RLock()
+	defer u.state.RUnlock()
+
 	return u.userConfigFiles
 }
 
@@ -181,35 +399,20 @@ func (u *userGitConfig) mapToSlice(m map[int][]*MetaData) []int {
 }
 
 // Metadata returns a list of metadata values for a given component and id.
-func (u *userGitConfig) UserConfigFilesByComponentID(component types.Component, id int) []*UserConfigFile {
-	switch component {
-	case types.ComponentDashboard:
-		return u.dashboards[id]
-	case types.ComponentMonitor:
-		return u.monitors[id]
-	case types.ComponentScreenboard:
-		return u.screenboards[id]
-	case types.ComponentDowntime:
-		return u.downtimes[id]
-	default:
-		return nil
-	}
+func (u *userGitConfig) UserConfigFilesByComponentID(component types.Component, id string) []*UserConfigFile {
+	u.state.RLock()
+	defer u.state.RUnlock()
+
+	return u.components[component][id]
 }
 
 // Reload the user config in run time.
-func (u *userGitConfig) Reload() error {
+func (u *userGitConfig) Reload(ctx context.Context) error {
 	u.Lock()
 	defer u.Unlock()
 
 	logrus.Infof("Loading a config from git repo %s", u.url)
 
-	u.dashboards = make(map[int][]*UserConfigFile)
-	u.monitors = make(map[int][]*UserConfigFile)
-	u.screenboards = make(map[int][]*UserConfigFile)
-	u.downtimes = make(map[int][]*UserConfigFile)
-
-	u.userConfigFiles = []*UserConfigFile{}
-
 	err := u.pullMasterFn()
 	if err != nil {
 		return err
@@ -220,7 +423,217 @@ func (u *userGitConfig) Reload() error {
 		return err
 	}
 
-	return u.readConfigs(files)
+	userConfigFiles, err := u.readConfigs(files)
+	if err != nil {
+		return err
+	}
+
+	// datadog may be unavailable, the selected components are resolved again on the next refresh.
+	userConfigFiles, added, err := u.resolveSelectors(ctx, userConfigFiles)
+	if err != nil {
+		logrus.Errorf("Unable to resolve user config selectors: %s", err)
+	}
+
+	// the components selected by the initial load are not new.
+	if u.userConfigFiles != nil {
+		u.addPending(added)
+	}
+
+	u.setUserConfigFiles(userConfigFiles)
+
+	return nil
+}
+
+// addPending keeps the component IDs selected by a reload which were neither listed nor selected by the
+// previous version of their user config file. Must be called before the reloaded files are stored.
+func (u *userGitConfig) addPending(added map[*UserConfigFile]map[types.Component][]string) {
+	previous := make(map[string]map[types.Component][]string)
+	for _, cfgFile := range u.userConfigFiles {
+		previous[cfgFile.Meta.FilePath] = cfgFile.Components()
+	}
+
+	for cfgFile, components := range added {
+		for component, ids := range components {
+			for _, id := range ids {
+				if containsID(previous[cfgFile.Meta.FilePath][component], id) {
+					continue
+				}
+
+				if u.pending == nil {
+					u.pending = make(map[string]map[types.Component][]string)
+				}
+				if u.pending[cfgFile.Meta.FilePath] == nil {
+					u.pending[cfgFile.Meta.FilePath] = make(map[types.Component][]string)
+				}
+				if !containsID(u.pending[cfgFile.Meta.FilePath][component], id) {
+					u.pending[cfgFile.Meta.FilePath][component] = append(u.pending[cfgFile.Meta.FilePath][component], id)
+				}
+			}
+		}
+	}
+}
+
+func containsID(ids []string, id string) bool {
+	for _, listed := range ids {
+		if listed == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RefreshSelectors resolves the selectors again and returns the newly selected component IDs.
+func (u *userGitConfig) RefreshSelectors(ctx context.Context) (map[*UserConfigFile]map[types.Component][]string, error) {
+	u.Lock()
+	defer u.Unlock()
+
+	userConfigFiles, added, err := u.resolveSelectors(ctx, u.userConfigFiles)
+	u.setUserConfigFiles(userConfigFiles)
+
+	// the components selected by a reload are returned if they are still selected.
+	for _, cfgFile := range userConfigFiles {
+		for component, ids := range u.pending[cfgFile.Meta.FilePath] {
+			selected := cfgFile.selected[component]
+			for _, id := range ids {
+				if !containsID(selected, id) || containsID(added[cfgFile][component], id) {
+					continue
+				}
+
+				if added[cfgFile] == nil {
+					added[cfgFile] = make(map[types.Component][]string)
+				}
+				added[cfgFile][component] = append(added[cfgFile][component], id)
+			}
+		}
+	}
+	u.pending = nil
+
+	return added, err
+}
+
+// resolveSelectors matches the selectors of user config files against the components listed in datadog,
+// each component type is listed once. The files with selectors are returned as copies holding the selected IDs,
+// the given files are not modified. The IDs which were not selected or listed before are returned by the copy.
+// If a component type could not be listed, the previously selected IDs are kept.
+func (u *userGitConfig) resolveSelectors(ctx context.Context, userConfigFiles []*UserConfigFile) ([]*UserConfigFile,
+	map[*UserConfigFile]map[types.Component][]string, error) {
+	var (
+		errs      []string
+		summaries = make(map[types.Component][]types.Summary)
+		failed    = make(map[types.Component]bool)
+		added     = make(map[*UserConfigFile]map[types.Component][]string)
+		resolved  = make([]*UserConfigFile, 0, len(userConfigFiles))
+	)
+
+	for _, cfgFile := range userConfigFiles {
+		if len(cfgFile.selectors) == 0 {
+			resolved = append(resolved, cfgFile)
+			continue
+		}
+
+		resolvedFile := &UserConfigFile{}
+		*resolvedFile = *cfgFile
+		resolvedFile.selected = make(map[types.Component][]string)
+		for component, ids := range cfgFile.selected {
+			resolvedFile.selected[component] = ids
+		}
+		resolved = append(resolved, resolvedFile)
+
+		for component, selectors := range cfgFile.selectors {
+			if failed[component] {
+				continue
+			}
+
+			list, ok := summaries[component]
+			if !ok {
+				var err error
+				list, err = u.listComponents(ctx, component)
+				if err != nil {
+					failed[component] = true
+					errs = append(errs, err.Error())
+					continue
+				}
+
+				summaries[component] = list
+			}
+
+			known := make(map[string]bool)
+			for _, id := range cfgFile.Components()[component] {
+				known[id] = true
+			}
+
+			var ids []string
+			for _, summary := range list {
+				for _, selector := range selectors {
+					if selector.Matches(summary) {
+						ids = append(ids, summary.ID)
+						break
+					}
+				}
+			}
+
+			for _, id := range ids {
+				if known[id] {
+					continue
+				}
+
+				if added[resolvedFile] == nil {
+					added[resolvedFile] = make(map[types.Component][]string)
+				}
+				added[resolvedFile][component] = append(added[resolvedFile][component], id)
+			}
+
+			resolvedFile.selected[component] = ids
+		}
+	}
+
+	if len(errs) > 0 {
+		return resolved, added, errors.New(strings.Join(errs, "; "))
+	}
+
+	return resolved, added, nil
+}
+
+func (u *userGitConfig) listComponents(ctx context.Context, component types.Component) ([]types.Summary, error) {
+	ct, ok := u.registry.Get(component)
+	if !ok {
+		return nil, errors.Errorf("unknown component type %s", component)
+	}
+
+	selectable, ok := ct.(types.Selectable)
+	if !ok {
+		return nil, errors.Errorf("component type %s does not support selectors", component)
+	}
+
+	list, err := selectable.List(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list %s components", component)
+	}
+
+	return list, nil
+}
+
+// setUserConfigFiles builds the index of user config files by component IDs and replaces the stored files and index.
+func (u *userGitConfig) setUserConfigFiles(userConfigFiles []*UserConfigFile) {
+	components := make(map[types.Component]map[string][]*UserConfigFile)
+	for _, cfgFile := range userConfigFiles {
+		for component, ids := range cfgFile.Components() {
+			if _, ok := components[component]; !ok {
+				components[component] = make(map[string][]*UserConfigFile)
+			}
+
+			for _, id := range ids {
+				components[component][id] = append(components[component][id], cfgFile)
+			}
+		}
+	}
+
+	u.state.Lock()
+	defer u.state.Unlock()
+
+	u.userConfigFiles = userConfigFiles
+	u.components = components
 }
 
 func (u *userGitConfig) resolveFileNames(files []os.FileInfo) string {
@@ -232,34 +645,20 @@ func (u *userGitConfig) resolveFileNames(files []os.FileInfo) string {
 	return strings.Join(tmpNames, ", ")
 }
 
-func (u *userGitConfig) readConfigs(configs []wrappedFileInfo) error {
+func (u *userGitConfig) readConfigs(configs []wrappedFileInfo) ([]*UserConfigFile, error) {
+	userConfigFiles := []*UserConfigFile{}
 	for _, config := range configs {
 		userConfigFile, err := u.UserConfigFromFile(config.path, false)
 		if err != nil {
-			return errors.Wrapf(err, "unable to read user config: %s", config.path)
+			return nil, errors.Wrapf(err, "unable to read user config: %s", config.path)
 		}
 
 		userConfigFile.Meta.FilePath = config.path
 
-		u.updateConfig(userConfigFile)
+		userConfigFiles = append(userConfigFiles, userConfigFile)
 	}
 
-	return nil
-}
-
-func (u *userGitConfig) updateConfig(cfgFile *UserConfigFile) {
-	u.updateComponent(cfgFile.Dashboards, cfgFile, u.dashboards)
-	u.updateComponent(cfgFile.Monitors, cfgFile, u.monitors)
-	u.updateComponent(cfgFile.ScreenBoards, cfgFile, u.screenboards)
-	u.updateComponent(cfgFile.Downtimes, cfgFile, u.downtimes)
-
-	u.userConfigFiles = append(u.userConfigFiles, cfgFile)
-}
-
-func (u *userGitConfig) updateComponent(ids []int, userCfg *UserConfigFile, component map[int][]*UserConfigFile) {
-	for _, id := range ids {
-		component[id] = append(component[id], userCfg)
-	}
+	return userConfigFiles, nil
 }
 
 type wrappedFileInfo struct {
@@ -295,3 +694,242 @@ func (u *userGitConfig) findConfigs(path string) ([]wrappedFileInfo, error) {
 
 	return configFiles, nil
 }
+
+// AddComponentIDs adds component IDs to a list under a config key of a user config file body.
+// The body is edited as text, so comments and formatting of the rest of the file are preserved.
+// A block list keeps the indentation of its items, a flow list is extended in place and a missing
+// key is appended to the end of the file. IDs which are already listed are skipped.
+func AddComponentIDs(body []byte, configKey string, ids ...string) ([]byte, error) {
+	existing, err := listedIDs(body, configKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var newIDs []string
+	for _, id := range ids {
+		if !existing[id] {
+			existing[id] = true
+			newIDs = append(newIDs, id)
+		}
+	}
+
+	if len(newIDs) == 0 {
+		return body, nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+	if len(body) == 0 {
+		lines = nil
+	}
+
+	keyLine := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, configKey+":") {
+			keyLine = i
+			break
+		}
+	}
+
+	var out []string
+	switch {
+	case keyLine == -1:
+		out = append(lines, configKey+":")
+		out = append(out, blockItems("  ", newIDs)...)
+	default:
+		value := strings.TrimSpace(stripYAMLComment(lines[keyLine][len(configKey)+1:]))
+		switch {
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") {
+				return nil, errors.Errorf("unable to edit a multiline flow list of %s", configKey)
+			}
+
+			items := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+			if items != "" {
+				items += ", "
+			}
+			items += strings.Join(newIDs, ", ")
+
+			out = append(out, lines[:keyLine]...)
+			out = append(out, fmt.Sprintf("%s: [%s]", configKey, items))
+			out = append(out, lines[keyLine+1:]...)
+		case value == "" || value == "~" || value == "null":
+			// find the end of the block list and the indentation of its items.
+			indent, last := "  ", keyLine
+			for i := keyLine + 1; i < len(lines); i++ {
+				trimmed := strings.TrimSpace(lines[i])
+				if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+					continue
+				}
+
+				if !strings.HasPrefix(lines[i], " ") && !strings.HasPrefix(lines[i], "-") {
+					break
+				}
+
+				if strings.HasPrefix(trimmed, "-") {
+					indent = lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " "))]
+				}
+				last = i
+			}
+
+			out = append(out, lines[:keyLine]...)
+			out = append(out, configKey+":")
+			out = append(out, lines[keyLine+1:last+1]...)
+			out = append(out, blockItems(indent, newIDs)...)
+			out = append(out, lines[last+1:]...)
+		default:
+			return nil, errors.Errorf("invalid list of %s", configKey)
+		}
+	}
+
+	result := []byte(strings.Join(out, "\n") + "\n")
+
+	// make sure the edited body lists all the IDs.
+	updated, err := listedIDs(result, configKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse edited user config")
+	}
+
+	for _, id := range ids {
+		if !updated[id] {
+			return nil, errors.Errorf("unable to add id %s to %s", id, configKey)
+		}
+	}
+
+	return result, nil
+}
+
+func listedIDs(body []byte, configKey string) (map[string]bool, error) {
+	keys := make(map[string]componentIDs)
+	if err := yaml.Unmarshal(body, &keys); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal user config")
+	}
+
+	value := keys[configKey]
+	if value.err != nil {
+		return nil, errors.Wrapf(value.err, "invalid list of %s", configKey)
+	}
+
+	ids := make(map[string]bool)
+	for _, id := range value.ids {
+		ids[id] = true
+	}
+
+	return ids, nil
+}
+
+func blockItems(indent string, ids []string) []string {
+	var items []string
+	for _, id := range ids {
+		items = append(items, fmt.Sprintf("%s- %s", indent, id))
+	}
+
+	return items
+}
+
+func stripYAMLComment(s string) string {
+	if i := strings.Index(s, " #"); i >= 0 {
+		return s[:i]
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(s), "#") {
+		return ""
+	}
+
+	return s
+}
+
+// RemoveComponentIDs removes component IDs from a list under a config key of a user config file body.
+// Like AddComponentIDs, the body is edited as text. A list which becomes empty is kept as an empty flow list.
+func RemoveComponentIDs(body []byte, configKey string, ids ...string) ([]byte, error) {
+	remove := make(map[string]bool)
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	lines := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+
+	keyLine := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, configKey+":") {
+			keyLine = i
+			break
+		}
+	}
+
+	if keyLine == -1 {
+		return body, nil
+	}
+
+	var out []string
+	value := strings.TrimSpace(stripYAMLComment(lines[keyLine][len(configKey)+1:]))
+	switch {
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, errors.Errorf("unable to edit a multiline flow list of %s", configKey)
+		}
+
+		var items []string
+		for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+			item = strings.TrimSpace(item)
+			if item != "" && !remove[unquoteYAML(item)] {
+				items = append(items, item)
+			}
+		}
+
+		out = append(out, lines[:keyLine]...)
+		out = append(out, fmt.Sprintf("%s: [%s]", configKey, strings.Join(items, ", ")))
+		out = append(out, lines[keyLine+1:]...)
+	case value == "" || value == "~" || value == "null":
+		out = append(out, lines[:keyLine+1]...)
+
+		var left int
+		i := keyLine + 1
+		for ; i < len(lines); i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(lines[i], " ") && !strings.HasPrefix(lines[i], "-") {
+				break
+			}
+
+			if strings.HasPrefix(trimmed, "-") {
+				if remove[unquoteYAML(strings.TrimSpace(stripYAMLComment(strings.TrimPrefix(trimmed, "-"))))] {
+					continue
+				}
+				left++
+			}
+
+			out = append(out, lines[i])
+		}
+
+		if left == 0 {
+			out[keyLine] = configKey + ": []"
+		}
+
+		out = append(out, lines[i:]...)
+	default:
+		return nil, errors.Errorf("invalid list of %s", configKey)
+	}
+
+	result := []byte(strings.Join(out, "\n") + "\n")
+
+	// make sure the edited body does not list the IDs anymore.
+	updated, err := listedIDs(result, configKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse edited user config")
+	}
+
+	for _, id := range ids {
+		if updated[id] {
+			return nil, errors.Errorf("unable to remove id %s from %s", id, configKey)
+		}
+	}
+
+	return result, nil
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
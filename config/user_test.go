@@ -1,16 +1,21 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/coinbase/watchdog/primitives/datadog/types"
 )
 
 func TestUserConfig(t *testing.T) {
 	userCfg := &userGitConfig{
-		dashboards:   make(map[int][]*UserConfigFile),
-		monitors:     make(map[int][]*UserConfigFile),
-		screenboards: make(map[int][]*UserConfigFile),
-		downtimes:    make(map[int][]*UserConfigFile),
+		registry: newTestRegistry(t),
 
 		pullMasterFn: func() error { return nil },
 		readDirFn:    ioutil.ReadDir,
@@ -19,66 +24,520 @@ func TestUserConfig(t *testing.T) {
 		basePath: "./fixtures/configs",
 	}
 
-	err := userCfg.Reload()
+	err := userCfg.Reload(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if len(userCfg.dashboards) != 6 {
-		t.Fatalf("expect 6 dashboards. Got %d", len(userCfg.dashboards))
+	if len(userCfg.components[types.ComponentDashboard]) != 6 {
+		t.Fatalf("expect 6 dashboards. Got %d", len(userCfg.components[types.ComponentDashboard]))
 	}
 
-	for _, expectedID := range []int{1, 2, 955878, 917832, 10, 20} {
-		if _, ok := userCfg.dashboards[expectedID]; !ok {
-			t.Fatalf("expect dashboard id %d", expectedID)
+	for _, expectedID := range []string{"1", "2", "955878", "917832", "10", "20"} {
+		if _, ok := userCfg.components[types.ComponentDashboard][expectedID]; !ok {
+			t.Fatalf("expect dashboard id %s", expectedID)
 		}
 	}
 
-	if len(userCfg.monitors) != 6 {
-		t.Fatalf("expect 6 monitors. Got %d", len(userCfg.monitors))
+	if len(userCfg.components[types.ComponentMonitor]) != 6 {
+		t.Fatalf("expect 6 monitors. Got %d", len(userCfg.components[types.ComponentMonitor]))
 	}
 
-	for _, expectedID := range []int{3, 4, 6065878, 4891392, 30, 40} {
-		if _, ok := userCfg.monitors[expectedID]; !ok {
-			t.Fatalf("expect monitor id %d", expectedID)
+	for _, expectedID := range []string{"3", "4", "6065878", "4891392", "30", "40"} {
+		if _, ok := userCfg.components[types.ComponentMonitor][expectedID]; !ok {
+			t.Fatalf("expect monitor id %s", expectedID)
 		}
 	}
 
-	if len(userCfg.screenboards) != 2 {
-		t.Fatalf("expect 2 screenboards. Got %d", len(userCfg.screenboards))
+	if len(userCfg.components[types.ComponentScreenboard]) != 2 {
+		t.Fatalf("expect 2 screenboards. Got %d", len(userCfg.components[types.ComponentScreenboard]))
 	}
 
-	for _, expectedID := range []int{42, 43} {
-		if _, ok := userCfg.screenboards[expectedID]; !ok {
-			t.Fatalf("expect screenboard id %d", expectedID)
+	for _, expectedID := range []string{"42", "43"} {
+		if _, ok := userCfg.components[types.ComponentScreenboard][expectedID]; !ok {
+			t.Fatalf("expect screenboard id %s", expectedID)
 		}
 	}
 
-	if len(userCfg.downtimes) != 2 {
-		t.Fatalf("expect 2 downtimes. Got %d", len(userCfg.downtimes))
+	if len(userCfg.components[types.ComponentDowntime]) != 2 {
+		t.Fatalf("expect 2 downtimes. Got %d", len(userCfg.components[types.ComponentDowntime]))
 	}
 
-	for _, expectedID := range []int{55, 66} {
-		if _, ok := userCfg.downtimes[expectedID]; !ok {
-			t.Fatalf("expect downtime id %d", expectedID)
+	for _, expectedID := range []string{"55", "66"} {
+		if _, ok := userCfg.components[types.ComponentDowntime][expectedID]; !ok {
+			t.Fatalf("expect downtime id %s", expectedID)
 		}
 	}
 
+	if len(userCfg.components[types.ComponentUnifiedDashboard]) != 2 {
+		t.Fatalf("expect 2 unified dashboards. Got %d", len(userCfg.components[types.ComponentUnifiedDashboard]))
+	}
+
+	for _, expectedID := range []string{"abc-def-ghi", "jkl-mno-pqr"} {
+		if files := userCfg.UserConfigFilesByComponentID(types.ComponentUnifiedDashboard, expectedID); len(files) != 1 {
+			t.Fatalf("expect unified dashboard id %s in one config file. Got %d", expectedID, len(files))
+		}
+	}
+
+	if len(userCfg.components[types.ComponentSynthetics]) != 1 {
+		t.Fatalf("expect 1 synthetic test. Got %d", len(userCfg.components[types.ComponentSynthetics]))
+	}
+
+	if _, ok := userCfg.components[types.ComponentSynthetics]["stu-vwx-yz1"]; !ok {
+		t.Fatal("expect synthetic test id stu-vwx-yz1")
+	}
+
+	if len(userCfg.components[types.ComponentSLO]) != 2 {
+		t.Fatalf("expect 2 slos. Got %d", len(userCfg.components[types.ComponentSLO]))
+	}
+
+	for _, expectedID := range []string{"12341234123412341234123412341234", "56785678567856785678567856785678"} {
+		if _, ok := userCfg.components[types.ComponentSLO][expectedID]; !ok {
+			t.Fatalf("expect slo id %s", expectedID)
+		}
+	}
+
+	if len(userCfg.components[types.ComponentLogPipeline]) != 1 {
+		t.Fatalf("expect 1 log pipeline. Got %d", len(userCfg.components[types.ComponentLogPipeline]))
+	}
+
+	if _, ok := userCfg.components[types.ComponentLogPipeline]["Xyz1aBc2DeF3"]; !ok {
+		t.Fatal("expect log pipeline id Xyz1aBc2DeF3")
+	}
+
 	// test reload, it should clear the 6 dashboards and load just 2
 	userCfg.basePath = "./fixtures/configs/a/1/"
-	err = userCfg.Reload()
+	err = userCfg.Reload(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	if len(userCfg.dashboards) != 2 {
-		t.Fatalf("expect 2 dashboards. Got %d", len(userCfg.dashboards))
+	if len(userCfg.components[types.ComponentDashboard]) != 2 {
+		t.Fatalf("expect 2 dashboards. Got %d", len(userCfg.components[types.ComponentDashboard]))
 	}
 
-	for _, expectedID := range []int{1, 2} {
-		if _, ok := userCfg.dashboards[expectedID]; !ok {
-			t.Fatalf("expect dashboard id %d", expectedID)
+	for _, expectedID := range []string{"1", "2"} {
+		if _, ok := userCfg.components[types.ComponentDashboard][expectedID]; !ok {
+			t.Fatalf("expect dashboard id %s", expectedID)
 		}
 	}
 
 }
+
+func TestUserConfigInvalidIDs(t *testing.T) {
+	_, err := parseUserConfigFile([]byte("dashboards:\n  foo: bar\n"), newTestRegistry(t))
+	if err == nil {
+		t.Fatal("expect an error for a map of dashboards")
+	}
+
+	// keys of unknown component types are ignored
+	cfgFile, err := parseUserConfigFile([]byte("meta:\n  team: foo\nnotebooks:\n  foo: bar\ndashboards: [1]\n"), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfgFile.Meta.Team != "foo" {
+		t.Fatalf("expect team foo. Got %s", cfgFile.Meta.Team)
+	}
+
+	ids := cfgFile.Components()[types.ComponentDashboard]
+	if len(ids) != 1 || ids[0] != "1" {
+		t.Fatalf("expect dashboard ids [1]. Got %v", ids)
+	}
+}
+
+func TestUserConfigAllowDelete(t *testing.T) {
+	cfgFile, err := parseUserConfigFile([]byte("meta:\n  team: foo\n  allowDelete: true\n"), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cfgFile.Meta.AllowDelete {
+		t.Fatal("expect allowDelete to be set")
+	}
+
+	cfgFile, err = parseUserConfigFile([]byte("meta:\n  team: foo\n"), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfgFile.Meta.AllowDelete {
+		t.Fatal("expect allowDelete to be off by default")
+	}
+}
+
+func TestUserConfigAssignees(t *testing.T) {
+	body := "meta:\n  team: foo\n  reviewers: [jane, john]\n  teamReviewers: [sre]\n  labels: [datadog]\n"
+	cfgFile, err := parseUserConfigFile([]byte(body), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(cfgFile.Meta.Reviewers, ",") != "jane,john" {
+		t.Fatalf("expect reviewers [jane john]. Got %v", cfgFile.Meta.Reviewers)
+	}
+
+	if strings.Join(cfgFile.Meta.TeamReviewers, ",") != "sre" {
+		t.Fatalf("expect team reviewers [sre]. Got %v", cfgFile.Meta.TeamReviewers)
+	}
+
+	if strings.Join(cfgFile.Meta.Labels, ",") != "datadog" {
+		t.Fatalf("expect labels [datadog]. Got %v", cfgFile.Meta.Labels)
+	}
+}
+
+func TestAddComponentIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		ids      []string
+		expected string
+	}{
+		{
+			name:     "block list",
+			body:     "meta:\n  team: foo\ndashboards:\n    - 1\n    - 2 # main\nmonitors: [3]\n",
+			ids:      []string{"4"},
+			expected: "meta:\n  team: foo\ndashboards:\n    - 1\n    - 2 # main\n    - 4\nmonitors: [3]\n",
+		},
+		{
+			name:     "flow list",
+			body:     "meta:\n  team: foo\ndashboards: [1, 2] # main\n",
+			ids:      []string{"3", "4"},
+			expected: "meta:\n  team: foo\ndashboards: [1, 2, 3, 4]\n",
+		},
+		{
+			name:     "empty flow list",
+			body:     "dashboards: []\n",
+			ids:      []string{"3"},
+			expected: "dashboards: [3]\n",
+		},
+		{
+			name:     "missing key",
+			body:     "meta:\n  team: foo\n",
+			ids:      []string{"abc-def-ghi"},
+			expected: "meta:\n  team: foo\ndashboards:\n  - abc-def-ghi\n",
+		},
+		{
+			name:     "empty key",
+			body:     "dashboards:\nmeta:\n  team: foo\n",
+			ids:      []string{"1"},
+			expected: "dashboards:\n  - 1\nmeta:\n  team: foo\n",
+		},
+		{
+			name:     "existing id",
+			body:     "dashboards: [1]\n",
+			ids:      []string{"1"},
+			expected: "dashboards: [1]\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := AddComponentIDs([]byte(tc.body), "dashboards", tc.ids...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != tc.expected {
+				t.Fatalf("expect body %q. Got %q", tc.expected, body)
+			}
+		})
+	}
+
+	if _, err := AddComponentIDs([]byte("dashboards: foo\n"), "dashboards", "1"); err == nil {
+		t.Fatal("expect error for an invalid list")
+	}
+}
+
+func TestRemoveComponentIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		ids      []string
+		expected string
+	}{
+		{
+			name:     "block list",
+			body:     "dashboards:\n  # main\n  - 1\n  - 2 # old\n  - \"3\"\nmonitors: [3]\n",
+			ids:      []string{"2", "3"},
+			expected: "dashboards:\n  # main\n  - 1\nmonitors: [3]\n",
+		},
+		{
+			name:     "last item of a block list",
+			body:     "dashboards:\n  - 1\nmonitors: [3]\n",
+			ids:      []string{"1"},
+			expected: "dashboards: []\nmonitors: [3]\n",
+		},
+		{
+			name:     "flow list",
+			body:     "dashboards: [1, 2, 3]\n",
+			ids:      []string{"2"},
+			expected: "dashboards: [1, 3]\n",
+		},
+		{
+			name:     "missing key",
+			body:     "monitors: [1]\n",
+			ids:      []string{"1"},
+			expected: "monitors: [1]\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := RemoveComponentIDs([]byte(tc.body), "dashboards", tc.ids...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != tc.expected {
+				t.Fatalf("expect body %q. Got %q", tc.expected, body)
+			}
+		})
+	}
+}
+
+func TestUserConfigOnDelete(t *testing.T) {
+	cfgFile, err := parseUserConfigFile([]byte("meta:\n  team: foo\n"), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy := cfgFile.Meta.OnDeletePolicy(); policy != OnDeleteRemove {
+		t.Fatalf("expect default policy %s. Got %s", OnDeleteRemove, policy)
+	}
+
+	cfgFile, err = parseUserConfigFile([]byte("meta:\n  team: foo\n  onDelete: recreate\n"), newTestRegistry(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy := cfgFile.Meta.OnDeletePolicy(); policy != OnDeleteRecreate {
+		t.Fatalf("expect policy %s. Got %s", OnDeleteRecreate, policy)
+	}
+
+	if _, err := parseUserConfigFile([]byte("meta:\n  onDelete: ignore\n"), newTestRegistry(t)); err == nil {
+		t.Fatal("expect an error for an unknown policy")
+	}
+}
+
+func TestUserConfigSelectors(t *testing.T) {
+	monitors := []types.Summary{
+		{ID: "1", Title: "cpu", Tags: []string{"team:payments"}},
+		{ID: "2", Title: "disk", Tags: []string{"team:payments", "env:prod"}},
+		{ID: "3", Title: "memory", Tags: []string{"team:infra"}},
+	}
+
+	registry, err := types.NewRegistry(
+		fakeComponentType{types.ComponentDashboard, "dashboards"},
+		selectableComponentType{fakeComponentType{types.ComponentMonitor, "monitors"}, func() []types.Summary { return monitors }},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfgFile, err := parseUserConfigFile([]byte("meta:\n  team: payments\nmonitors: [1]\nmonitorSelectors:\n  - tags: [team:payments]\n"), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if selectors := cfgFile.Selectors()[types.ComponentMonitor]; len(selectors) != 1 || selectors[0].Tags[0] != "team:payments" {
+		t.Fatalf("expect a monitor selector by team:payments tag. Got %+v", selectors)
+	}
+
+	userCfg := &userGitConfig{
+		registry:        registry,
+		userConfigFiles: []*UserConfigFile{cfgFile},
+	}
+
+	added, err := userCfg.RefreshSelectors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed := userCfg.UserConfigFiles()[0]
+	if ids := added[refreshed][types.ComponentMonitor]; len(ids) != 1 || ids[0] != "2" {
+		t.Fatalf("expect monitor 2 to be newly selected. Got %v", ids)
+	}
+
+	if ids := refreshed.Components()[types.ComponentMonitor]; len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("expect monitors 1 and 2. Got %v", ids)
+	}
+
+	if ids := cfgFile.Components()[types.ComponentMonitor]; len(ids) != 1 || ids[0] != "1" {
+		t.Fatalf("expect the parsed user config file not to be modified. Got %v", ids)
+	}
+
+	if files := userCfg.UserConfigFilesByComponentID(types.ComponentMonitor, "2"); len(files) != 1 || files[0] != refreshed {
+		t.Fatalf("expect monitor 2 in the index. Got %d files", len(files))
+	}
+
+	monitors = append(monitors, types.Summary{ID: "4", Title: "latency", Tags: []string{"team:payments"}})
+	added, err = userCfg.RefreshSelectors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := added[userCfg.UserConfigFiles()[0]][types.ComponentMonitor]; len(ids) != 1 || ids[0] != "4" {
+		t.Fatalf("expect only monitor 4 to be newly selected. Got %v", ids)
+	}
+
+	if _, err := parseUserConfigFile([]byte("monitorSelectors:\n  - {}\n"), registry); err == nil {
+		t.Fatal("expect an error for an empty selector")
+	}
+}
+
+func TestUserConfigRefreshSelectorsConcurrently(t *testing.T) {
+	registry, err := types.NewRegistry(
+		selectableComponentType{fakeComponentType{types.ComponentMonitor, "monitors"}, func() []types.Summary {
+			return []types.Summary{{ID: "1", Tags: []string{"team:payments"}}, {ID: "2", Tags: []string{"team:payments"}}}
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfgFile, err := parseUserConfigFile([]byte("meta:\n  team: payments\nmonitorSelectors:\n  - tags: [team:payments]\n"), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userCfg := &userGitConfig{
+		registry:        registry,
+		userConfigFiles: []*UserConfigFile{cfgFile},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if _, err := userCfg.RefreshSelectors(context.Background()); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			if ids := userCfg.UserConfigFiles()[0].Components()[types.ComponentMonitor]; len(ids) != 2 {
+				t.Fatalf("expect monitors 1 and 2. Got %v", ids)
+			}
+			return
+		default:
+		}
+
+		for _, file := range userCfg.UserConfigFiles() {
+			file.Components()
+		}
+
+		for _, file := range userCfg.UserConfigFilesByComponentID(types.ComponentMonitor, "1") {
+			file.Components()
+		}
+	}
+}
+
+func TestUserConfigReloadSelectors(t *testing.T) {
+	monitors := []types.Summary{{ID: "1", Tags: []string{"team:payments"}}}
+	registry, err := types.NewRegistry(
+		selectableComponentType{fakeComponentType{types.ComponentMonitor, "monitors"}, func() []types.Summary { return monitors }},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body := []byte("meta:\n  team: payments\nmonitorSelectors:\n  - tags: [team:payments]\n")
+	if err := ioutil.WriteFile(filepath.Join(dir, "payments.yml"), body, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	userCfg := &userGitConfig{
+		registry: registry,
+
+		pullMasterFn: func() error { return nil },
+		readDirFn:    ioutil.ReadDir,
+		readFileFn:   ioutil.ReadFile,
+
+		basePath: dir,
+	}
+
+	if err := userCfg.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := userCfg.RefreshSelectors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(added) != 0 {
+		t.Fatalf("expect no newly selected monitors after the initial load. Got %v", added)
+	}
+
+	monitors = append(monitors, types.Summary{ID: "2", Tags: []string{"team:payments"}})
+	if err := userCfg.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err = userCfg.RefreshSelectors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := added[userCfg.UserConfigFiles()[0]][types.ComponentMonitor]; len(ids) != 1 || ids[0] != "2" {
+		t.Fatalf("expect monitor 2 selected by the reload. Got %v", ids)
+	}
+
+	added, err = userCfg.RefreshSelectors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(added) != 0 {
+		t.Fatalf("expect monitor 2 to be reported once. Got %v", added)
+	}
+}
+
+type fakeComponentType struct {
+	component types.Component
+	configKey string
+}
+
+func (f fakeComponentType) Type() types.Component { return f.component }
+func (f fakeComponentType) ConfigKey() string     { return f.configKey }
+func (f fakeComponentType) Get(ctx context.Context, id string) (json.RawMessage, error) {
+	return nil, nil
+}
+func (f fakeComponentType) ModifiedIDs(context.Context, time.Duration) ([]string, error) {
+	return nil, nil
+}
+func (f fakeComponentType) Update(ctx context.Context, payload json.RawMessage) error { return nil }
+
+func newTestRegistry(t *testing.T) *types.Registry {
+	registry, err := types.NewRegistry(
+		fakeComponentType{types.ComponentDashboard, "dashboards"},
+		fakeComponentType{types.ComponentUnifiedDashboard, "unifiedDashboards"},
+		fakeComponentType{types.ComponentMonitor, "monitors"},
+		fakeComponentType{types.ComponentScreenboard, "screenboards"},
+		fakeComponentType{types.ComponentDowntime, "downtimes"},
+		fakeComponentType{types.ComponentSynthetics, "synthetics"},
+		fakeComponentType{types.ComponentSLO, "slos"},
+		fakeComponentType{types.ComponentLogPipeline, "logPipelines"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return registry
+}
+
+type selectableComponentType struct {
+	fakeComponentType
+	list func() []types.Summary
+}
+
+func (s selectableComponentType) List(ctx context.Context) ([]types.Summary, error) {
+	return s.list(), nil
+}
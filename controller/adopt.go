@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// AdoptOptions describes the existing datadog components a team takes ownership of. Components are listed
+// by ID or selected by tags and a title prefix, like selectors in user config.
+type AdoptOptions struct {
+	Team    string
+	Project string
+	Slack   string
+
+	Components  map[types.Component][]string
+	Tags        []string
+	TitlePrefix string
+
+	// DryRun returns the planned files without opening a pull request.
+	DryRun bool
+}
+
+// AdoptPlan is a result of an adoption, the user config file and the component files it adds.
+type AdoptPlan struct {
+	ConfigFile string
+	Config     []byte
+
+	// Files is a mapping of a component file path to its content.
+	Files map[string][]byte
+
+	// Skipped are the components which are already managed by a user config file.
+	Skipped []string
+
+	// PullRequest is a number of the onboarding pull request, zero for a dry run.
+	PullRequest int
+}
+
+// Adopt writes the selected datadog components to their component paths, adds their IDs to the team user config,
+// which is created if it does not exist, and opens a single onboarding pull request. Components already managed
+// by a user config file are skipped.
+func (c *Controller) Adopt(ctx context.Context, opts AdoptOptions) (*AdoptPlan, error) {
+	if opts.Team == "" {
+		return nil, errors.New("empty team")
+	}
+
+	components, err := c.adoptedComponents(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	err = c.git.PullMaster()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to pull git master")
+	}
+
+	plan := &AdoptPlan{
+		ConfigFile: c.adoptConfigFile(opts.Team, opts.Project),
+		Files:      make(map[string][]byte),
+	}
+
+	body, err := c.git.ReadFile(plan.ConfigFile)
+	switch {
+	case os.IsNotExist(errors.Cause(err)):
+		body, err = newUserConfigBody(opts)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, errors.Wrapf(err, "unable to read user config %s", plan.ConfigFile)
+	}
+
+	for _, ct := range c.datadog.Registry.ComponentTypes() {
+		var ids []string
+		for _, id := range components[ct.Type()] {
+			if managed := c.cfg.UserConfigFilesByComponentID(ct.Type(), id); len(managed) > 0 {
+				plan.Skipped = append(plan.Skipped, fmt.Sprintf("%s %s is managed by %s", ct.Type(), id, managed[0].Meta.FilePath))
+				continue
+			}
+
+			buf := new(bytes.Buffer)
+			err := c.datadog.Write(ctx, ct.Type(), id, buf)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to get %s %s", ct.Type(), id)
+			}
+
+			plan.Files[c.cfg.ComponentPath(ct.Type(), opts.Team, opts.Project, id)] = buf.Bytes()
+			ids = append(ids, id)
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		body, err = config.AddComponentIDs(body, ct.ConfigKey(), ids...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to add %s ids to %s", ct.Type(), plan.ConfigFile)
+		}
+	}
+	plan.Config = body
+
+	if len(plan.Files) == 0 {
+		return nil, errors.Errorf("no components to adopt for team %s", opts.Team)
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	branch := fmt.Sprintf("refs/heads/%s/%d", opts.Team, time.Now().UnixNano())
+	err = c.git.CreateBranch(branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create branch %s", branch)
+	}
+	defer func() {
+		err = c.git.RemoveBranch(branch)
+		if err != nil {
+			logrus.Errorf("Error removing local branch %s: %s", branch, err)
+		}
+	}()
+
+	err = c.git.Checkout(branch, false, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to checkout to branch %s", branch)
+	}
+
+	files := make(map[string][]byte)
+	for file, body := range plan.Files {
+		files[file] = body
+	}
+	files[plan.ConfigFile] = plan.Config
+
+	for file, body := range files {
+		err = c.git.NewFile(file, body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to create a new file %s on git workspace", file)
+		}
+
+		err = c.git.Add(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to add a file %s to a commit", file)
+		}
+	}
+
+	msg, commitHash, err := c.git.Commit(fmt.Sprintf("Onboard datadog components owned by %s", opts.Team))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to make a new commit")
+	}
+
+	logrus.Debugf("A new commit created %s\n%s", commitHash, msg)
+
+	err = c.git.Push(branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to push changes to remote branch %s", branch)
+	}
+
+	title := fmt.Sprintf("[Automated PR] Onboard datadog components owned by [%s] - %s", opts.Team, plan.ConfigFile)
+	description := fmt.Sprintf("Existing datadog components are adopted by team %s:\n\n", opts.Team)
+	for _, file := range plan.FilePaths() {
+		description += fmt.Sprintf("- `%s`\n", file)
+	}
+	description += fmt.Sprintf("\nMerge this PR to manage them with `%s`.", plan.ConfigFile)
+	if bodyExtra := c.cfg.PullRequestBodyExtra(); bodyExtra != "" {
+		description += "\n\n" + bodyExtra
+	}
+
+	plan.PullRequest, err = c.createNewPullRequest(ctx, title, branch, "master", description)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create a new pull request")
+	}
+
+	return plan, nil
+}
+
+// FilePaths returns the sorted paths of the component files.
+func (p *AdoptPlan) FilePaths() []string {
+	var files []string
+	for file := range p.Files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// adoptedComponents returns the listed component IDs and the IDs of components matching the tags and the title prefix.
+func (c *Controller) adoptedComponents(ctx context.Context, opts AdoptOptions) (map[types.Component][]string, error) {
+	components := make(map[types.Component][]string)
+	listed := make(map[types.Component]map[string]bool)
+	add := func(component types.Component, id string) {
+		if listed[component] == nil {
+			listed[component] = make(map[string]bool)
+		}
+
+		if !listed[component][id] {
+			listed[component][id] = true
+			components[component] = append(components[component], id)
+		}
+	}
+
+	for component, ids := range opts.Components {
+		if _, ok := c.datadog.Registry.Get(component); !ok {
+			return nil, errors.Errorf("unknown component type %s", component)
+		}
+
+		for _, id := range ids {
+			add(component, id)
+		}
+	}
+
+	selector := types.Selector{Tags: opts.Tags, TitlePrefix: opts.TitlePrefix}
+	if selector.Empty() {
+		return components, nil
+	}
+
+	for _, ct := range c.datadog.Registry.ComponentTypes() {
+		selectable, ok := ct.(types.Selectable)
+		if !ok {
+			continue
+		}
+
+		summaries, err := selectable.List(ctx)
+		if err == datadog.ErrSelectNotSupported {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list %s", ct.Type())
+		}
+
+		for _, summary := range summaries {
+			if selector.Matches(summary) {
+				add(ct.Type(), summary.ID)
+			}
+		}
+	}
+
+	return components, nil
+}
+
+// adoptConfigFile returns a path to the user config file of a team, relative to the repo root.
+func (c *Controller) adoptConfigFile(team, project string) string {
+	dir := strings.Trim(c.cfg.GetUserConfigBasePath(), "/") + "/" + team
+	if project != "" {
+		dir += "/" + project
+	}
+
+	return strings.TrimLeft(dir+"/components.yaml", "/")
+}
+
+// newUserConfigBody returns a user config with the meta section only, component IDs are added with config.AddComponentIDs.
+func newUserConfigBody(opts AdoptOptions) ([]byte, error) {
+	meta := map[string]string{"team": opts.Team}
+	if opts.Project != "" {
+		meta["project"] = opts.Project
+	}
+
+	if opts.Slack != "" {
+		meta["slack"] = opts.Slack
+	}
+
+	body, err := yaml.Marshal(map[string]map[string]string{"meta": meta})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal user config meta")
+	}
+
+	return body, nil
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestAdopt(t *testing.T) {
+	get := func(ctx context.Context, id string) (json.RawMessage, error) {
+		return []byte(`{"id":` + id + `}`), nil
+	}
+
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentDashboard, "dashboards", get, nil, nil),
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", get, nil, nil,
+			datadog.WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				return []types.Summary{
+					{ID: "1", Tags: []string{"team:payments"}},
+					{ID: "2", Tags: []string{"team:payments"}},
+					{ID: "3", Tags: []string{"team:other"}},
+				}, nil
+			})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", nil, datadog.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// monitor 2 is managed by another config file
+	managed := config.NewUserConfigFile(config.MetaData{Team: "other", FilePath: "/config/other.yml"},
+		map[types.Component][]string{types.ComponentMonitor: {"2"}})
+
+	git := &memGitClient{files: map[string]string{}}
+	gh := &recordingGithubClient{}
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{managed}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog:             ddog,
+		git:                 git,
+		github:              gh,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	opts := AdoptOptions{
+		Team:       "payments",
+		Slack:      "#payments",
+		Components: map[types.Component][]string{types.ComponentDashboard: {"10"}},
+		Tags:       []string{"team:payments"},
+		DryRun:     true,
+	}
+
+	plan, err := c.Adopt(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := plan.FilePaths()
+	if len(files) != 2 || files[0] != "data/payments/dashboard-10.json" || files[1] != "data/payments/monitor-1.json" {
+		t.Fatalf("expect dashboard 10 and monitor 1 files. Got %v", files)
+	}
+
+	if len(plan.Skipped) != 1 {
+		t.Fatalf("expect managed monitor 2 to be skipped. Got %v", plan.Skipped)
+	}
+
+	expectedConfig := "meta:\n  slack: '#payments'\n  team: payments\ndashboards:\n  - 10\nmonitors:\n  - 1\n"
+	if plan.ConfigFile != "config/payments/components.yaml" || string(plan.Config) != expectedConfig {
+		t.Fatalf("expect config %q in config/payments/components.yaml. Got %q in %s", expectedConfig, plan.Config, plan.ConfigFile)
+	}
+
+	if len(git.files) != 0 || len(gh.titles) != 0 {
+		t.Fatalf("expect nothing written on a dry run. Got files %v, pull requests %v", git.files, gh.titles)
+	}
+
+	opts.DryRun = false
+	plan, err = c.Adopt(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(git.files) != 3 || git.files["config/payments/components.yaml"] != expectedConfig {
+		t.Fatalf("expect config and 2 component files. Got %v", git.files)
+	}
+
+	expectedTitle := "[Automated PR] Onboard datadog components owned by [payments] - config/payments/components.yaml"
+	if len(gh.titles) != 1 || gh.titles[0] != expectedTitle || plan.PullRequest != 1 {
+		t.Fatalf("expect pull request %s. Got %v", expectedTitle, gh.titles)
+	}
+
+	// an existing user config which could not be read must not be replaced.
+	c.git = unreadableGitClient{&memGitClient{files: map[string]string{}}}
+	opts.DryRun = true
+	if _, err := c.Adopt(context.Background(), opts); err == nil {
+		t.Fatal("expect an error reading the user config")
+	}
+}
+
+// unreadableGitClient fails to read any file with an error other than a missing file.
+type unreadableGitClient struct {
+	*memGitClient
+}
+
+func (g unreadableGitClient) ReadFile(path string) ([]byte, error) {
+	return nil, os.ErrPermission
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/github"
+
+	"github.com/sirupsen/logrus"
+)
+
+// assignPullRequest requests the reviewers and applies the labels listed in the user config meta. If the meta
+// lists no reviewers, the owners of the changed files are looked up in the CODEOWNERS file of the resources repo.
+// The extra reviewers, e.g. the user who made the change in datadog, are always requested. Errors are logged,
+// the pull request is already created.
+func (c *Controller) assignPullRequest(ctx context.Context, prNumber int, meta config.MetaData, files []string, extraReviewers ...string) {
+	reviewers, teamReviewers := meta.Reviewers, meta.TeamReviewers
+	if len(reviewers) == 0 && len(teamReviewers) == 0 {
+		reviewers, teamReviewers = c.codeowners(files)
+	}
+
+	reviewers = unique(append(append([]string{}, reviewers...), extraReviewers...))
+	teamReviewers = unique(teamReviewers)
+
+	if len(reviewers) > 0 || len(teamReviewers) > 0 {
+		err := c.github.RequestReviewers(ctx, prNumber, reviewers, teamReviewers)
+		if err != nil {
+			logrus.Errorf("Error requesting reviewers %v and teams %v on pull request %d: %s", reviewers, teamReviewers, prNumber, err)
+		}
+	}
+
+	if len(meta.Labels) > 0 {
+		err := c.github.AddLabels(ctx, prNumber, meta.Labels)
+		if err != nil {
+			logrus.Errorf("Error adding labels %v to pull request %d: %s", meta.Labels, prNumber, err)
+		}
+	}
+}
+
+// codeowners returns the owners of files from the first CODEOWNERS file found in the workspace.
+func (c *Controller) codeowners(files []string) (reviewers, teamReviewers []string) {
+	for _, path := range github.CodeownersPaths {
+		body, err := c.git.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		codeowners := github.ParseCodeowners(body)
+		for _, file := range files {
+			fileReviewers, fileTeamReviewers := codeowners.Owners(file)
+			reviewers = append(reviewers, fileReviewers...)
+			teamReviewers = append(teamReviewers, fileTeamReviewers...)
+		}
+
+		return reviewers, teamReviewers
+	}
+
+	return nil, nil
+}
+
+// unique returns the non empty values in the order of their first occurrence.
+func unique(values []string) []string {
+	var (
+		result []string
+		seen   = make(map[string]bool)
+	)
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+
+	return result
+}
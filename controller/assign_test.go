@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+)
+
+func TestAssignPullRequest(t *testing.T) {
+	codeowners := "*                  @org/platform\n/data/team/        @jane @org/sre\n"
+
+	for _, tc := range []struct {
+		name                  string
+		meta                  config.MetaData
+		files                 map[string]string
+		extraReviewers        []string
+		expectedReviewers     []string
+		expectedTeamReviewers []string
+		expectedLabels        []string
+	}{
+		{
+			name:                  "meta",
+			meta:                  config.MetaData{Reviewers: []string{"john"}, TeamReviewers: []string{"payments"}, Labels: []string{"datadog"}},
+			files:                 map[string]string{".github/CODEOWNERS": codeowners},
+			extraReviewers:        []string{"john", "jack"},
+			expectedReviewers:     []string{"john", "jack"},
+			expectedTeamReviewers: []string{"payments"},
+			expectedLabels:        []string{"datadog"},
+		},
+		{
+			name:                  "codeowners",
+			files:                 map[string]string{"CODEOWNERS": codeowners},
+			expectedReviewers:     []string{"jane"},
+			expectedTeamReviewers: []string{"sre", "platform"},
+		},
+		{
+			name:           "no owners",
+			files:          map[string]string{},
+			extraReviewers: []string{""},
+		},
+	} {
+		gh := &recordingGithubClient{}
+		c := &Controller{
+			git:    &memGitClient{files: tc.files},
+			github: gh,
+		}
+
+		c.assignPullRequest(context.Background(), 1, tc.meta, []string{"data/team/monitor-1.json", "config/team.yml"}, tc.extraReviewers...)
+
+		if strings.Join(gh.reviewers, ",") != strings.Join(tc.expectedReviewers, ",") {
+			t.Fatalf("%s: expect reviewers %v. Got %v", tc.name, tc.expectedReviewers, gh.reviewers)
+		}
+
+		if strings.Join(gh.teamReviewers, ",") != strings.Join(tc.expectedTeamReviewers, ",") {
+			t.Fatalf("%s: expect team reviewers %v. Got %v", tc.name, tc.expectedTeamReviewers, gh.teamReviewers)
+		}
+
+		if strings.Join(gh.labels, ",") != strings.Join(tc.expectedLabels, ",") {
+			t.Fatalf("%s: expect labels %v. Got %v", tc.name, tc.expectedLabels, gh.labels)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/coinbase/watchdog/jsondiff"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+const (
+	// maxPullRequestBodyLength is below the limit of 65536 characters of a pull request body in github.
+	maxPullRequestBodyLength = 60000
+
+	// maxChangesLength caps the summary of changes, so the rest of a pull request body fits.
+	maxChangesLength = 50000
+
+	// maxComponentChanges is a number of changes listed per component.
+	maxComponentChanges = 20
+)
+
+// readComponentFiles returns the content of the component files in the workspace, missing files are skipped.
+func (c *Controller) readComponentFiles(team, project string, componentsMap map[types.Component][]string) map[string][]byte {
+	files := make(map[string][]byte)
+	for component, ids := range componentsMap {
+		for _, id := range ids {
+			file := c.cfg.ComponentPath(component, team, project, id)
+			if body, err := c.git.ReadFile(file); err == nil {
+				files[file] = body
+			}
+		}
+	}
+
+	return files
+}
+
+// describeChanges returns a markdown summary of the changes of every component file, computed from the JSON
+// trees of the files before and after the update. The summary is capped at maxChangesLength.
+func (c *Controller) describeChanges(team, project string, componentsMap map[types.Component][]string, before, after map[string][]byte) string {
+	var components []types.Component
+	for component := range componentsMap {
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i] < components[j] })
+
+	var (
+		summary string
+		skipped int
+	)
+	for _, component := range components {
+		for _, id := range componentsMap[component] {
+			file := c.cfg.ComponentPath(component, team, project, id)
+			newBody, ok := after[file]
+			if !ok || bytes.Equal(before[file], newBody) {
+				continue
+			}
+
+			description := fmt.Sprintf("**%s %s** `%s`\n%s\n", component, id, file, componentChanges(before[file], newBody))
+			if len(summary)+len(description) > maxChangesLength {
+				skipped++
+				continue
+			}
+			summary += description
+		}
+	}
+
+	if skipped > 0 {
+		summary += fmt.Sprintf("%d more components changed, see the files of the pull request.\n", skipped)
+	}
+
+	return summary
+}
+
+// componentChanges returns a markdown list of the changes of a component file.
+func componentChanges(oldBody, newBody []byte) string {
+	if oldBody == nil {
+		return "- new component file"
+	}
+
+	oldComponent, newComponent := &datadog.Component{}, &datadog.Component{}
+	if json.Unmarshal(oldBody, oldComponent) != nil || json.Unmarshal(newBody, newComponent) != nil {
+		return "- the component file changed"
+	}
+
+	changes, err := jsondiff.Compare(oldComponent.Payload, newComponent.Payload)
+	if err != nil || len(changes) == 0 {
+		return "- the component file changed"
+	}
+
+	var lines []string
+	for i, change := range changes {
+		if i == maxComponentChanges {
+			lines = append(lines, fmt.Sprintf("- and %d more changes", len(changes)-maxComponentChanges))
+			break
+		}
+
+		lines = append(lines, "- "+change.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// truncateDescription cuts a markdown description to the last line which fits into maxLength and adds a note. A code
+// block left open by the cut is closed, so the note is not rendered as code.
+func truncateDescription(description string, maxLength int) string {
+	if len(description) <= maxLength {
+		return description
+	}
+
+	// a line longer than maxLength is cut at a character boundary.
+	cut := maxLength
+	for cut > 0 && !utf8.RuneStart(description[cut]) {
+		cut--
+	}
+
+	truncated := description[:cut]
+	if i := strings.LastIndex(truncated, "\n"); i > 0 {
+		truncated = truncated[:i]
+	}
+
+	fences := 0
+	for _, line := range strings.Split(truncated, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fences++
+		}
+	}
+
+	if fences%2 == 1 {
+		truncated += "\n```"
+	}
+
+	return truncated + "\n\n... the description is truncated"
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog/pollster"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+// coalesceChecks is a number of times per quiet period the pending changes are checked.
+const coalesceChecks = 10
+
+// changeBatch holds the pending changes of a user config file.
+type changeBatch struct {
+	userConfigFile *config.UserConfigFile
+	componentsMap  map[types.Component][]string
+	modifiedBy     []string
+}
+
+// coalescer merges the changes detected in datadog into a batch per user config file. A batch is released
+// once none of its components changed for the quiet period or, if perTeam is set, once no component of the
+// team changed for the quiet period.
+type coalescer struct {
+	quietPeriod time.Duration
+	perTeam     bool
+
+	batches    map[string]*changeBatch
+	lastChange map[string]time.Time
+}
+
+func newCoalescer(quietPeriod time.Duration, perTeam bool) *coalescer {
+	return &coalescer{
+		quietPeriod: quietPeriod,
+		perTeam:     perTeam,
+		batches:     make(map[string]*changeBatch),
+		lastChange:  make(map[string]time.Time),
+	}
+}
+
+// add adds a changed component to the batch of its user config file.
+func (co *coalescer) add(response *pollster.Response, now time.Time) {
+	file := strings.TrimLeft(response.UserConfigFile.Meta.FilePath, "/")
+	batch, ok := co.batches[file]
+	if !ok {
+		batch = &changeBatch{componentsMap: make(map[types.Component][]string)}
+		co.batches[file] = batch
+	}
+
+	// the latest user config file is kept, it could be reloaded meanwhile.
+	batch.userConfigFile = response.UserConfigFile
+	if !containsString(batch.componentsMap[response.Component], response.ID) {
+		batch.componentsMap[response.Component] = append(batch.componentsMap[response.Component], response.ID)
+	}
+
+	if response.ModifiedBy != "" && !containsString(batch.modifiedBy, response.ModifiedBy) {
+		batch.modifiedBy = append(batch.modifiedBy, response.ModifiedBy)
+	}
+
+	co.lastChange[co.key(batch)] = now
+}
+
+// remove drops a component from the batch of a user config file, e.g. if the component was deleted.
+func (co *coalescer) remove(userConfigFile *config.UserConfigFile, component types.Component, id string) {
+	file := strings.TrimLeft(userConfigFile.Meta.FilePath, "/")
+	batch, ok := co.batches[file]
+	if !ok {
+		return
+	}
+
+	var ids []string
+	for _, pendingID := range batch.componentsMap[component] {
+		if pendingID != id {
+			ids = append(ids, pendingID)
+		}
+	}
+
+	if len(ids) > 0 {
+		batch.componentsMap[component] = ids
+	} else {
+		delete(batch.componentsMap, component)
+	}
+
+	if len(batch.componentsMap) == 0 {
+		delete(co.batches, file)
+	}
+}
+
+// ready removes and returns the batches whose quiet period passed, ordered by the user config file.
+func (co *coalescer) ready(now time.Time) []*changeBatch {
+	var files []string
+	for file, batch := range co.batches {
+		if now.Sub(co.lastChange[co.key(batch)]) >= co.quietPeriod {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	var batches []*changeBatch
+	for _, file := range files {
+		batches = append(batches, co.batches[file])
+		delete(co.batches, file)
+	}
+
+	// forget the changes of the released batches.
+	for key := range co.lastChange {
+		if now.Sub(co.lastChange[key]) >= co.quietPeriod {
+			delete(co.lastChange, key)
+		}
+	}
+
+	return batches
+}
+
+// key returns the key of the quiet period of a batch, the user config file or the team.
+func (co *coalescer) key(batch *changeBatch) string {
+	if co.perTeam {
+		return "team:" + batch.userConfigFile.Meta.Team
+	}
+
+	return "file:" + strings.TrimLeft(batch.userConfigFile.Meta.FilePath, "/")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog/pollster"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestCoalescer(t *testing.T) {
+	var (
+		start    = time.Now()
+		payments = config.NewUserConfigFile(config.MetaData{Team: "payments", FilePath: "/config/payments.yml"}, nil)
+		billing  = config.NewUserConfigFile(config.MetaData{Team: "payments", FilePath: "/config/billing.yml"}, nil)
+		sre      = config.NewUserConfigFile(config.MetaData{Team: "sre", FilePath: "/config/sre.yml"}, nil)
+	)
+
+	change := func(file *config.UserConfigFile, component types.Component, id, modifiedBy string) *pollster.Response {
+		return &pollster.Response{UserConfigFile: file, Component: component, ID: id, ModifiedBy: modifiedBy}
+	}
+
+	files := func(batches []*changeBatch) string {
+		var names []string
+		for _, batch := range batches {
+			names = append(names, batch.userConfigFile.Meta.FilePath)
+		}
+		return strings.Join(names, ",")
+	}
+
+	co := newCoalescer(time.Minute, false)
+	co.add(change(payments, types.ComponentDashboard, "1", "jane"), start)
+	co.add(change(payments, types.ComponentDashboard, "1", "jane"), start.Add(30*time.Second))
+	co.add(change(payments, types.ComponentMonitor, "2", "john"), start.Add(40*time.Second))
+	co.add(change(sre, types.ComponentMonitor, "3", ""), start)
+	co.add(change(sre, types.ComponentMonitor, "4", ""), start)
+	co.remove(sre, types.ComponentMonitor, "4")
+
+	if batches := co.ready(start.Add(90 * time.Second)); files(batches) != "/config/sre.yml" {
+		t.Fatalf("expect the sre batch ready. Got %s", files(batches))
+	} else if ids := batches[0].componentsMap[types.ComponentMonitor]; strings.Join(ids, ",") != "3" {
+		t.Fatalf("expect monitors [3]. Got %v", ids)
+	}
+
+	batches := co.ready(start.Add(100 * time.Second))
+	if files(batches) != "/config/payments.yml" {
+		t.Fatalf("expect the payments batch ready. Got %s", files(batches))
+	}
+
+	if ids := batches[0].componentsMap[types.ComponentDashboard]; strings.Join(ids, ",") != "1" {
+		t.Fatalf("expect dashboards [1]. Got %v", ids)
+	}
+
+	if ids := batches[0].componentsMap[types.ComponentMonitor]; strings.Join(ids, ",") != "2" {
+		t.Fatalf("expect monitors [2]. Got %v", ids)
+	}
+
+	if strings.Join(batches[0].modifiedBy, ",") != "jane,john" {
+		t.Fatalf("expect modifiers [jane john]. Got %v", batches[0].modifiedBy)
+	}
+
+	if batches := co.ready(start.Add(time.Hour)); len(batches) != 0 {
+		t.Fatalf("expect no batches. Got %s", files(batches))
+	}
+
+	// a change of any config file of the team holds the batches of the team
+	co = newCoalescer(time.Minute, true)
+	co.add(change(payments, types.ComponentDashboard, "1", ""), start)
+	co.add(change(sre, types.ComponentDashboard, "5", ""), start)
+	co.add(change(billing, types.ComponentMonitor, "6", ""), start.Add(50*time.Second))
+
+	if batches := co.ready(start.Add(time.Minute)); files(batches) != "/config/sre.yml" {
+		t.Fatalf("expect the sre batch ready. Got %s", files(batches))
+	}
+
+	if batches := co.ready(start.Add(110 * time.Second)); files(batches) != "/config/billing.yml,/config/payments.yml" {
+		t.Fatalf("expect the payments team batches ready. Got %s", files(batches))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/coinbase/watchdog/config"
 	"github.com/coinbase/watchdog/controller/notify"
 	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/pollster"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
 	"github.com/coinbase/watchdog/primitives/git"
@@ -53,10 +55,14 @@ type Controller struct {
 	github              github.Client
 	pollster            pollster.Pollster
 	notificationHandler *notify.Handler
+
+	// recreated maps the deleted components recreated from git to their new IDs until the pull request replacing
+	// the ID is opened, so a deleted component handled again after a failure is not created twice.
+	recreated map[string]string
 }
 
 // ComponentExists checks if a component file on the master branch.
-func (c *Controller) ComponentExists(component types.Component, team, project string, id int) bool {
+func (c *Controller) ComponentExists(component types.Component, team, project, id string) bool {
 	c.Lock()
 	defer c.Unlock()
 
@@ -74,7 +80,14 @@ func (c *Controller) ComponentExists(component types.Component, team, project st
 // CreatePullRequest takes a map of datadog components and their ids
 // checks for the difference between current state and state from master branch
 // and creates a pull requests if needed. This is the main controller's function.
-func (c *Controller) CreatePullRequest(team, project, configFile string, componentsMap map[types.Component][]int) error {
+func (c *Controller) CreatePullRequest(ctx context.Context, team, project, configFile string, componentsMap map[types.Component][]string) error {
+	return c.createPullRequest(ctx, team, project, configFile, componentsMap, nil)
+}
+
+// createPullRequest creates a pull request for the changed components. If the users who changed the components
+// in datadog are known, the users are named in the pull request and the slack message and, if the users are mapped
+// to github logins, requested to review the pull request.
+func (c *Controller) createPullRequest(ctx context.Context, team, project, configFile string, componentsMap map[types.Component][]string, modifiedBy []string) error {
 	if len(componentsMap) == 0 {
 		return nil
 	}
@@ -114,9 +127,12 @@ func (c *Controller) CreatePullRequest(team, project, configFile string, compone
 		return errors.Wrapf(err, "unable to checkout to branch %s", branch)
 	}
 
+	// keep the component files of master to describe the changes
+	before := c.readComponentFiles(team, project, componentsMap)
+
 	// add files from component map to a git commit
 	for component, ids := range componentsMap {
-		err = c.addFiles(team, project, component, ids)
+		err = c.addFiles(ctx, team, project, component, ids)
 		if err != nil {
 			logrus.Errorf("error adding component %s files: %s", component, err)
 		}
@@ -134,7 +150,11 @@ func (c *Controller) CreatePullRequest(team, project, configFile string, compone
 		return nil
 	}
 
-	pullRequestTitle, pullRequestBody := c.preparePullRequestDescription(team, patch, configFile, c.cfg.PullRequestBodyExtra(), componentsMap)
+	changes := c.describeChanges(team, project, componentsMap, before, c.readComponentFiles(team, project, componentsMap))
+	if len(modifiedBy) > 0 {
+		changes = fmt.Sprintf("Changed in datadog by `%s`\n\n", strings.Join(modifiedBy, "`, `")) + changes
+	}
+	pullRequestTitle, pullRequestBody := c.preparePullRequestDescription(team, changes, configFile, c.cfg.PullRequestBodyExtra(), componentsMap)
 
 	logrus.Infof("A change has been detected. Patch:\n%s", patch)
 
@@ -148,7 +168,7 @@ func (c *Controller) CreatePullRequest(team, project, configFile string, compone
 
 	// find duplicate and outdated PRs
 	// duplicates are PRs that have exactly the same change in them, outdated are the opposite
-	duplicatePRs, outdatedPRs, err := c.findOpenPRs(pullRequestTitle, commitHash, c.cfg.SystemConfig.GitUser())
+	duplicatePRs, outdatedPRs, err := c.findOpenPRs(ctx, pullRequestTitle, commitHash, c.cfg.SystemConfig.GitUser())
 	if err != nil {
 		return errors.Wrapf(err, "unable to find open PRs")
 	}
@@ -164,37 +184,76 @@ func (c *Controller) CreatePullRequest(team, project, configFile string, compone
 		return nil
 	}
 
-	logrus.Info("No opened PRs found")
+	var (
+		newPRNumber  int
+		notification = "A new pull request https://%s/%s/%s/pull/%d has been created"
+	)
+	if c.cfg.GetGithubUpdatePullRequests() && len(outdatedPRs) > 0 {
+		// update the latest outdated PR to keep its reviews, the other outdated PRs are closed in its favor
+		var pr *github.PullRequest
+		pr, outdatedPRs = latestPullRequest(outdatedPRs)
 
-	// push changes to remote branch
-	err = c.git.Push(branch)
-	if err != nil {
-		return errors.Wrapf(err, "unable to push changes to remote branch %s", branch)
+		err = c.updatePullRequest(ctx, pr, branch, commitHash, pullRequestTitle, pullRequestBody)
+		if err != nil {
+			return errors.Wrapf(err, "unable to update pull request %d", pr.Number)
+		}
+
+		newPRNumber, notification = pr.Number, "Pull request https://%s/%s/%s/pull/%d has been updated"
+	} else {
+		logrus.Info("No opened PRs found")
+
+		// push changes to remote branch
+		err = c.git.Push(branch)
+		if err != nil {
+			return errors.Wrapf(err, "unable to push changes to remote branch %s", branch)
+		}
+
+		// create a new pull request
+		newPRNumber, err = c.createNewPullRequest(ctx, pullRequestTitle, branch, "master", pullRequestBody)
+		if err != nil {
+			return errors.Wrapf(err, "unable to create a new pull request")
+		}
 	}
 
-	// create a new pull request
-	newPRNumber, err := c.createNewPullRequest(context.Background(), pullRequestTitle, branch, "master", pullRequestBody)
-	if err != nil {
-		return errors.Wrapf(err, "unable to create a new pull request")
+	// request reviews of the owners and the user who made the change, do not exit on failure
+	var meta config.MetaData
+	if userConfig, err := c.cfg.UserConfigFromFile(configFile, false); err != nil {
+		logrus.Errorf("Error retrieving user config from a file %s: %s", configFile, err)
+	} else {
+		meta = userConfig.Meta
 	}
 
-	// notify slack channel about a new pull request
-	c.notify(
-		configFile,
-		fmt.Sprintf("A new pull request https://%s/%s/%s/pull/%d has been created", c.cfg.GetGithubBaseURL(), c.cfg.GetGithubProjectOwner(), c.cfg.GetGithubRepo(), newPRNumber),
-		"")
+	var files []string
+	for component, ids := range componentsMap {
+		for _, id := range ids {
+			files = append(files, c.cfg.ComponentPath(component, team, project, id))
+		}
+	}
+	sort.Strings(files)
+	var reviewers []string
+	for _, user := range modifiedBy {
+		reviewers = append(reviewers, c.cfg.GetGithubReviewer(user))
+	}
+	c.assignPullRequest(ctx, newPRNumber, meta, files, reviewers...)
+
+	// notify slack channel about a new or updated pull request
+	title := fmt.Sprintf(notification, c.cfg.GetGithubBaseURL(), c.cfg.GetGithubProjectOwner(), c.cfg.GetGithubRepo(), newPRNumber)
+	if len(modifiedBy) > 0 {
+		title += fmt.Sprintf(" for a change by %s", strings.Join(modifiedBy, ", "))
+	}
+	c.notify(ctx, configFile, title, "")
 
 	// close outdated PRs, do not exit on failure
-	c.tryCloseOutdatedPRs(newPRNumber, outdatedPRs)
+	c.tryCloseOutdatedPRs(ctx, newPRNumber, outdatedPRs)
 	return nil
 }
 
-func (c *Controller) notify(configFile, title, body string) {
+func (c *Controller) notify(ctx context.Context, configFile, title, body string) {
 	// TODO: refactor method do be generic for different notification backends.
 	userConfig, err := c.cfg.UserConfigFromFile(configFile, false)
 	if err == nil {
 		err = c.notificationHandler.AddComment(
-			context.Background(),
+			ctx,
 			notify.NInfo,
 			title,
 			body,
@@ -207,7 +266,7 @@ func (c *Controller) notify(configFile, title, body string) {
 	}
 }
 
-func (c *Controller) tryCloseOutdatedPRs(newPRNumber int, prs []*github.PullRequest) {
+func (c *Controller) tryCloseOutdatedPRs(ctx context.Context, newPRNumber int, prs []*github.PullRequest) {
 	for _, pr := range prs {
 		logrus.Debugf("Closing PR %d branch %s", pr.Number, pr.Branch)
 		err := c.closePullRequestRemoveBranch(pr.Number, pr.Branch)
@@ -216,18 +275,18 @@ func (c *Controller) tryCloseOutdatedPRs(newPRNumber int, prs []*github.PullRequ
 		}
 
 		// add comment to closed PR
-		err = c.github.CreatePullRequestComment(context.Background(), pr.Number, fmt.Sprintf(":warning: **Closed in favor of #%d**", newPRNumber))
+		err = c.github.CreatePullRequestComment(ctx, pr.Number, fmt.Sprintf(":warning: **Closed in favor of #%d**", newPRNumber))
 		if err != nil {
 			logrus.Errorf("Error commenting on pull request %d: %s", pr.Number, err)
 		}
 	}
 }
 
-func (c *Controller) preparePullRequestDescription(team, patch, configFile, bodyExtra string, componentsMap map[types.Component][]int) (title, body string) {
+func (c *Controller) preparePullRequestDescription(team, changes, configFile, bodyExtra string, componentsMap map[types.Component][]string) (title, body string) {
 	title = fmt.Sprintf("[Automated PR] Update datadog component files owned by [%s] - %s", team, configFile)
 
 	body = "Modified component files have been detected and a new PR has been created\n\n"
-	body += "The following components are different from master branch:\n" + patch
+	body += "The following components are different from master branch:\n" + changes
 	body += "\n\n"
 
 	// if only one component with a single ID, add a component name to title and
@@ -235,7 +294,7 @@ func (c *Controller) preparePullRequestDescription(team, patch, configFile, body
 	if len(componentsMap) == 1 {
 		for name, ids := range componentsMap {
 			if len(ids) == 1 {
-				title += fmt.Sprintf(" %s %d", name, ids[0])
+				title += fmt.Sprintf(" %s %s", name, ids[0])
 				body += ":warning: **Closing this PR will revert all changes made in datadog!!!**"
 			}
 		}
@@ -246,19 +305,28 @@ func (c *Controller) preparePullRequestDescription(team, patch, configFile, body
 		body += bodyExtra
 	}
 
+	// github rejects pull requests with a larger body
+	body = truncateDescription(body, maxPullRequestBodyLength)
+
 	return
 }
 
-func (c *Controller) addFiles(team, project string, component types.Component, ids []int) error {
+func (c *Controller) addFiles(ctx context.Context, team, project string, component types.Component, ids []string) error {
 	for _, id := range ids {
 		// build a filepath to component json
 		filename := c.cfg.ComponentPath(component, team, project, id)
 
 		// allocate buffer for datadog component
 		buf := new(bytes.Buffer)
-		err := c.datadog.Write(component, id, buf)
+		err := c.datadog.Write(ctx, component, id, buf)
+		if client.IsNotFound(err) {
+			// deleted components are handled by HandleDeletedComponent, a file must not be overwritten with an error.
+			logrus.Warnf("Component %s with id %s was deleted in datadog. Skipping", component, id)
+			continue
+		}
+
 		if err != nil {
-			logrus.Errorf("unable to write a component %s with id %d to a buffer: %s", component, id, err)
+			logrus.Errorf("unable to write a component %s with id %s to a buffer: %s", component, id, err)
 			continue
 		}
 
@@ -287,10 +355,10 @@ func (c *Controller) createNewPullRequest(ctx context.Context, title, branch, ba
 	return prNumber, nil
 }
 
-func (c *Controller) findOpenPRs(title, newCommitHash, owner string) (duplicates, outdated []*github.PullRequest, err error) {
+func (c *Controller) findOpenPRs(ctx context.Context, title, newCommitHash, owner string) (duplicates, outdated []*github.PullRequest, err error) {
 	// find the similar PRs by a title
 	logrus.Infof("Searching open PRs on github with title %s", title)
-	prs, err := c.github.FindPullRequests(context.Background(), owner, title)
+	prs, err := c.github.FindPullRequests(ctx, owner, title)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -316,6 +384,53 @@ func (c *Controller) findOpenPRs(title, newCommitHash, owner string) (duplicates
 	return
 }
 
+// updatePullRequest force-pushes a new commit to the branch of an open pull request, updates its title and body
+// and comments with the difference between the previous and the new commit.
+func (c *Controller) updatePullRequest(ctx context.Context, pr *github.PullRequest, branch, commitHash, title, body string) error {
+	_, patch, err := c.git.DiffCommits(pr.SHA, commitHash)
+	if err != nil {
+		return errors.Wrapf(err, "unable to compare commits %s and %s", pr.SHA, commitHash)
+	}
+
+	logrus.Infof("Updating PR %d branch %s with commit %s", pr.Number, pr.Branch, commitHash)
+	err = c.git.Push(git.UpdateRefSpec(branch, pr.Branch))
+	if err != nil {
+		return errors.Wrapf(err, "unable to push changes to remote branch %s", pr.Branch)
+	}
+
+	err = c.github.EditPullRequest(ctx, pr.Number, title, body)
+	if err != nil {
+		return err
+	}
+
+	if len(patch) > maxChangesLength {
+		patch = patch[:maxChangesLength] + "\n... the diff is truncated"
+	}
+
+	// the branch is already updated, do not exit on failure
+	err = c.github.CreatePullRequestComment(ctx, pr.Number, fmt.Sprintf(":arrows_counterclockwise: **Updated with a new change**\n```diff\n%s\n```", patch))
+	if err != nil {
+		logrus.Errorf("Error commenting on pull request %d: %s", pr.Number, err)
+	}
+
+	return nil
+}
+
+// latestPullRequest returns the most recently created pull request and the rest of the pull requests.
+func latestPullRequest(prs []*github.PullRequest) (*github.PullRequest, []*github.PullRequest) {
+	latest := 0
+	for i, pr := range prs {
+		if pr.CreatedAt != nil && (prs[latest].CreatedAt == nil || pr.CreatedAt.After(*prs[latest].CreatedAt)) {
+			latest = i
+		}
+	}
+
+	var rest []*github.PullRequest
+	rest = append(rest, prs[:latest]...)
+	rest = append(rest, prs[latest+1:]...)
+	return prs[latest], rest
+}
+
 func (c *Controller) closePullRequestRemoveBranch(number int, branch string) error {
 	err := c.github.ClosePullRequests([]int{number}, true)
 	if err != nil {
@@ -1,15 +1,24 @@
 package controller
 
 import (
-	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"context"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"github.com/coinbase/watchdog/primitives/github"
 )
 
 func TestController_preparePullRequestDescription(t *testing.T) {
 	c := &Controller{}
 
-	components := map[types.Component][]int{
-		types.ComponentDashboard: {1, 2, 3},
+	components := map[types.Component][]string{
+		types.ComponentDashboard: {"1", "2", "3"},
 	}
 
 	title, body := c.preparePullRequestDescription("test-team", "patch-string", "test/file1.yml", "bodyExtra", components)
@@ -27,8 +36,8 @@ func TestController_preparePullRequestDescription(t *testing.T) {
 		t.Fatalf("expect body %s .Got %s", expectedBody, body)
 	}
 
-	components = map[types.Component][]int{
-		types.ComponentDashboard: {1},
+	components = map[types.Component][]string{
+		types.ComponentDashboard: {"1"},
 	}
 	title, body = c.preparePullRequestDescription("test-team", "patch-string", "test/file1.yml", "", components)
 	expectedTitle = "[Automated PR] Update datadog component files owned by [test-team] - test/file1.yml dashboard 1"
@@ -43,4 +52,246 @@ func TestController_preparePullRequestDescription(t *testing.T) {
 	if body != expectedBody {
 		t.Fatalf("expect body %s .Got %s", expectedBody, body)
 	}
+
+	components = map[types.Component][]string{
+		types.ComponentUnifiedDashboard: {"abc-def-ghi"},
+	}
+	title, _ = c.preparePullRequestDescription("test-team", "patch-string", "test/file1.yml", "", components)
+	expectedTitle = "[Automated PR] Update datadog component files owned by [test-team] - test/file1.yml unified_dashboard abc-def-ghi"
+
+	if title != expectedTitle {
+		t.Fatalf("expect title %s .Got %s", expectedTitle, title)
+	}
+}
+
+func TestController_describeChanges(t *testing.T) {
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+	}
+
+	components := map[types.Component][]string{
+		types.ComponentMonitor:   {"1", "2", "3"},
+		types.ComponentDashboard: {"4"},
+	}
+
+	before := map[string][]byte{
+		"data/team/monitor-1.json":   []byte(`{"type":"monitor","monitor":{"monitor":{"options":{"thresholds":{"critical":5}},"tags":["a"]}}}`),
+		"data/team/monitor-3.json":   []byte(`{"type":"monitor","monitor":{"monitor":{"name":"same"}}}`),
+		"data/team/dashboard-4.json": []byte(`{"type":"dashboard","dashboard":{"dash":{"graphs":[{"title":"Latency p99","definition":{"requests":[{"q":"x"}]}}]}}}`),
+	}
+
+	after := map[string][]byte{
+		"data/team/monitor-1.json":   []byte(`{"type":"monitor","monitor":{"monitor":{"options":{"thresholds":{"critical":10}},"tags":["a","b"]}}}`),
+		"data/team/monitor-2.json":   []byte(`{"type":"monitor","monitor":{"monitor":{"name":"new"}}}`),
+		"data/team/monitor-3.json":   []byte(`{"type":"monitor","monitor":{"monitor":{"name":"same"}}}`),
+		"data/team/dashboard-4.json": []byte(`{"type":"dashboard","dashboard":{"dash":{"graphs":[{"title":"Latency p99","definition":{"requests":[{"q":"y"}]}}]}}}`),
+	}
+
+	expected := "**dashboard 4** `data/team/dashboard-4.json`\n" +
+		"- graph 'Latency p99' definition.requests[0].q changed from `\"x\"` to `\"y\"`\n" +
+		"**monitor 1** `data/team/monitor-1.json`\n" +
+		"- monitor.options.thresholds.critical changed from `5` to `10`\n" +
+		"- monitor.tag added: `\"b\"`\n" +
+		"**monitor 2** `data/team/monitor-2.json`\n" +
+		"- new component file\n"
+
+	if summary := c.describeChanges("team", "", components, before, after); summary != expected {
+		t.Fatalf("expect summary:\n%s\nGot:\n%s", expected, summary)
+	}
+
+	_, body := c.preparePullRequestDescription("test-team", "changes", "test/file1.yml", strings.Repeat("x", 70000), components)
+	if len(body) > 65536 || !strings.HasSuffix(body, "the description is truncated") {
+		t.Fatalf("expect a truncated body. Got %d characters", len(body))
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	description := "changes\n```diff\n- a\n+ b\n```\nend"
+	if truncated := truncateDescription(description, 100); truncated != description {
+		t.Fatalf("expect a short description unchanged. Got %s", truncated)
+	}
+
+	// the cut is on the last line which fits and the open code block is closed
+	expected := "changes\n```diff\n- a\n```\n\n... the description is truncated"
+	if truncated := truncateDescription(description, 20); truncated != expected {
+		t.Fatalf("expect %q. Got %q", expected, truncated)
+	}
+
+	// a single line is cut at a character boundary
+	if truncated := truncateDescription("ééé", 3); truncated != "é\n\n... the description is truncated" {
+		t.Fatalf("expect the line cut after the first character. Got %q", truncated)
+	}
+}
+
+// reviewerSystemsConfig maps the datadog user jane@example.com to the github login jane.
+type reviewerSystemsConfig struct {
+	fakeSystemsConfig
+}
+
+func (f reviewerSystemsConfig) GetGithubReviewer(datadogUser string) string {
+	if datadogUser == "jane@example.com" {
+		return "jane"
+	}
+
+	return ""
+}
+
+func TestController_createPullRequestModifiedBy(t *testing.T) {
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"dash":{"id":` + id + `,"title":"new"}}`), nil
+		}, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		modifiedBy        []string
+		expectedReviewers []string
+	}{
+		{modifiedBy: []string{"jane@example.com"}, expectedReviewers: []string{"jane"}},
+		{modifiedBy: []string{"john@example.com"}},
+		{modifiedBy: []string{"john@example.com", "jane@example.com"}, expectedReviewers: []string{"jane"}},
+		{},
+	} {
+		git := &memGitClient{files: map[string]string{
+			"data/team/dashboard-10.json": `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"old"}}}`,
+		}}
+		gh := &recordingGithubClient{}
+
+		c := &Controller{
+			cfg: &config.Config{
+				UserConfig:   &fakeUserConfig{},
+				SystemConfig: &reviewerSystemsConfig{},
+			},
+			datadog:             ddog,
+			git:                 git,
+			github:              gh,
+			notificationHandler: notify.NewHandler(),
+		}
+
+		err := c.createPullRequest(context.Background(), "team", "", "config/team.yml",
+			map[types.Component][]string{types.ComponentDashboard: {"10"}}, tc.modifiedBy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gh.bodies) != 1 {
+			t.Fatalf("expect a pull request. Got %d", len(gh.bodies))
+		}
+
+		named := strings.Contains(gh.bodies[0], "Changed in datadog by `"+strings.Join(tc.modifiedBy, "`, `")+"`")
+		if named != (len(tc.modifiedBy) > 0) {
+			t.Fatalf("expect the users %v named in the body. Got %s", tc.modifiedBy, gh.bodies[0])
+		}
+
+		if strings.Join(gh.reviewers, ",") != strings.Join(tc.expectedReviewers, ",") {
+			t.Fatalf("expect reviewers %v. Got %v", tc.expectedReviewers, gh.reviewers)
+		}
+	}
+}
+
+// updateSystemsConfig enables updating the outdated pull requests.
+type updateSystemsConfig struct {
+	fakeSystemsConfig
+}
+
+func (f updateSystemsConfig) GetGithubUpdatePullRequests() bool {
+	return true
+}
+
+// outdatedGitClient finds every open pull request outdated and records the pushed branches.
+type outdatedGitClient struct {
+	memGitClient
+	pushed []string
+}
+
+func (g *outdatedGitClient) DiffCommits(commitAHash, commitBHash string, files ...string) (bool, string, error) {
+	return true, "-old\n+new", nil
+}
+
+func (g *outdatedGitClient) Push(branches ...string) error {
+	g.pushed = append(g.pushed, branches...)
+	return nil
+}
+
+// updatingGithubClient records edited and closed pull requests and comments.
+type updatingGithubClient struct {
+	recordingGithubClient
+	edited   []int
+	closed   []int
+	comments map[int][]string
+}
+
+func (g *updatingGithubClient) EditPullRequest(ctx context.Context, number int, title, body string) error {
+	g.edited = append(g.edited, number)
+	return nil
+}
+
+func (g *updatingGithubClient) ClosePullRequests(prs []int, removeBranch bool) error {
+	g.closed = append(g.closed, prs...)
+	return nil
+}
+
+func (g *updatingGithubClient) CreatePullRequestComment(ctx context.Context, id int, text string) error {
+	g.comments[id] = append(g.comments[id], text)
+	return nil
+}
+
+func TestController_createPullRequestUpdate(t *testing.T) {
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"dash":{"id":` + id + `,"title":"new"}}`), nil
+		}, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	older, newer := time.Now().Add(-time.Hour), time.Now()
+	git := &outdatedGitClient{memGitClient: memGitClient{files: map[string]string{}}}
+	gh := &updatingGithubClient{comments: make(map[int][]string)}
+	gh.open = []*github.PullRequest{
+		{Number: 1, Branch: "refs/heads/team/1", SHA: "a", CreatedAt: &older},
+		{Number: 2, Branch: "refs/heads/team/2", SHA: "b", CreatedAt: &newer},
+	}
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{},
+			SystemConfig: &updateSystemsConfig{},
+		},
+		datadog:             ddog,
+		git:                 git,
+		github:              gh,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	err = c.createPullRequest(context.Background(), "team", "", "config/team.yml",
+		map[types.Component][]string{types.ComponentDashboard: {"10"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gh.titles) != 0 {
+		t.Fatalf("expect no new pull request. Got %v", gh.titles)
+	}
+
+	if len(git.pushed) != 1 || !strings.HasPrefix(git.pushed[0], "+refs/heads/team/") || !strings.HasSuffix(git.pushed[0], ":refs/heads/team/2") {
+		t.Fatalf("expect a force-push to refs/heads/team/2. Got %v", git.pushed)
+	}
+
+	if len(gh.edited) != 1 || gh.edited[0] != 2 {
+		t.Fatalf("expect pull request 2 edited. Got %v", gh.edited)
+	}
+
+	if len(gh.comments[2]) != 1 || !strings.Contains(gh.comments[2][0], "-old\n+new") {
+		t.Fatalf("expect the incremental diff commented on pull request 2. Got %v", gh.comments[2])
+	}
+
+	if len(gh.closed) != 1 || gh.closed[0] != 1 || len(gh.comments[1]) != 1 || !strings.Contains(gh.comments[1][0], "#2") {
+		t.Fatalf("expect pull request 1 closed in favor of 2. Got closed %v, comments %v", gh.closed, gh.comments[1])
+	}
 }
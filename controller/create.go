@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// createdComponent is a datadog component created from a component file.
+type createdComponent struct {
+	file      string
+	component types.Component
+	id        string
+}
+
+// createFromFiles creates datadog components from the added and modified files of a pull request which have
+// no ID. For each user config file owning the created components a follow-up pull request is opened, it adds
+// the new IDs to the user config and renames the component files to match Config.ComponentPath. The files which
+// have an ID, e.g. a moved or renamed component file, are returned to be restored instead of creating a copy.
+// If the files could not be read, the modified files are returned to be restored as they are.
+func (c *Controller) createFromFiles(ctx context.Context, prNumber int, createdFiles, modifiedFiles []string) ([]string, error) {
+	if len(createdFiles) == 0 && len(modifiedFiles) == 0 {
+		return modifiedFiles, nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	err := c.git.PullMaster()
+	if err != nil {
+		return modifiedFiles, errors.Wrap(err, "unable to pull master branch")
+	}
+
+	type newComponent struct {
+		file      string
+		component *datadog.Component
+	}
+
+	var (
+		restore       []string
+		newComponents []newComponent
+	)
+
+	for _, file := range append(append([]string{}, createdFiles...), modifiedFiles...) {
+		component, err := c.readComponentFile(file)
+		if err != nil {
+			return modifiedFiles, err
+		}
+
+		id, err := c.datadog.PayloadID(component)
+		if err == datadog.ErrCreateNotSupported || (err == nil && id != "") {
+			restore = append(restore, file)
+			continue
+		}
+
+		if err != nil {
+			return modifiedFiles, errors.Wrapf(err, "unable to read component id from file %s", file)
+		}
+
+		newComponents = append(newComponents, newComponent{file, component})
+	}
+
+	var (
+		errs    []string
+		created = make(map[*config.UserConfigFile][]createdComponent)
+	)
+	for _, nc := range newComponents {
+		cfgFile, err := c.userConfigFileForComponent(nc.component.Type, nc.file)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		logrus.Infof("Creating datadog component %s from pull request %d", nc.file, prNumber)
+		id, err := c.datadog.Create(ctx, nc.component)
+		if id == "" {
+			errs = append(errs, errors.Wrapf(err, "unable to create a component from file %s", nc.file).Error())
+			continue
+		}
+
+		if err != nil {
+			// the component was created, but some of its dependencies were not.
+			errs = append(errs, errors.Wrapf(err, "component %s %s was created from file %s with errors", nc.component.Type, id, nc.file).Error())
+		}
+
+		created[cfgFile] = append(created[cfgFile], createdComponent{nc.file, nc.component.Type, id})
+	}
+
+	for _, cfgFile := range sortedUserConfigFiles(created) {
+		components := created[cfgFile]
+
+		newPRNumber, err := c.createIDsPullRequest(ctx, cfgFile, components)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "unable to open a pull request to update %s", cfgFile.Meta.FilePath).Error())
+			continue
+		}
+
+		title := fmt.Sprintf("Created %d datadog component(s). Pull request https://%s/%s/%s/pull/%d adds them to %s",
+			len(components), c.cfg.GetGithubBaseURL(), c.cfg.GetGithubProjectOwner(), c.cfg.GetGithubRepo(), newPRNumber, cfgFile.Meta.FilePath)
+
+		e := c.notificationHandler.AddComment(ctx, notify.NSuccess, title, createdComponentsList(components),
+			notify.WithGithubPRComment(prNumber), notify.WithSlackMessage(cfgFile.Meta.Slack))
+		if e != nil {
+			logrus.Errorf("Error commenting on pull request %d: %s", prNumber, e)
+		}
+	}
+
+	return restore, c.error(errs)
+}
+
+// createIDsPullRequest opens a pull request which adds the IDs of created components to a user config file
+// and moves the component files to their path in Config.ComponentPath. Must be called with the lock held.
+func (c *Controller) createIDsPullRequest(ctx context.Context, cfgFile *config.UserConfigFile, components []createdComponent) (int, error) {
+	team, project := cfgFile.Meta.Team, cfgFile.Meta.Project
+	configFile := strings.TrimLeft(cfgFile.Meta.FilePath, "/")
+
+	err := c.git.PullMaster()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to pull git master")
+	}
+
+	branch := fmt.Sprintf("refs/heads/%s/%d", team, time.Now().UnixNano())
+	err = c.git.CreateBranch(branch)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to create branch %s", branch)
+	}
+	defer func() {
+		err = c.git.RemoveBranch(branch)
+		if err != nil {
+			logrus.Errorf("Error removing local branch %s: %s", branch, err)
+		}
+	}()
+
+	err = c.git.Checkout(branch, false, false)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to checkout to branch %s", branch)
+	}
+
+	body, err := c.git.ReadFile(configFile)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to read user config %s", configFile)
+	}
+
+	ids := make(map[types.Component][]string)
+	for _, created := range components {
+		ids[created.component] = append(ids[created.component], created.id)
+	}
+
+	for _, ct := range c.datadog.Registry.ComponentTypes() {
+		if len(ids[ct.Type()]) == 0 {
+			continue
+		}
+
+		body, err = config.AddComponentIDs(body, ct.ConfigKey(), ids[ct.Type()]...)
+		if err != nil {
+			return 0, errors.Wrapf(err, "unable to add %s ids to %s", ct.Type(), configFile)
+		}
+	}
+
+	err = c.git.NewFile(configFile, body)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to write user config %s", configFile)
+	}
+
+	err = c.git.Add(configFile)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to add a file %s to a commit", configFile)
+	}
+
+	// replace the original files with the components as stored in datadog, under the expected path.
+	for _, created := range components {
+		if created.file != c.cfg.ComponentPath(created.component, team, project, created.id) {
+			err = c.git.Remove(created.file)
+			if err != nil {
+				return 0, errors.Wrapf(err, "unable to remove a file %s", created.file)
+			}
+		}
+
+		err = c.addFiles(ctx, team, project, created.component, []string{created.id})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	msg, commitHash, err := c.git.Commit("Add created component IDs")
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to make a new commit")
+	}
+
+	logrus.Debugf("A new commit created %s\n%s", commitHash, msg)
+
+	err = c.git.Push(branch)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unable to push changes to remote branch %s", branch)
+	}
+
+	title := fmt.Sprintf("[Automated PR] Add created datadog components owned by [%s] - %s", team, configFile)
+	description := "New datadog components have been created from component files:\n\n" + createdComponentsList(components)
+	description += "\nMerge this PR to add their IDs to the user config. :warning: **Closing this PR leaves the created components unmanaged!!!**"
+	if bodyExtra := c.cfg.PullRequestBodyExtra(); bodyExtra != "" {
+		description += "\n\n" + bodyExtra
+	}
+
+	return c.createNewPullRequest(ctx, title, branch, "master", description)
+}
+
+// userConfigFileForComponent finds a user config file which owns a component file by matching the file directory
+// with the component path of the config team and project. If several config files match, the one which already
+// lists components of the same type is preferred.
+func (c *Controller) userConfigFileForComponent(component types.Component, file string) (*config.UserConfigFile, error) {
+	dir := path.Dir(strings.TrimLeft(file, "/"))
+
+	var matches []*config.UserConfigFile
+	for _, cfgFile := range c.cfg.UserConfigFiles() {
+		componentPath := c.cfg.ComponentPath(component, cfgFile.Meta.Team, cfgFile.Meta.Project, "")
+		if path.Dir(strings.TrimLeft(componentPath, "/")) == dir {
+			matches = append(matches, cfgFile)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.Errorf("no user config file found for component file %s", file)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		iListed := len(matches[i].Components()[component]) > 0
+		jListed := len(matches[j].Components()[component]) > 0
+		if iListed != jListed {
+			return iListed
+		}
+
+		return matches[i].Meta.FilePath < matches[j].Meta.FilePath
+	})
+
+	return matches[0], nil
+}
+
+func (c *Controller) readComponentFile(file string) (*datadog.Component, error) {
+	body, err := c.git.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read component file %s", file)
+	}
+
+	component := &datadog.Component{}
+	if err := json.Unmarshal(body, component); err != nil {
+		return nil, errors.Wrapf(err, "unable to unmarshal to datadog component. File %s", file)
+	}
+
+	return component, nil
+}
+
+func createdComponentsList(components []createdComponent) string {
+	var list string
+	for _, created := range components {
+		list += fmt.Sprintf("- %s `%s` from `%s`\n", created.component, created.id, created.file)
+	}
+
+	return list
+}
+
+func sortedUserConfigFiles(m map[*config.UserConfigFile][]createdComponent) []*config.UserConfigFile {
+	var files []*config.UserConfigFile
+	for cfgFile := range m {
+		files = append(files, cfgFile)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Meta.FilePath < files[j].Meta.FilePath
+	})
+
+	return files
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestCreateFromFiles(t *testing.T) {
+	var created []string
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"dash":{"id":` + id + `,"title":"test"}}`), nil
+		}, nil, nil,
+		datadog.WithCreateFn(func(payload json.RawMessage) (string, error) {
+			dashboard := struct {
+				Dash struct {
+					ID json.Number `json:"id"`
+				} `json:"dash"`
+			}{}
+			err := json.Unmarshal(payload, &dashboard)
+			return dashboard.Dash.ID.String(), err
+		}, func(ctx context.Context, payload json.RawMessage) (string, error) {
+			created = append(created, string(payload))
+			return "20", nil
+		}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	git := &memGitClient{files: map[string]string{
+		"config/team.yml":             "dashboards: [10, 11]\n",
+		"data/team/moved.json":        `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"test"}}}`,
+		"data/team/new.json":          `{"type":"dashboard","dashboard":{"dash":{"title":"new"}}}`,
+		"data/team/dashboard-11.json": `{"type":"dashboard","dashboard":{"dash":{"id":11,"title":"test"}}}`,
+	}}
+	gh := &recordingGithubClient{}
+	cfgFile := config.NewUserConfigFile(config.MetaData{Team: "team", FilePath: "/config/team.yml"},
+		map[types.Component][]string{types.ComponentDashboard: {"10", "11"}})
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{cfgFile}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog:             ddog,
+		git:                 git,
+		github:              gh,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	restore, err := c.createFromFiles(context.Background(), 1, []string{"data/team/moved.json", "data/team/new.json"},
+		[]string{"data/team/dashboard-11.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the moved file holds an ID, it is restored instead of creating a copy
+	if fmt.Sprint(restore) != "[data/team/moved.json data/team/dashboard-11.json]" {
+		t.Fatalf("expect the files with an ID to be restored. Got %v", restore)
+	}
+
+	if len(created) != 1 || len(gh.titles) != 1 {
+		t.Fatalf("expect a single dashboard created and a pull request. Got %v, pull requests %v", created, gh.titles)
+	}
+
+	// the modified files are restored even if the added files could not be read
+	restore, err = c.createFromFiles(context.Background(), 1, []string{"data/team/missing.json"}, []string{"data/team/dashboard-11.json"})
+	if err == nil {
+		t.Fatal("expect an error reading a missing file")
+	}
+
+	if fmt.Sprint(restore) != "[data/team/dashboard-11.json]" {
+		t.Fatalf("expect the modified files to be restored. Got %v", restore)
+	}
+}
+
+func TestUserConfigFileForComponent(t *testing.T) {
+	teamDashboards := config.NewUserConfigFile(config.MetaData{Team: "team", FilePath: "/config/team/a.yml"}, nil)
+	teamMonitors := config.NewUserConfigFile(config.MetaData{Team: "team", FilePath: "/config/team/b.yml"},
+		map[types.Component][]string{types.ComponentMonitor: {"1"}})
+	project := config.NewUserConfigFile(config.MetaData{Team: "team", Project: "project", FilePath: "/config/team/project.yml"}, nil)
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{project, teamMonitors, teamDashboards}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+	}
+
+	for _, tc := range []struct {
+		component types.Component
+		file      string
+		expected  *config.UserConfigFile
+	}{
+		{types.ComponentDashboard, "data/team/new-dashboard.json", teamDashboards},
+		{types.ComponentMonitor, "data/team/new-monitor.json", teamMonitors},
+		{types.ComponentMonitor, "data/team/project/monitor.json", project},
+		{types.ComponentMonitor, "data/other/monitor.json", nil},
+	} {
+		cfgFile, err := c.userConfigFileForComponent(tc.component, tc.file)
+		if tc.expected == nil {
+			if err == nil {
+				t.Fatalf("expect error for file %s. Got %s", tc.file, cfgFile.Meta.FilePath)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cfgFile != tc.expected {
+			t.Fatalf("expect config %s for file %s. Got %s", tc.expected.Meta.FilePath, tc.file, cfgFile.Meta.FilePath)
+		}
+	}
+}
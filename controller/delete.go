@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// removedComponent is a datadog component whose file was removed from git.
+type removedComponent struct {
+	file      string
+	component types.Component
+	id        string
+	cfgFile   *config.UserConfigFile
+}
+
+// deleteFromFiles deletes datadog components whose files were removed in a pull request. A component is deleted
+// only if the user config file which owns it sets allowDelete and its ID is no longer listed in any user config.
+// If a pull request removes more components than allowed by the system config, nothing is deleted.
+func (c *Controller) deleteFromFiles(ctx context.Context, prNumber int, removedFiles []string) error {
+	var removed []removedComponent
+	for _, file := range removedFiles {
+		component, id, ok := c.componentFromFilename(file)
+		if !ok {
+			logrus.Infof("Removed file %s is not a component file. Skipping", file)
+			continue
+		}
+
+		cfgFile, err := c.userConfigFileForComponent(component, file)
+		if err != nil || !cfgFile.Meta.AllowDelete {
+			logrus.Infof("Deletion is not allowed for removed file %s. Skipping", file)
+			continue
+		}
+
+		if len(c.cfg.UserConfigFilesByComponentID(component, id)) > 0 {
+			e := c.notificationHandler.AddComment(ctx, notify.NWarning,
+				fmt.Sprintf("File %s was removed, but %s %s is still listed in user config. It was not deleted", file, component, id), "",
+				notify.WithGithubPRComment(prNumber))
+			if e != nil {
+				logrus.Errorf("Error commenting on pull request %d: %s", prNumber, e)
+			}
+			continue
+		}
+
+		removed = append(removed, removedComponent{file, component, id, cfgFile})
+	}
+
+	if len(removed) == 0 {
+		return nil
+	}
+
+	if maxDeletions := c.cfg.GetDatadogMaxDeletions(); len(removed) > maxDeletions {
+		return errors.Errorf("pull request removes %d components, more than the limit of %d. Nothing was deleted:\n%s",
+			len(removed), maxDeletions, removedComponentsList(removed))
+	}
+
+	var errs []string
+	for _, rc := range removed {
+		logrus.Infof("Deleting datadog component %s %s removed in pull request %d", rc.component, rc.id, prNumber)
+
+		var (
+			level notify.NotificationLevel = notify.NSuccess
+			title                          = fmt.Sprintf("Deleted %s %s, file %s was removed", rc.component, rc.id, rc.file)
+		)
+
+		err := c.datadog.Delete(ctx, rc.component, rc.id)
+		if err != nil {
+			errs = append(errs, err.Error())
+			level, title = notify.NError, fmt.Sprintf("Error deleting %s %s, file %s was removed: %s", rc.component, rc.id, rc.file, err)
+		}
+
+		e := c.notificationHandler.AddComment(ctx, level, title, "",
+			notify.WithGithubPRComment(prNumber), notify.WithSlackMessage(rc.cfgFile.Meta.Slack))
+		if e != nil {
+			logrus.Errorf("Error commenting on pull request %d: %s", prNumber, e)
+		}
+	}
+
+	return c.error(errs)
+}
+
+// componentFromFilename returns a component type and ID from a component file name built by Config.ComponentPath.
+func (c *Controller) componentFromFilename(file string) (types.Component, string, bool) {
+	return config.ParseComponentPath(c.datadog.Registry, file)
+}
+
+func removedComponentsList(components []removedComponent) string {
+	var list string
+	for _, rc := range components {
+		list += fmt.Sprintf("- %s `%s` from `%s`\n", rc.component, rc.id, rc.file)
+	}
+
+	return list
+}
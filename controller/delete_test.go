@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestDeleteFromFiles(t *testing.T) {
+	var deleted []string
+	ddog, err := datadog.New("123", "345", nil,
+		datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards", nil, nil, nil,
+			datadog.WithDeleteFn(func(ctx context.Context, id string) error {
+				deleted = append(deleted, id)
+				return nil
+			}))),
+		datadog.WithComponentType(datadog.NewComponentType(types.ComponentUnifiedDashboard, "unifiedDashboards", nil, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := config.NewUserConfigFile(config.MetaData{Team: "team", AllowDelete: true, FilePath: "/config/team.yml"},
+		map[types.Component][]string{types.ComponentDashboard: {"2"}})
+	notAllowed := config.NewUserConfigFile(config.MetaData{Team: "other", FilePath: "/config/other.yml"}, nil)
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{allowed, notAllowed}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog:             ddog,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	err = c.deleteFromFiles(context.Background(), 1, []string{
+		"data/team/dashboard-1.json",
+		"data/team/dashboard-2.json",
+		"data/other/dashboard-3.json",
+		"data/team/new-dashboard.json",
+		"data/team/unified_dashboard-abc-def-ghi.json",
+	})
+	if err == nil {
+		t.Fatal("expect an error deleting a unified dashboard without delete support")
+	}
+
+	if len(deleted) != 1 || deleted[0] != "1" {
+		t.Fatalf("expect dashboard 1 deleted. Got %v", deleted)
+	}
+
+	// the number of deleted components is limited to 2 by the system config
+	deleted = nil
+	err = c.deleteFromFiles(context.Background(), 1, []string{
+		"data/team/dashboard-4.json",
+		"data/team/dashboard-5.json",
+		"data/team/dashboard-6.json",
+	})
+	if err == nil {
+		t.Fatal("expect an error removing more components than allowed")
+	}
+
+	if len(deleted) != 0 {
+		t.Fatalf("expect nothing deleted. Got %v", deleted)
+	}
+
+	err = c.deleteFromFiles(context.Background(), 1, []string{"data/team/dashboard-4.json", "data/team/dashboard-5.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(deleted)
+	if len(deleted) != 2 || deleted[0] != "4" || deleted[1] != "5" {
+		t.Fatalf("expect dashboards 4 and 5 deleted. Got %v", deleted)
+	}
+}
+
+func TestComponentFromFilename(t *testing.T) {
+	ddog, err := datadog.New("123", "345", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Controller{datadog: ddog}
+
+	for _, tc := range []struct {
+		file      string
+		component types.Component
+		id        string
+		ok        bool
+	}{
+		{"data/team/dashboard-123.json", types.ComponentDashboard, "123", true},
+		{"data/team/project/unified_dashboard-abc-def-ghi.json", types.ComponentUnifiedDashboard, "abc-def-ghi", true},
+		{"data/team/log_pipeline-Xyz1aBc2DeF3.json", types.ComponentLogPipeline, "Xyz1aBc2DeF3", true},
+		{"data/team/dashboard-.json", "", "", false},
+		{"data/team/dashboard-123.yml", "", "", false},
+		{"data/team/notebook-1.json", "", "", false},
+	} {
+		component, id, ok := c.componentFromFilename(tc.file)
+		if ok != tc.ok || component != tc.component || id != tc.id {
+			t.Fatalf("expect %s %s %v from %s. Got %s %s %v", tc.component, tc.id, tc.ok, tc.file, component, id, ok)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// HandleDeletedComponent handles a managed component which was deleted in datadog according to the onDelete
+// policy of the user config file. With the remove policy a pull request removing the component file and its ID
+// from the user config is opened. With the recreate policy the component is created again from the git copy and
+// a pull request replacing the ID in the user config is opened. Nothing is done while such pull request is open
+// or once it was closed without merging, the ID is then expected to be removed from the user config by hand.
+// If the pull request could not be opened, the recreated component is reused by the next attempt.
+func (c *Controller) HandleDeletedComponent(ctx context.Context, cfgFile *config.UserConfigFile, component types.Component, id string) error {
+	team, project := cfgFile.Meta.Team, cfgFile.Meta.Project
+	configFile := strings.TrimLeft(cfgFile.Meta.FilePath, "/")
+	policy := cfgFile.Meta.OnDeletePolicy()
+
+	if team == "" {
+		return errors.Errorf("empty team for deleted %s %s", component, id)
+	}
+
+	ct, ok := c.datadog.Registry.Get(component)
+	if !ok {
+		return errors.Errorf("unknown component type %s", component)
+	}
+
+	action := "Remove"
+	if policy == config.OnDeleteRecreate {
+		action = "Recreate"
+	}
+	title := fmt.Sprintf("[Automated PR] %s deleted datadog component owned by [%s] - %s %s %s", action, team, configFile, component, id)
+
+	c.Lock()
+	defer c.Unlock()
+
+	prs, err := c.github.FindPullRequests(ctx, c.cfg.SystemConfig.GitUser(), title)
+	if err != nil {
+		return errors.Wrap(err, "unable to find open PRs")
+	}
+
+	if len(prs) > 0 {
+		logrus.Infof("Pull request %d for deleted %s %s is already open. Skipping", prs[0].Number, component, id)
+		return nil
+	}
+
+	// a user closed the pull request without merging it, the component must not be handled again.
+	closedPRs, err := c.github.FindClosedPullRequests(ctx, c.cfg.SystemConfig.GitUser(), title)
+	if err != nil {
+		return errors.Wrap(err, "unable to find closed PRs")
+	}
+
+	for _, pr := range closedPRs {
+		if !pr.Merged {
+			logrus.Infof("Pull request %d for deleted %s %s was closed without merging. Skipping", pr.Number, component, id)
+			return nil
+		}
+	}
+
+	err = c.git.PullMaster()
+	if err != nil {
+		return errors.Wrap(err, "unable to pull git master")
+	}
+
+	branch := fmt.Sprintf("refs/heads/%s/%d", team, time.Now().UnixNano())
+	err = c.git.CreateBranch(branch)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create branch %s", branch)
+	}
+	defer func() {
+		err = c.git.RemoveBranch(branch)
+		if err != nil {
+			logrus.Errorf("Error removing local branch %s: %s", branch, err)
+		}
+	}()
+
+	err = c.git.Checkout(branch, false, false)
+	if err != nil {
+		return errors.Wrapf(err, "unable to checkout to branch %s", branch)
+	}
+
+	body, err := c.git.ReadFile(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read user config %s", configFile)
+	}
+
+	body, err = config.RemoveComponentIDs(body, ct.ConfigKey(), id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove %s %s from %s", component, id, configFile)
+	}
+
+	filename := c.cfg.ComponentPath(component, team, project, id)
+	_, fileErr := c.git.ReadFile(filename)
+	recreatedKey := fmt.Sprintf("%s/%s", component, id)
+
+	var description string
+	switch policy {
+	case config.OnDeleteRecreate:
+		if fileErr != nil {
+			return errors.Wrapf(fileErr, "unable to recreate %s %s, no component file %s", component, id, filename)
+		}
+
+		newID, ok := c.recreated[recreatedKey]
+		if ok {
+			logrus.Infof("Deleted %s %s was already recreated as %s", component, id, newID)
+		} else {
+			datadogComponent, err := c.readComponentFile(filename)
+			if err != nil {
+				return err
+			}
+
+			newID, err = c.datadog.Create(ctx, datadogComponent)
+			if newID == "" {
+				return errors.Wrapf(err, "unable to recreate %s %s", component, id)
+			}
+
+			if err != nil {
+				logrus.Errorf("Recreated %s %s as %s with errors: %s", component, id, newID, err)
+			}
+
+			if c.recreated == nil {
+				c.recreated = make(map[string]string)
+			}
+			c.recreated[recreatedKey] = newID
+		}
+
+		body, err = config.AddComponentIDs(body, ct.ConfigKey(), newID)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add %s %s to %s", component, newID, configFile)
+		}
+
+		err = c.git.Remove(filename)
+		if err != nil {
+			return errors.Wrapf(err, "unable to remove a file %s", filename)
+		}
+
+		err = c.addFiles(ctx, team, project, component, []string{newID})
+		if err != nil {
+			return err
+		}
+
+		description = fmt.Sprintf("%s `%s` was deleted in datadog and has been recreated from `%s` as `%s`.\n\n", component, id, filename, newID)
+		description += "Merge this PR to replace the ID in the user config. :warning: **Closing this PR leaves the recreated component unmanaged!!!**"
+	default:
+		if fileErr == nil {
+			err = c.git.Remove(filename)
+			if err != nil {
+				return errors.Wrapf(err, "unable to remove a file %s", filename)
+			}
+		}
+
+		description = fmt.Sprintf("%s `%s` was deleted in datadog. Merge this PR to remove `%s` and the ID from the user config.\n\n", component, id, filename)
+		description += fmt.Sprintf("Set `onDelete: %s` in the user config meta to recreate deleted components instead.", config.OnDeleteRecreate)
+	}
+
+	err = c.git.NewFile(configFile, body)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write user config %s", configFile)
+	}
+
+	err = c.git.Add(configFile)
+	if err != nil {
+		return errors.Wrapf(err, "unable to add a file %s to a commit", configFile)
+	}
+
+	msg, commitHash, err := c.git.Commit(fmt.Sprintf("%s deleted %s %s", action, component, id))
+	if err != nil {
+		return errors.Wrap(err, "unable to make a new commit")
+	}
+
+	logrus.Debugf("A new commit created %s\n%s", commitHash, msg)
+
+	err = c.git.Push(branch)
+	if err != nil {
+		return errors.Wrapf(err, "unable to push changes to remote branch %s", branch)
+	}
+
+	if bodyExtra := c.cfg.PullRequestBodyExtra(); bodyExtra != "" {
+		description += "\n\n" + bodyExtra
+	}
+
+	newPRNumber, err := c.createNewPullRequest(ctx, title, branch, "master", description)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create a new pull request")
+	}
+	delete(c.recreated, recreatedKey)
+
+	c.assignPullRequest(ctx, newPRNumber, cfgFile.Meta, []string{configFile, filename})
+
+	e := c.notificationHandler.AddComment(ctx, notify.NWarning,
+		fmt.Sprintf("%s %s was deleted in datadog. A new pull request https://%s/%s/%s/pull/%d has been created",
+			component, id, c.cfg.GetGithubBaseURL(), c.cfg.GetGithubProjectOwner(), c.cfg.GetGithubRepo(), newPRNumber),
+		"", notify.WithSlackMessage(cfgFile.Meta.Slack))
+	if e != nil {
+		logrus.Errorf("Error adding a notification: %s", e)
+	}
+
+	return nil
+}
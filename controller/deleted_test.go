@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"github.com/coinbase/watchdog/primitives/github"
+
+	"github.com/pkg/errors"
+)
+
+// memGitClient keeps the workspace files in memory.
+type memGitClient struct {
+	fakeGitClient
+	files map[string]string
+}
+
+func (g *memGitClient) NewFile(name string, body []byte) error {
+	g.files[name] = string(body)
+	return nil
+}
+
+func (g *memGitClient) ReadFile(path string) ([]byte, error) {
+	body, ok := g.files[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return []byte(body), nil
+}
+
+func (g *memGitClient) Remove(path string) error {
+	delete(g.files, path)
+	return nil
+}
+
+// recordingGithubClient records created pull requests, requested reviewers and labels.
+type recordingGithubClient struct {
+	fakeGithubClient
+	titles        []string
+	bodies        []string
+	reviewers     []string
+	teamReviewers []string
+	labels        []string
+	open          []*github.PullRequest
+	closedPRs     []*github.PullRequest
+}
+
+func (g *recordingGithubClient) CreatePullRequest(ctx context.Context, title, head, base, body string) (string, int, error) {
+	g.titles = append(g.titles, title)
+	g.bodies = append(g.bodies, body)
+	return "", len(g.titles), nil
+}
+
+func (g *recordingGithubClient) FindClosedPullRequests(ctx context.Context, owner, titleMatch string) ([]*github.PullRequest, error) {
+	return g.closedPRs, nil
+}
+
+func (g *recordingGithubClient) RequestReviewers(ctx context.Context, pr int, reviewers, teamReviewers []string) error {
+	g.reviewers = append(g.reviewers, reviewers...)
+	g.teamReviewers = append(g.teamReviewers, teamReviewers...)
+	return nil
+}
+
+func (g *recordingGithubClient) AddLabels(ctx context.Context, pr int, labels []string) error {
+	g.labels = append(g.labels, labels...)
+	return nil
+}
+
+func (g *recordingGithubClient) FindPullRequests(ctx context.Context, owner, titleMatch string) ([]*github.PullRequest, error) {
+	return g.open, nil
+}
+
+// failingPushGitClient fails to push until fail is unset.
+type failingPushGitClient struct {
+	*memGitClient
+	fail bool
+}
+
+func (g *failingPushGitClient) Push(branches ...string) error {
+	if g.fail {
+		return errors.New("push failed")
+	}
+
+	return nil
+}
+
+func TestHandleDeletedComponentRecreateOnce(t *testing.T) {
+	var created int
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"dash":{"id":` + id + `,"title":"test"}}`), nil
+		}, nil, nil,
+		datadog.WithCreateFn(func(json.RawMessage) (string, error) { return "", nil },
+			func(ctx context.Context, payload json.RawMessage) (string, error) {
+				created++
+				return fmt.Sprint(19 + created), nil
+			}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	git := &failingPushGitClient{memGitClient: &memGitClient{files: map[string]string{
+		"config/team.yml":             "dashboards: [10, 11]\n",
+		"data/team/dashboard-10.json": `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"test"}}}`,
+	}}, fail: true}
+	gh := &recordingGithubClient{}
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog:             ddog,
+		git:                 git,
+		github:              gh,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	cfgFile := config.NewUserConfigFile(config.MetaData{Team: "team", OnDelete: config.OnDeleteRecreate, FilePath: "/config/team.yml"}, nil)
+	if err := c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10"); err == nil {
+		t.Fatal("expect an error pushing the branch")
+	}
+
+	// the workspace is reset to master before the next attempt
+	git.files = map[string]string{
+		"config/team.yml":             "dashboards: [10, 11]\n",
+		"data/team/dashboard-10.json": `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"test"}}}`,
+	}
+	git.fail = false
+	if err := c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10"); err != nil {
+		t.Fatal(err)
+	}
+
+	if created != 1 {
+		t.Fatalf("expect the dashboard to be recreated once. Got %d", created)
+	}
+
+	if git.files["config/team.yml"] != "dashboards: [11, 20]\n" || len(gh.titles) != 1 {
+		t.Fatalf("expect a pull request replacing the ID with 20. Got %q, pull requests %v", git.files["config/team.yml"], gh.titles)
+	}
+}
+
+func TestHandleDeletedComponent(t *testing.T) {
+	var created []string
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"dash":{"id":` + id + `,"title":"test"}}`), nil
+		}, nil, nil,
+		datadog.WithCreateFn(func(json.RawMessage) (string, error) { return "", nil },
+			func(ctx context.Context, payload json.RawMessage) (string, error) {
+				created = append(created, string(payload))
+				return "20", nil
+			}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		policy        string
+		expectedFiles []string
+		expectedTitle string
+		expectedCfg   string
+	}{
+		{
+			policy:        "",
+			expectedFiles: []string{"config/team.yml"},
+			expectedTitle: "[Automated PR] Remove deleted datadog component owned by [team] - config/team.yml dashboard 10",
+			expectedCfg:   "dashboards: [11]\n",
+		},
+		{
+			policy:        config.OnDeleteRecreate,
+			expectedFiles: []string{"config/team.yml", "data/team/dashboard-20.json"},
+			expectedTitle: "[Automated PR] Recreate deleted datadog component owned by [team] - config/team.yml dashboard 10",
+			expectedCfg:   "dashboards: [11, 20]\n",
+		},
+	} {
+		created = nil
+		git := &memGitClient{files: map[string]string{
+			"config/team.yml":             "dashboards: [10, 11]\n",
+			"data/team/dashboard-10.json": `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"test"}}}`,
+		}}
+		gh := &recordingGithubClient{}
+
+		c := &Controller{
+			cfg: &config.Config{
+				UserConfig:   &fakeUserConfig{},
+				SystemConfig: &fakeSystemsConfig{},
+			},
+			datadog:             ddog,
+			git:                 git,
+			github:              gh,
+			notificationHandler: notify.NewHandler(),
+		}
+
+		cfgFile := config.NewUserConfigFile(config.MetaData{Team: "team", OnDelete: tc.policy, FilePath: "/config/team.yml"}, nil)
+		err := c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(git.files) != len(tc.expectedFiles) {
+			t.Fatalf("expect files %v. Got %v", tc.expectedFiles, git.files)
+		}
+
+		for _, file := range tc.expectedFiles {
+			if _, ok := git.files[file]; !ok {
+				t.Fatalf("expect file %s. Got %v", file, git.files)
+			}
+		}
+
+		if git.files["config/team.yml"] != tc.expectedCfg {
+			t.Fatalf("expect config %q. Got %q", tc.expectedCfg, git.files["config/team.yml"])
+		}
+
+		if len(gh.titles) != 1 || gh.titles[0] != tc.expectedTitle {
+			t.Fatalf("expect pull request %s. Got %v", tc.expectedTitle, gh.titles)
+		}
+
+		if tc.policy == config.OnDeleteRecreate && (len(created) != 1 || !strings.Contains(created[0], `"title":"test"`)) {
+			t.Fatalf("expect dashboard recreated from git. Got %v", created)
+		}
+
+		// nothing is done while a pull request is open
+		gh.open = []*github.PullRequest{{Number: 1}}
+		err = c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gh.titles) != 1 {
+			t.Fatalf("expect no new pull request. Got %v", gh.titles)
+		}
+
+		// nothing is done once the pull request was closed without merging
+		gh.open = nil
+		gh.closedPRs = []*github.PullRequest{{Number: 1}}
+		created = nil
+		err = c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gh.titles) != 1 || len(created) != 0 {
+			t.Fatalf("expect no new pull request and component. Got %v, created %v", gh.titles, created)
+		}
+
+		// a merged pull request does not stop handling the component, e.g. if its ID was added back
+		gh.closedPRs = []*github.PullRequest{{Number: 1, Merged: true}}
+		git.files["config/team.yml"] = "dashboards: [10, 11]\n"
+		git.files["data/team/dashboard-10.json"] = `{"type":"dashboard","dashboard":{"dash":{"id":10,"title":"test"}}}`
+		err = c.HandleDeletedComponent(context.Background(), cfgFile, types.ComponentDashboard, "10")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gh.titles) != 2 {
+			t.Fatalf("expect a new pull request. Got %v", gh.titles)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxDiscoverySlackItems is a max number of unmanaged components listed in a slack summary.
+const maxDiscoverySlackItems = 20
+
+// DiscoveryReport lists the datadog components of the org, managed or not.
+type DiscoveryReport struct {
+	Components []DiscoveredComponent `json:"components"`
+
+	// Errors are the component types which could not be listed.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// DiscoveredComponent is a datadog component found by the discovery. A component is managed
+// if it is listed in at least one user config file.
+type DiscoveredComponent struct {
+	Component types.Component  `json:"component"`
+	ID        string           `json:"id"`
+	Title     string           `json:"title"`
+	Creator   string           `json:"creator"`
+	Tags      []string         `json:"tags"`
+	Managed   bool             `json:"managed"`
+	Owners    []ComponentOwner `json:"owners,omitempty"`
+}
+
+// ComponentOwner is a user config file which manages a component.
+type ComponentOwner struct {
+	Team       string `json:"team"`
+	Project    string `json:"project,omitempty"`
+	ConfigFile string `json:"configFile"`
+}
+
+// Discover lists every component in datadog of the component types which could be listed and marks
+// each one as managed, with the owning teams and user config files, or unmanaged.
+func (c *Controller) Discover(ctx context.Context) (*DiscoveryReport, error) {
+	report := &DiscoveryReport{Components: []DiscoveredComponent{}}
+
+	for _, ct := range c.datadog.Registry.ComponentTypes() {
+		selectable, ok := ct.(types.Selectable)
+		if !ok {
+			continue
+		}
+
+		summaries, err := selectable.List(ctx)
+		if err == datadog.ErrSelectNotSupported {
+			continue
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, errors.Wrapf(err, "unable to list %s", ct.Type()).Error())
+			continue
+		}
+
+		for _, summary := range summaries {
+			discovered := DiscoveredComponent{
+				Component: ct.Type(),
+				ID:        summary.ID,
+				Title:     summary.Title,
+				Creator:   summary.Creator,
+				Tags:      summary.Tags,
+			}
+
+			for _, cfgFile := range c.cfg.UserConfigFilesByComponentID(ct.Type(), summary.ID) {
+				discovered.Owners = append(discovered.Owners, ComponentOwner{
+					Team:       cfgFile.Meta.Team,
+					Project:    cfgFile.Meta.Project,
+					ConfigFile: strings.TrimLeft(cfgFile.Meta.FilePath, "/"),
+				})
+			}
+			discovered.Managed = len(discovered.Owners) > 0
+
+			report.Components = append(report.Components, discovered)
+		}
+	}
+
+	if len(report.Components) == 0 && len(report.Errors) > 0 {
+		return nil, errors.New(strings.Join(report.Errors, "; "))
+	}
+
+	sort.SliceStable(report.Components, func(i, j int) bool {
+		if report.Components[i].Component != report.Components[j].Component {
+			return report.Components[i].Component < report.Components[j].Component
+		}
+
+		return report.Components[i].ID < report.Components[j].ID
+	})
+
+	return report, nil
+}
+
+// WriteCSV writes the report as CSV, one line per component. Tags are separated by spaces,
+// teams and config files of several owners by semicolons.
+func (r *DiscoveryReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"component", "id", "title", "creator", "tags", "managed", "teams", "config_files"})
+	if err != nil {
+		return errors.Wrap(err, "unable to write csv header")
+	}
+
+	for _, dc := range r.Components {
+		var teams, configFiles []string
+		for _, owner := range dc.Owners {
+			teams = append(teams, owner.Team)
+			configFiles = append(configFiles, owner.ConfigFile)
+		}
+
+		err := cw.Write([]string{string(dc.Component), dc.ID, dc.Title, dc.Creator, strings.Join(dc.Tags, " "),
+			strconv.FormatBool(dc.Managed), strings.Join(teams, ";"), strings.Join(configFiles, ";")})
+		if err != nil {
+			return errors.Wrapf(err, "unable to write csv line for %s %s", dc.Component, dc.ID)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// TeamSummary returns the number of components managed by a team and the unmanaged components
+// tagged with team:<team>, which most likely belong to the team.
+func (r *DiscoveryReport) TeamSummary(team string) (int, []DiscoveredComponent) {
+	var (
+		managed   int
+		unmanaged []DiscoveredComponent
+		teamTag   = "team:" + team
+	)
+
+	for _, dc := range r.Components {
+		if !dc.Managed {
+			for _, tag := range dc.Tags {
+				if tag == teamTag {
+					unmanaged = append(unmanaged, dc)
+					break
+				}
+			}
+			continue
+		}
+
+		for _, owner := range dc.Owners {
+			if owner.Team == team {
+				managed++
+				break
+			}
+		}
+	}
+
+	return managed, unmanaged
+}
+
+// ReportDiscovery sends a discovery summary to the slack channel of every team in user config.
+func (c *Controller) ReportDiscovery(ctx context.Context) error {
+	report, err := c.Discover(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to discover datadog components")
+	}
+
+	var (
+		errs []string
+		sent = make(map[string]bool)
+	)
+	for _, cfgFile := range c.cfg.UserConfigFiles() {
+		team, channel := cfgFile.Meta.Team, cfgFile.Meta.Slack
+		if team == "" || channel == "" || sent[team+"/"+channel] {
+			continue
+		}
+		sent[team+"/"+channel] = true
+
+		managed, unmanaged := report.TeamSummary(team)
+
+		var (
+			level notify.NotificationLevel = notify.NSuccess
+			title                          = fmt.Sprintf("Datadog discovery for team [%s]: %d managed components, no unmanaged components tagged team:%s", team, managed, team)
+		)
+
+		body := discoveredComponentsList(unmanaged, maxDiscoverySlackItems)
+		if len(unmanaged) > 0 {
+			level = notify.NInfo
+			title = fmt.Sprintf("Datadog discovery for team [%s]: %d managed components, %d unmanaged components tagged team:%s",
+				team, managed, len(unmanaged), team)
+		}
+
+		e := c.notificationHandler.AddComment(ctx, level, title, body, notify.WithSlackMessage(channel))
+		if e != nil {
+			errs = append(errs, e.Error())
+		}
+	}
+
+	return c.error(errs)
+}
+
+// reportDiscoveryPeriodically sends the discovery summary to team channels every interval.
+func (c *Controller) reportDiscoveryPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.ReportDiscovery(ctx); err != nil {
+				logrus.Errorf("Error reporting discovered datadog components: %s", err)
+			}
+		}
+	}
+}
+
+func discoveredComponentsList(components []DiscoveredComponent, max int) string {
+	var list string
+	for i, dc := range components {
+		if i == max {
+			list += fmt.Sprintf("... and %d more\n", len(components)-max)
+			break
+		}
+
+		list += fmt.Sprintf("- %s `%s` %s", dc.Component, dc.ID, dc.Title)
+		if dc.Creator != "" {
+			list += fmt.Sprintf(" (created by %s)", dc.Creator)
+		}
+		list += "\n"
+	}
+
+	return list
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestDiscover(t *testing.T) {
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", nil, nil, nil,
+			datadog.WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				return []types.Summary{
+					{ID: "2", Title: "disk", Tags: []string{"team:payments"}, Creator: "jane"},
+					{ID: "1", Title: "cpu", Tags: []string{"team:payments", "env:prod"}, Creator: "joe"},
+					{ID: "3", Title: "memory", Tags: []string{"team:other"}},
+				}, nil
+			})),
+		// dashboards could not be listed, they are not reported
+		datadog.NewComponentType(types.ComponentDashboard, "dashboards", nil, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", nil, datadog.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfgFile := config.NewUserConfigFile(config.MetaData{Team: "payments", FilePath: "/config/payments.yml"},
+		map[types.Component][]string{types.ComponentMonitor: {"1"}})
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{cfgFile}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog: ddog,
+	}
+
+	report, err := c.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Components) != 3 {
+		t.Fatalf("expect 3 discovered monitors. Got %+v", report.Components)
+	}
+
+	managed := report.Components[0]
+	if managed.ID != "1" || !managed.Managed || len(managed.Owners) != 1 || managed.Owners[0].Team != "payments" ||
+		managed.Owners[0].ConfigFile != "config/payments.yml" {
+		t.Fatalf("expect monitor 1 managed by payments. Got %+v", managed)
+	}
+
+	if unmanaged := report.Components[1]; unmanaged.ID != "2" || unmanaged.Managed || unmanaged.Creator != "jane" {
+		t.Fatalf("expect monitor 2 unmanaged, created by jane. Got %+v", unmanaged)
+	}
+
+	count, teamUnmanaged := report.TeamSummary("payments")
+	if count != 1 || len(teamUnmanaged) != 1 || teamUnmanaged[0].ID != "2" {
+		t.Fatalf("expect 1 managed and unmanaged monitor 2 for payments. Got %d %+v", count, teamUnmanaged)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := report.WriteCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expect a header and 3 lines. Got %s", buf.String())
+	}
+
+	if lines[1] != "monitor,1,cpu,joe,team:payments env:prod,true,payments,config/payments.yml" {
+		t.Fatalf("expect a managed monitor line. Got %s", lines[1])
+	}
+}
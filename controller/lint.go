@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/lint"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/github"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// lintStatusContext is a name of the commit status set on pull requests.
+	lintStatusContext = "watchdog/lint"
+
+	// validationCheckName is a name of the check run which validates changed components in datadog.
+	validationCheckName = "watchdog/validation"
+
+	statusSuccess = "success"
+	statusFailure = "failure"
+)
+
+// fieldPattern matches a quoted field name in a datadog validation error,
+// e.g. The value provided for parameter 'query' is invalid.
+var fieldPattern = regexp.MustCompile(`'([\w.]+)'`)
+
+// ValidatePullRequest lints the resources repo as it would be after merging a pull request and sets the
+// watchdog/lint status of the head commit. The files of master are read from the workspace and the files
+// changed in the pull request are read from github at the given SHA. The changed components are validated
+// in datadog, the errors are posted as review comments and fail the watchdog/validation check.
+func (c *Controller) ValidatePullRequest(ctx context.Context, prNumber int, branch, sha string) error {
+	files, changed, err := c.pullRequestTree(ctx, prNumber, sha)
+	if err != nil {
+		return err
+	}
+
+	if err := c.lintPullRequest(ctx, prNumber, sha, files); err != nil {
+		return err
+	}
+
+	return c.validatePullRequest(ctx, prNumber, branch, sha, files, changed)
+}
+
+// pullRequestTree applies the files changed in a pull request to the files of master. The changed files
+// which are still present after the merge are returned as well.
+func (c *Controller) pullRequestTree(ctx context.Context, prNumber int, sha string) (map[string][]byte, []string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.git.PullMaster(); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to pull master")
+	}
+
+	var (
+		configPath = strings.Trim(c.cfg.GetUserConfigBasePath(), "/")
+		dataPath   = strings.Trim(c.cfg.GetDatadogDataPath(), "/")
+	)
+
+	files, err := lint.ReadFiles(c.git.ReadDir, c.git.ReadFile, configPath, dataPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	created, removed, modified, err := c.github.PullRequestFiles(ctx, prNumber)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to get files of pull request %d", prNumber)
+	}
+
+	for _, file := range removed {
+		delete(files, file)
+	}
+
+	var changed []string
+	for _, file := range append(created, modified...) {
+		if !strings.HasPrefix(file, configPath+"/") && !strings.HasPrefix(file, dataPath+"/") {
+			continue
+		}
+
+		body, err := c.github.GetFileContent(ctx, file, sha)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[file] = body
+		changed = append(changed, file)
+	}
+
+	return files, changed, nil
+}
+
+// lintPullRequest lints the files of a pull request and sets the watchdog/lint status.
+func (c *Controller) lintPullRequest(ctx context.Context, prNumber int, sha string, files map[string][]byte) error {
+	problems := lint.New(c.datadog.Registry, c.cfg.GetUserConfigBasePath(), c.cfg.GetDatadogDataPath()).Lint(files)
+
+	var (
+		state       = statusSuccess
+		description = "No problems found"
+	)
+	if len(problems) > 0 {
+		state = statusFailure
+		description = fmt.Sprintf("%d problems found", len(problems))
+	}
+
+	logrus.Infof("Pull request %d at %s: %s", prNumber, sha, description)
+	if err := c.github.CreateCommitStatus(ctx, sha, state, lintStatusContext, description); err != nil {
+		return errors.Wrapf(err, "unable to set status of pull request %d", prNumber)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, problem := range problems {
+		lines = append(lines, "- "+problem.String())
+	}
+
+	title := fmt.Sprintf("Pull request %d has %s", prNumber, description)
+	err := c.notificationHandler.AddComment(ctx, notify.NError, title, strings.Join(lines, "\n"), notify.WithGithubPRComment(prNumber))
+	if err != nil {
+		logrus.Errorf("Error commenting on pull request %d: %s", prNumber, err)
+	}
+
+	return nil
+}
+
+// validatePullRequest validates the changed component files in datadog and creates the watchdog/validation
+// check run. The component types which could not be validated, e.g. dashboards, are skipped.
+func (c *Controller) validatePullRequest(ctx context.Context, prNumber int, branch, sha string, files map[string][]byte, changed []string) error {
+	var (
+		validated int
+		comments  []github.ReviewComment
+		errs      []string
+	)
+	for _, file := range changed {
+		if !strings.HasPrefix(file, strings.Trim(c.cfg.GetDatadogDataPath(), "/")+"/") {
+			continue
+		}
+
+		// invalid component files are reported by the lint.
+		component := &datadog.Component{}
+		if err := json.Unmarshal(files[file], component); err != nil {
+			continue
+		}
+
+		validationErrs, err := c.datadog.Validate(ctx, component)
+		if errors.Cause(err) == datadog.ErrValidateNotSupported || errors.Cause(err) == datadog.ErrInvalidComponentTypeID {
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "unable to validate %s", file).Error())
+			continue
+		}
+
+		validated++
+		for _, validationErr := range validationErrs {
+			comments = append(comments, github.ReviewComment{
+				Path: file,
+				Line: fieldLine(files[file], validationErr),
+				Body: fmt.Sprintf("Datadog rejected the %s: %s", component.Type, validationErr),
+			})
+		}
+	}
+
+	// datadog could not be reached, the check is not created so it could be re-run by updating the pull request.
+	if err := c.error(errs); err != nil {
+		return err
+	}
+
+	run := github.CheckRun{
+		Name:       validationCheckName,
+		HeadBranch: branch,
+		HeadSHA:    sha,
+		Conclusion: statusSuccess,
+		Title:      fmt.Sprintf("%d components are valid", validated),
+	}
+
+	if len(comments) > 0 {
+		var lines []string
+		for _, comment := range comments {
+			lines = append(lines, fmt.Sprintf("- %s line %d: %s", comment.Path, comment.Line, comment.Body))
+		}
+
+		run.Conclusion = statusFailure
+		run.Title = fmt.Sprintf("%d errors found by datadog", len(comments))
+		run.Summary = strings.Join(lines, "\n")
+
+		body := "Datadog rejected the changed components, the pull request could not be applied after merge."
+		if err := c.github.CreateReview(ctx, prNumber, sha, body, comments); err != nil {
+			logrus.Errorf("Error reviewing pull request %d: %s", prNumber, err)
+		}
+	}
+
+	logrus.Infof("Pull request %d at %s: %s", prNumber, sha, run.Title)
+	if err := c.github.CreateCheckRun(ctx, run); err != nil {
+		return errors.Wrapf(err, "unable to create check run of pull request %d", prNumber)
+	}
+
+	return nil
+}
+
+// fieldLine returns the line of a component file which holds the field named in a validation error.
+// The first line is returned if the field is not found.
+func fieldLine(body []byte, validationErr string) int {
+	match := fieldPattern.FindStringSubmatch(validationErr)
+	if match == nil {
+		return 1
+	}
+
+	// nested fields like options.thresholds are matched by the last key.
+	keys := strings.Split(match[1], ".")
+	key := fmt.Sprintf("%q", keys[len(keys)-1])
+
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		if bytes.HasPrefix(bytes.TrimSpace(line), []byte(key)) {
+			return i + 1
+		}
+	}
+
+	return 1
+}
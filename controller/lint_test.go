@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/controller/notify"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"github.com/coinbase/watchdog/primitives/github"
+)
+
+// lintGithubClient serves the files of a pull request and records commit statuses.
+type lintGithubClient struct {
+	fakeGithubClient
+	created  []string
+	removed  []string
+	files    map[string]string
+	statuses []string
+	runs     []github.CheckRun
+	comments []github.ReviewComment
+}
+
+func (g *lintGithubClient) PullRequestFiles(ctx context.Context, number int) ([]string, []string, []string, error) {
+	return g.created, g.removed, nil, nil
+}
+
+func (g *lintGithubClient) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	body, ok := g.files[path]
+	if !ok || ref != "abc" {
+		return nil, os.ErrNotExist
+	}
+
+	return []byte(body), nil
+}
+
+func (g *lintGithubClient) CreateCommitStatus(ctx context.Context, sha, state, statusContext, description string) error {
+	g.statuses = append(g.statuses, strings.Join([]string{sha, state, statusContext, description}, " "))
+	return nil
+}
+
+func (g *lintGithubClient) CreateCheckRun(ctx context.Context, run github.CheckRun) error {
+	g.runs = append(g.runs, run)
+	return nil
+}
+
+func (g *lintGithubClient) CreateReview(ctx context.Context, number int, sha, body string, comments []github.ReviewComment) error {
+	g.comments = append(g.comments, comments...)
+	return nil
+}
+
+func TestValidatePullRequest(t *testing.T) {
+	registry, err := types.NewRegistry(datadog.NewComponentType(types.ComponentDashboard, "dashboards", nil, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", nil, datadog.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		created        []string
+		files          map[string]string
+		expectedStatus string
+	}{
+		{
+			created: []string{"config/team.yml", "data/team/dashboard-1.json", "README.md"},
+			files: map[string]string{
+				"config/team.yml":            "meta:\n  team: team\ndashboards: [1]\n",
+				"data/team/dashboard-1.json": `{"type":"dashboard","dashboard":{"id":1}}`,
+			},
+			expectedStatus: "abc success watchdog/lint No problems found",
+		},
+		{
+			created: []string{"config/team.yml", "data/other/dashboard-1.json"},
+			files: map[string]string{
+				"config/team.yml":             "meta:\n  team: team\ndashboards: [1]\n",
+				"data/other/dashboard-1.json": `{"type":"dashboard","dashboard":{"id":1}}`,
+			},
+			expectedStatus: "abc failure watchdog/lint 2 problems found",
+		},
+	} {
+		gh := &lintGithubClient{created: tc.created, files: tc.files}
+		c := &Controller{
+			cfg: &config.Config{
+				UserConfig:   &fakeUserConfig{},
+				SystemConfig: &fakeSystemsConfig{},
+			},
+			datadog:             ddog,
+			git:                 &memGitClient{files: map[string]string{}},
+			github:              gh,
+			notificationHandler: notify.NewHandler(),
+		}
+
+		if err := c.ValidatePullRequest(context.Background(), 1, "branch", "abc"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gh.statuses) != 1 || gh.statuses[0] != tc.expectedStatus {
+			t.Fatalf("expect status %q. Got %v", tc.expectedStatus, gh.statuses)
+		}
+
+		if len(gh.runs) != 1 || gh.runs[0].Conclusion != "success" || gh.runs[0].HeadBranch != "branch" {
+			t.Fatalf("expect successful check run. Got %+v", gh.runs)
+		}
+	}
+}
+
+func TestValidatePullRequestMonitors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/monitor/validate" {
+			t.Fatalf("expect URL /api/v1/monitor/validate. Got %s", r.URL.Path)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(string(body), "invalid") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":["The value provided for parameter 'query' is invalid"]}`)
+			return
+		}
+
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	ddClient, err := client.New("123", "345", client.WithBaseURL(ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", ddClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gh := &lintGithubClient{
+		created: []string{"config/team.yml", "data/team/monitor-1.json", "data/team/monitor-2.json", "data/team/dashboard-3.json"},
+		files: map[string]string{
+			"config/team.yml":            "meta:\n  team: team\nmonitors: [1, 2]\ndashboards: [3]\n",
+			"data/team/monitor-1.json":   "{\n  \"type\": \"monitor\",\n  \"monitor\": {\n    \"monitor\": {\n      \"id\": 1,\n      \"query\": \"avg(last_5m):avg:system.load.1{*} > 1\"\n    }\n  }\n}",
+			"data/team/monitor-2.json":   "{\n  \"type\": \"monitor\",\n  \"monitor\": {\n    \"monitor\": {\n      \"id\": 2,\n      \"query\": \"invalid\"\n    }\n  }\n}",
+			"data/team/dashboard-3.json": `{"type":"dashboard","dashboard":{"dash":{"id":3}}}`,
+		},
+	}
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog:             ddog,
+		git:                 &memGitClient{files: map[string]string{}},
+		github:              gh,
+		notificationHandler: notify.NewHandler(),
+	}
+
+	if err := c.ValidatePullRequest(context.Background(), 1, "branch", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(gh.runs) != 1 || gh.runs[0].Conclusion != "failure" || gh.runs[0].Name != "watchdog/validation" {
+		t.Fatalf("expect failed watchdog/validation check run. Got %+v", gh.runs)
+	}
+
+	if len(gh.comments) != 1 {
+		t.Fatalf("expect 1 review comment. Got %+v", gh.comments)
+	}
+
+	if comment := gh.comments[0]; comment.Path != "data/team/monitor-2.json" || comment.Line != 6 ||
+		!strings.Contains(comment.Body, "parameter 'query' is invalid") {
+		t.Fatalf("expect a comment on the query of monitor 2. Got %+v", comment)
+	}
+}
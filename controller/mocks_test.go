@@ -13,26 +13,40 @@ import (
 
 // mock user config impl.
 type fakeUserConfig struct {
+	userConfigFiles []*config.UserConfigFile
 }
 
-func (c fakeUserConfig) UserConfigFilesByComponentID(component types.Component, id int) []*config.UserConfigFile {
-	return nil
+func (c fakeUserConfig) UserConfigFilesByComponentID(component types.Component, id string) []*config.UserConfigFile {
+	var files []*config.UserConfigFile
+	for _, cfgFile := range c.userConfigFiles {
+		for _, listedID := range cfgFile.Components()[component] {
+			if listedID == id {
+				files = append(files, cfgFile)
+			}
+		}
+	}
+
+	return files
 }
 
-func (c fakeUserConfig) Reload() error {
+func (c fakeUserConfig) Reload(ctx context.Context) error {
 	return nil
 }
 
+func (c fakeUserConfig) RefreshSelectors(ctx context.Context) (map[*config.UserConfigFile]map[types.Component][]string, error) {
+	return nil, nil
+}
+
 func (c fakeUserConfig) GetUserConfigBasePath() string {
 	return "config"
 }
 
 func (c fakeUserConfig) UserConfigFiles() []*config.UserConfigFile {
-	return nil
+	return c.userConfigFiles
 }
 
 func (c fakeUserConfig) UserConfigFromFile(path string, a bool) (*config.UserConfigFile, error) {
-	return nil, nil
+	return config.NewUserConfigFile(config.MetaData{FilePath: path}, nil), nil
 }
 
 // mock git impl
@@ -87,6 +101,10 @@ func (g fakeGitClient) Add(path string) error {
 	return nil
 }
 
+func (g fakeGitClient) Remove(path string) error {
+	return nil
+}
+
 func (g fakeGitClient) Commit(msg string) (string, string, error) {
 	return "", "", nil
 }
@@ -121,7 +139,19 @@ func (g fakeGithubClient) FindPullRequests(ctx context.Context, owner, titleMatc
 	return nil, nil
 }
 
-func (g fakeGithubClient) RequestReviewers(pr int, names []string) error {
+func (g fakeGithubClient) FindClosedPullRequests(ctx context.Context, owner, titleMatch string) (prs []*github.PullRequest, err error) {
+	return nil, nil
+}
+
+func (g fakeGithubClient) RequestReviewers(ctx context.Context, pr int, reviewers, teamReviewers []string) error {
+	return nil
+}
+
+func (g fakeGithubClient) AddLabels(ctx context.Context, pr int, labels []string) error {
+	return nil
+}
+
+func (g fakeGithubClient) EditPullRequest(ctx context.Context, number int, title, body string) error {
 	return nil
 }
 
@@ -129,6 +159,22 @@ func (g fakeGithubClient) RemoveRemoveRef(ctx context.Context, ref string) error
 	return nil
 }
 
+func (g fakeGithubClient) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	return nil, nil
+}
+
+func (g fakeGithubClient) CreateCommitStatus(ctx context.Context, sha, state, statusContext, description string) error {
+	return nil
+}
+
+func (g fakeGithubClient) CreateCheckRun(ctx context.Context, run github.CheckRun) error {
+	return nil
+}
+
+func (g fakeGithubClient) CreateReview(ctx context.Context, number int, sha, body string, comments []github.ReviewComment) error {
+	return nil
+}
+
 func (g fakeGithubClient) CreatePullRequestComment(ctx context.Context, id int, text string) error {
 	return nil
 }
@@ -152,10 +198,62 @@ func (f fakeSystemsConfig) GetDatadogPollingScheduler() string {
 	return ""
 }
 
+func (f fakeSystemsConfig) GetDatadogPollingStatePath() string {
+	return ""
+}
+
 func (f fakeSystemsConfig) GetDatadogPollingInterval() time.Duration {
 	return time.Second
 }
 
+func (f fakeSystemsConfig) GetDatadogPollingHashInterval() time.Duration {
+	return time.Second
+}
+
+func (f fakeSystemsConfig) GetDatadogSite() string {
+	return "US1"
+}
+
+func (f fakeSystemsConfig) GetDatadogAPIURL() string {
+	return ""
+}
+
+func (f fakeSystemsConfig) GetDatadogRequestTimeout() time.Duration {
+	return time.Second
+}
+
+func (f fakeSystemsConfig) GetDatadogMaxDeletions() int {
+	return 2
+}
+
+func (f fakeSystemsConfig) GetDatadogDeletedCheckInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogSelectorRefreshInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogDiscoveryInterval() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogQuietPeriod() time.Duration {
+	return 0
+}
+
+func (f fakeSystemsConfig) GetDatadogQuietPeriodPerTeam() bool {
+	return false
+}
+
+func (f fakeSystemsConfig) GetGithubReviewer(datadogUser string) string {
+	return ""
+}
+
+func (f fakeSystemsConfig) GetGithubUpdatePullRequests() bool {
+	return false
+}
+
 func (f fakeSystemsConfig) GetGithubDatadogDataPath() string {
 	return ""
 }
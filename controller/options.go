@@ -30,24 +30,36 @@ func WithSimplePollster(interval time.Duration, cfg *config.Config) Option {
 			return ErrDatadogNotInitialized
 		}
 
-		wc.pollster = pollster.NewSimplePollster(wc.datadog.Client, interval, cfg, wc.ComponentExists)
+		wc.pollster = pollster.NewSimplePollster(wc.datadog.Registry, interval, cfg, wc.ComponentExists,
+			pollster.WithRateLimits(wc.datadog.Client.RateLimits),
+			pollster.WithDeletedCheck(cfg.GetDatadogDeletedCheckInterval()),
+			pollster.WithSelectorRefresh(cfg.GetDatadogSelectorRefreshInterval()))
 		return nil
 	}
 }
 
-// WithDatadog is an option used to configure a datadog client.
-func WithDatadog(apiKey, appKey string, clientOpts ...client.Option) Option {
+// WithHashPollster is an option for the datadog polling mechanism which compares the content hashes of
+// the components, the hashes are persisted to the state file.
+func WithHashPollster(interval time.Duration, statePath string, cfg *config.Config) Option {
 	return func(wc *Controller) error {
-		var (
-			err error
-			c   *client.Client
-		)
-
-		if len(clientOpts) > 0 {
-			c, err = client.New(apiKey, appKey, clientOpts...)
+		if wc.datadog == nil {
+			return ErrDatadogNotInitialized
 		}
 
-		dd, err := datadog.New(apiKey, appKey, c)
+		wc.pollster = pollster.NewHashPollster(wc.datadog.Registry, interval, cfg, statePath, wc.ComponentExists,
+			pollster.WithHashInterval(cfg.GetDatadogPollingHashInterval()),
+			pollster.WithRateLimits(wc.datadog.Client.RateLimits),
+			pollster.WithDeletedCheck(cfg.GetDatadogDeletedCheckInterval()),
+			pollster.WithSelectorRefresh(cfg.GetDatadogSelectorRefreshInterval()))
+		return nil
+	}
+}
+
+// WithDatadog is an option used to configure a datadog client. If the client is nil, a default
+// client will be created. Datadog options could be used to set the component type registry.
+func WithDatadog(apiKey, appKey string, c *client.Client, opts ...datadog.Option) Option {
+	return func(wc *Controller) error {
+		dd, err := datadog.New(apiKey, appKey, c, opts...)
 		if err != nil {
 			return err
 		}
@@ -87,3 +99,17 @@ func WithSSHGit(url, gitUser, gitEmail string, privateKeyBody []byte, ignoreKnow
 		return nil
 	}
 }
+
+// WithLocalGit is an option used to read components from a local checkout instead of cloning the repo,
+// e.g. to plan or apply changes in CI.
+func WithLocalGit(path string) Option {
+	return func(wc *Controller) error {
+		g, err := git.Open(path)
+		if err != nil {
+			return err
+		}
+
+		wc.git = g
+		return nil
+	}
+}
@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DriftModified is a component which differs in git and datadog.
+	DriftModified = "modified"
+
+	// DriftMissingFile is a managed component without a component file in git.
+	DriftMissingFile = "missing file"
+
+	// DriftDeleted is a managed component which was deleted in datadog.
+	DriftDeleted = "deleted in datadog"
+)
+
+// diffContextLines is a number of unchanged lines printed around a change.
+const diffContextLines = 2
+
+// PlanFilter limits a plan to the user config files of a team or to a single user config file.
+type PlanFilter struct {
+	Team string
+	File string
+}
+
+func (f PlanFilter) matches(cfgFile *config.UserConfigFile) bool {
+	if f.Team != "" && cfgFile.Meta.Team != f.Team {
+		return false
+	}
+
+	return f.File == "" || strings.TrimLeft(cfgFile.Meta.FilePath, "/") == strings.TrimLeft(f.File, "/")
+}
+
+// ComponentDrift is a managed component whose state in git differs from datadog.
+type ComponentDrift struct {
+	Component  types.Component
+	ID         string
+	File       string
+	ConfigFile string
+	Drift      string
+
+	// Diff is a line diff of git, prefixed with "-", and datadog, prefixed with "+".
+	Diff string
+}
+
+// Plan compares every managed component in git with datadog and returns the components which drifted. The workspace
+// is used as it is, so a plan could run against a fresh clone or a local checkout. Components which could not be
+// compared are returned as an error along with the drifts found.
+func (c *Controller) Plan(ctx context.Context, filter PlanFilter) ([]ComponentDrift, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	var (
+		errs   []string
+		drifts []ComponentDrift
+	)
+	for _, cfgFile := range c.cfg.UserConfigFiles() {
+		if !filter.matches(cfgFile) {
+			continue
+		}
+
+		components := cfgFile.Components()
+		for _, ct := range c.datadog.Registry.ComponentTypes() {
+			for _, id := range components[ct.Type()] {
+				drift, err := c.planComponent(ctx, cfgFile, ct.Type(), id)
+				if err != nil {
+					errs = append(errs, err.Error())
+					continue
+				}
+
+				if drift != nil {
+					drifts = append(drifts, *drift)
+				}
+			}
+		}
+	}
+
+	return drifts, c.error(errs)
+}
+
+// planComponent compares a single component in git and datadog, nil is returned if there is no drift.
+func (c *Controller) planComponent(ctx context.Context, cfgFile *config.UserConfigFile, component types.Component, id string) (*ComponentDrift, error) {
+	drift := &ComponentDrift{
+		Component:  component,
+		ID:         id,
+		File:       c.cfg.ComponentPath(component, cfgFile.Meta.Team, cfgFile.Meta.Project, id),
+		ConfigFile: strings.TrimLeft(cfgFile.Meta.FilePath, "/"),
+	}
+
+	buf := new(bytes.Buffer)
+	err := c.datadog.Write(ctx, component, id, buf)
+	if client.IsNotFound(err) {
+		drift.Drift = DriftDeleted
+		return drift, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get %s %s", component, id)
+	}
+
+	stored, err := c.git.ReadFile(drift.File)
+	if err != nil {
+		drift.Drift = DriftMissingFile
+		return drift, nil
+	}
+
+	gitJSON, err := c.normalizeComponent(stored)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid component file %s", drift.File)
+	}
+
+	datadogJSON, err := c.normalizeComponent(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s %s in datadog", component, id)
+	}
+
+	if gitJSON == datadogJSON {
+		return nil, nil
+	}
+
+	drift.Drift = DriftModified
+	drift.Diff = diffLines(gitJSON, datadogJSON)
+	return drift, nil
+}
+
+// Apply updates the modified components in datadog with their state in git and returns the applied drifts.
+// Components deleted in datadog or missing in git are not applied and are returned as an error.
+func (c *Controller) Apply(ctx context.Context, filter PlanFilter) ([]ComponentDrift, error) {
+	drifts, err := c.Plan(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var (
+		errs    []string
+		applied []ComponentDrift
+	)
+	for _, drift := range drifts {
+		if drift.Drift != DriftModified {
+			errs = append(errs, fmt.Sprintf("%s %s is not applied: %s", drift.Component, drift.ID, drift.Drift))
+			continue
+		}
+
+		component, err := c.readComponentFile(drift.File)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		logrus.Infof("Applying %s to %s %s", drift.File, drift.Component, drift.ID)
+		if err := c.datadog.Update(ctx, component); err != nil {
+			errs = append(errs, errors.Wrapf(err, "unable to apply %s", drift.File).Error())
+			continue
+		}
+
+		applied = append(applied, drift)
+	}
+
+	return applied, c.error(errs)
+}
+
+// normalizeComponent returns a normalized JSON of a component without the fields datadog changes on its own, so
+// e.g. the next occurrence of a recurring downtime is not a drift.
+func (c *Controller) normalizeComponent(body []byte) (string, error) {
+	component := &datadog.Component{}
+	if err := json.Unmarshal(body, component); err != nil {
+		return "", err
+	}
+
+	if err := c.datadog.Normalize(component); err != nil {
+		return "", err
+	}
+
+	normalized, err := json.Marshal(component)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeJSON(normalized)
+}
+
+// normalizeJSON returns an indented JSON with sorted keys, so the formatting of a file is not a drift.
+func normalizeJSON(body []byte) (string, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// diffLines returns the changed lines of a and b, prefixed with "-" and "+", with a few unchanged lines around them.
+func diffLines(a, b string) string {
+	dmp := diffmatchpatch.New()
+	charsA, charsB, lines := dmp.DiffLinesToChars(a+"\n", b+"\n")
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(charsA, charsB, false), lines)
+
+	type line struct {
+		prefix string
+		text   string
+	}
+
+	var all []line
+	for _, diff := range diffs {
+		prefix := " "
+		switch diff.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		}
+
+		for _, text := range strings.Split(strings.TrimSuffix(diff.Text, "\n"), "\n") {
+			all = append(all, line{prefix, text})
+		}
+	}
+
+	// print the lines within diffContextLines of a change, skipped lines are replaced with "..."
+	var (
+		out     []string
+		skipped bool
+	)
+	for i, l := range all {
+		show := false
+		for j := i - diffContextLines; j <= i+diffContextLines; j++ {
+			if j >= 0 && j < len(all) && all[j].prefix != " " {
+				show = true
+				break
+			}
+		}
+
+		if !show {
+			skipped = true
+			continue
+		}
+
+		if skipped && len(out) > 0 {
+			out = append(out, "  ...")
+		}
+		skipped = false
+		out = append(out, l.prefix+" "+l.text)
+	}
+
+	return strings.Join(out, "\n")
+}
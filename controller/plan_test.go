@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestPlanAndApply(t *testing.T) {
+	var updated []string
+	registry, err := types.NewRegistry(datadog.NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			if id == "4" {
+				return nil, &client.NotFoundError{}
+			}
+
+			return []byte(`{"id":` + id + `,"title":"datadog"}`), nil
+		}, nil,
+		func(ctx context.Context, payload json.RawMessage) error {
+			updated = append(updated, string(payload))
+			return nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", nil, datadog.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	git := &memGitClient{files: map[string]string{
+		// the formatting of a file is not a drift
+		"data/team/dashboard-1.json":  `{"dashboard": {"title": "datadog", "id": 1}, "type": "dashboard"}`,
+		"data/team/dashboard-2.json":  `{"type":"dashboard","dashboard":{"id":2,"title":"git"}}`,
+		"data/team/dashboard-4.json":  `{"type":"dashboard","dashboard":{"id":4,"title":"git"}}`,
+		"data/other/dashboard-5.json": `{"type":"dashboard","dashboard":{"id":5,"title":"git"}}`,
+	}}
+
+	team := config.NewUserConfigFile(config.MetaData{Team: "team", FilePath: "/config/team.yml"},
+		map[types.Component][]string{types.ComponentDashboard: {"1", "2", "3", "4"}})
+	other := config.NewUserConfigFile(config.MetaData{Team: "other", FilePath: "/config/other.yml"},
+		map[types.Component][]string{types.ComponentDashboard: {"5"}})
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{team, other}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog: ddog,
+		git:     git,
+	}
+
+	drifts, err := c.Plan(context.Background(), PlanFilter{Team: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drifts) != 3 {
+		t.Fatalf("expect 3 drifts. Got %+v", drifts)
+	}
+
+	if d := drifts[0]; d.ID != "2" || d.Drift != DriftModified || !strings.Contains(d.Diff, `-     "title": "git"`) ||
+		!strings.Contains(d.Diff, `+     "title": "datadog"`) {
+		t.Fatalf("expect dashboard 2 modified. Got %+v", d)
+	}
+
+	if d := drifts[1]; d.ID != "3" || d.Drift != DriftMissingFile {
+		t.Fatalf("expect dashboard 3 missing file. Got %+v", d)
+	}
+
+	if d := drifts[2]; d.ID != "4" || d.Drift != DriftDeleted {
+		t.Fatalf("expect dashboard 4 deleted. Got %+v", d)
+	}
+
+	applied, err := c.Apply(context.Background(), PlanFilter{File: "config/other.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 1 || applied[0].ID != "5" || len(updated) != 1 || !strings.Contains(updated[0], `"title":"git"`) {
+		t.Fatalf("expect dashboard 5 updated from git. Got %+v, %v", applied, updated)
+	}
+}
+
+func TestPlanRecurringDowntime(t *testing.T) {
+	registry, err := types.NewRegistry(datadog.NewComponentType(types.ComponentDowntime, "downtimes",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(`{"id":` + id + `,"message":"weekly","start":5000,"end":6000,"active":true,"recurrence":{"type":"weeks"}}`), nil
+		}, nil, nil, datadog.WithNormalizeFn(client.NormalizeDowntime)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ddog, err := datadog.New("123", "345", nil, datadog.WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// datadog moved the downtime to its next occurrence
+	git := &memGitClient{files: map[string]string{
+		"data/team/downtime-1.json": `{"type":"downtime","downtime":{"id":1,"message":"weekly","start":1000,"end":2000,"recurrence":{"type":"weeks"}}}`,
+		"data/team/downtime-2.json": `{"type":"downtime","downtime":{"id":2,"message":"git","start":1000,"end":2000,"recurrence":{"type":"weeks"}}}`,
+	}}
+
+	team := config.NewUserConfigFile(config.MetaData{Team: "team", FilePath: "/config/team.yml"},
+		map[types.Component][]string{types.ComponentDowntime: {"1", "2"}})
+
+	c := &Controller{
+		cfg: &config.Config{
+			UserConfig:   &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{team}},
+			SystemConfig: &fakeSystemsConfig{},
+		},
+		datadog: ddog,
+		git:     git,
+	}
+
+	drifts, err := c.Plan(context.Background(), PlanFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drifts) != 1 || drifts[0].ID != "2" || strings.Contains(drifts[0].Diff, "start") {
+		t.Fatalf("expect only the message of downtime 2 to drift. Got %+v", drifts)
+	}
+}
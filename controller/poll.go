@@ -2,10 +2,11 @@ package controller
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/coinbase/watchdog/config"
 	"github.com/coinbase/watchdog/primitives/datadog/pollster"
-	"github.com/coinbase/watchdog/primitives/datadog/types"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -16,19 +17,23 @@ func (c *Controller) PollDatadog(ctx context.Context) {
 	result := c.pollster.Do(ctx)
 	logrus.Info("Checking datadog assets against master")
 
-	err := c.Poll(c.cfg.UserConfigFiles())
+	err := c.Poll(ctx, c.cfg.UserConfigFiles())
 	if err != nil {
 		logrus.Errorf("The following errors raised during polling datadog: %s", err)
 	}
 
 	go c.startWatcher(ctx, result)
+
+	if interval := c.cfg.GetDatadogDiscoveryInterval(); interval > 0 {
+		go c.reportDiscoveryPeriodically(ctx, interval)
+	}
 }
 
 // Poll the datadog components
-func (c *Controller) Poll(userConfigFiles []*config.UserConfigFile) error {
+func (c *Controller) Poll(ctx context.Context, userConfigFiles []*config.UserConfigFile) error {
 	var errs []string
 	for _, userFile := range userConfigFiles {
-		err := c.CreatePullRequest(userFile.Meta.Team, userFile.Meta.Project, userFile.Meta.FilePath, userFile.Components())
+		err := c.CreatePullRequest(ctx, userFile.Meta.Team, userFile.Meta.Project, userFile.Meta.FilePath, userFile.Components())
 		if err != nil {
 			errs = append(errs, err.Error())
 		}
@@ -38,19 +43,45 @@ func (c *Controller) Poll(userConfigFiles []*config.UserConfigFile) error {
 }
 
 // ReloadUserConfigsAndPoll will reload the user config nad run Poll()
-func (c *Controller) ReloadUserConfigsAndPoll(userConfigFiles []*config.UserConfigFile) error {
-	if err := c.cfg.Reload(); err != nil {
+func (c *Controller) ReloadUserConfigsAndPoll(ctx context.Context, userConfigFiles []*config.UserConfigFile) error {
+	if err := c.cfg.Reload(ctx); err != nil {
 		return errors.Wrap(err, "unable to reload user config")
 	}
 
 	if len(userConfigFiles) == 0 {
-		return c.Poll(c.cfg.UserConfigFiles())
+		return c.Poll(ctx, c.cfg.UserConfigFiles())
+	}
+
+	// poll the reloaded user config files, they include the components matched by selectors.
+	reloaded := make(map[string]*config.UserConfigFile)
+	for _, cfgFile := range c.cfg.UserConfigFiles() {
+		reloaded[strings.TrimLeft(cfgFile.Meta.FilePath, "/")] = cfgFile
+	}
+
+	var files []*config.UserConfigFile
+	for _, cfgFile := range userConfigFiles {
+		if reloadedFile, ok := reloaded[strings.TrimLeft(cfgFile.Meta.FilePath, "/")]; ok {
+			cfgFile = reloadedFile
+		}
+		files = append(files, cfgFile)
 	}
 
-	return c.Poll(userConfigFiles)
+	return c.Poll(ctx, files)
 }
 
+// startWatcher creates pull requests for the changes detected by the pollster. The changes of a user config file
+// are merged into a single pull request once its components were not changed for the quiet period.
 func (c *Controller) startWatcher(ctx context.Context, result chan *pollster.Response) {
+	quietPeriod := c.cfg.GetDatadogQuietPeriod()
+	pending := newCoalescer(quietPeriod, c.cfg.GetDatadogQuietPeriodPerTeam())
+
+	var check <-chan time.Time
+	if quietPeriod > 0 {
+		ticker := time.NewTicker(quietPeriod / coalesceChecks)
+		defer ticker.Stop()
+		check = ticker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -58,11 +89,36 @@ func (c *Controller) startWatcher(ctx context.Context, result chan *pollster.Res
 			return
 
 		case response := <-result:
-			err := c.CreatePullRequest(response.UserConfigFile.Meta.Team,
-				response.UserConfigFile.Meta.Project, response.UserConfigFile.Meta.FilePath, map[types.Component][]int{response.Component: {response.ID}})
-			if err != nil {
-				logrus.Errorf("Error creating a new pull request for detected change: %s", err)
+			if response.Deleted {
+				pending.remove(response.UserConfigFile, response.Component, response.ID)
+				err := c.HandleDeletedComponent(ctx, response.UserConfigFile, response.Component, response.ID)
+				if err != nil {
+					logrus.Errorf("Error handling deleted %s %s: %s", response.Component, response.ID, err)
+				}
+				continue
 			}
+
+			pending.add(response, time.Now())
+			if quietPeriod > 0 {
+				logrus.Debugf("Detected a change of %s %s, waiting %s for more changes", response.Component, response.ID, quietPeriod)
+				continue
+			}
+
+			c.createPullRequests(ctx, pending.ready(time.Now()))
+
+		case now := <-check:
+			c.createPullRequests(ctx, pending.ready(now))
+		}
+	}
+}
+
+// createPullRequests creates a pull request per batch of changes.
+func (c *Controller) createPullRequests(ctx context.Context, batches []*changeBatch) {
+	for _, batch := range batches {
+		meta := batch.userConfigFile.Meta
+		err := c.createPullRequest(ctx, meta.Team, meta.Project, meta.FilePath, batch.componentsMap, batch.modifiedBy)
+		if err != nil {
+			logrus.Errorf("Error creating a new pull request for detected change: %s", err)
 		}
 	}
 }
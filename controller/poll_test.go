@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -10,12 +11,12 @@ import (
 )
 
 func TestPoll(t *testing.T) {
-	ddog, err := datadog.New("123", "345", nil, datadog.WithAccessorGetFn(
-		types.ComponentDashboard,
-		func(id int) (json.RawMessage, error) {
+	ddog, err := datadog.New("123", "345", nil, datadog.WithComponentType(datadog.NewComponentType(
+		types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
 			return nil, nil
-		},
-	))
+		}, nil, nil,
+	)))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -31,7 +32,7 @@ func TestPoll(t *testing.T) {
 		github:  &fakeGithubClient{},
 	}
 
-	err = c.Poll(nil)
+	err = c.Poll(context.Background(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
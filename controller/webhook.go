@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/coinbase/watchdog/config"
 	"github.com/coinbase/watchdog/controller/notify"
 	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -23,7 +25,7 @@ var (
 // HandlePullRequestWebhook takes a pull request payload, extracts the files affected by the change
 // looks for config files and component files, reloads the user config if such files were affected and restores
 // the datadog components if such files were affected.
-func (c *Controller) HandlePullRequestWebhook(payload github.PullRequestPayload) error {
+func (c *Controller) HandlePullRequestWebhook(ctx context.Context, payload github.PullRequestPayload) error {
 	if payload.PullRequest.Number == 0 {
 		return ErrInvalidPullRequestPayload
 	}
@@ -40,6 +42,12 @@ func (c *Controller) HandlePullRequestWebhook(payload github.PullRequestPayload)
 
 	prNumber := int(payload.Number)
 
+	// validate the changes of opened and updated pull requests, including the pull requests of the bot.
+	switch payload.Action {
+	case "opened", "reopened", "synchronize":
+		return c.ValidatePullRequest(ctx, prNumber, payload.PullRequest.Head.Ref, payload.PullRequest.Head.Sha)
+	}
+
 	// if the pull request was not closed, we should ignore this call.
 	if payload.Action != "closed" {
 		logrus.Infof("Ignoring a webhook call for PR %d with action %s", prNumber, payload.Action)
@@ -52,16 +60,28 @@ func (c *Controller) HandlePullRequestWebhook(payload github.PullRequestPayload)
 		return nil
 	}
 
-	// 1. if a user created and merged a pull request, watchdog should apply the change from master branch.
-	// 2. if a bot created a pull request, but a user closed it (without merging), watchdog should restore from a master branch.
-	if (userType == "user" && merged) || (userType == "bot" && !merged) {
-		return c.restoreFromFiles(prNumber)
+	switch {
+	case userType == "user" && merged:
+		// if a user created and merged a pull request, watchdog should apply the change from master branch
+		// create the components from new component files and delete the components whose files were removed.
+		return c.restoreFromFiles(ctx, prNumber, true)
+	case userType == "bot" && !merged:
+		// if a bot created a pull request, but a user closed it (without merging), watchdog should restore from a master branch.
+		return c.restoreFromFiles(ctx, prNumber, false)
+	case userType == "bot" && merged:
+		// component files of bot pull requests already match datadog, but the user config could list created components.
+		_, _, _, userConfigFiles, err := c.pullRequestFiles(ctx, prNumber)
+		if err != nil {
+			return errors.Wrapf(err, "unable to extract files from pull request %d", prNumber)
+		}
+
+		return c.reloadConfigs(ctx, prNumber, userConfigFiles)
 	}
 
 	return nil
 }
 
-func (c *Controller) reloadConfigs(prNumber int, userConfigFiles []string) error {
+func (c *Controller) reloadConfigs(ctx context.Context, prNumber int, userConfigFiles []string) error {
 	if len(userConfigFiles) == 0 {
 		return nil
 	}
@@ -91,7 +111,7 @@ func (c *Controller) reloadConfigs(prNumber int, userConfigFiles []string) error
 		level       notify.NotificationLevel = notify.NInfo
 	)
 
-	err := c.ReloadUserConfigsAndPoll(filesToReload)
+	err := c.ReloadUserConfigsAndPoll(ctx, filesToReload)
 	if err == nil {
 		title = "Successfully reloaded user config!"
 	} else {
@@ -101,7 +121,7 @@ func (c *Controller) reloadConfigs(prNumber int, userConfigFiles []string) error
 		level = notify.NError
 	}
 
-	nonCriticalErr := c.notificationHandler.AddComment(context.Background(), level, title, body, notify.WithGithubPRComment(prNumber))
+	nonCriticalErr := c.notificationHandler.AddComment(ctx, level, title, body, notify.WithGithubPRComment(prNumber))
 	if err != nil {
 		logrus.Errorf("Error commenting on pull request %d: %s", prNumber, nonCriticalErr)
 	}
@@ -109,29 +129,52 @@ func (c *Controller) reloadConfigs(prNumber int, userConfigFiles []string) error
 	return err
 }
 
-func (c *Controller) restoreFromFiles(prNumber int) error {
-	componentFiles, userConfigFiles, err := c.pullRequestFiles(prNumber)
+// restoreFromFiles reloads user configs and restores components changed in a pull request. If merged is set,
+// the pull request was merged by a user and components are also created and deleted from added and removed files.
+func (c *Controller) restoreFromFiles(ctx context.Context, prNumber int, merged bool) error {
+	componentFiles, createdFiles, removedFiles, userConfigFiles, err := c.pullRequestFiles(ctx, prNumber)
 	if err != nil {
 		return errors.Wrapf(err, "unable to extract files from pull request %d", prNumber)
 	}
 
 	// find if config files were affected by the change and reload user config if so.
 	// the error is not critical so just print out but do not fail.
-	err = c.reloadConfigs(prNumber, userConfigFiles)
+	err = c.reloadConfigs(ctx, prNumber, userConfigFiles)
 	if err != nil {
 		logrus.Errorf("Unable to reload user config for PR %d: %s", prNumber, err)
 	}
 
+	// create components from new files and from modified files without an ID.
+	if merged {
+		componentFiles, err = c.createFromFiles(ctx, prNumber, createdFiles, componentFiles)
+		if err != nil {
+			logrus.Errorf("Error creating datadog components: %s. Commenting on PR %d", err, prNumber)
+			e := c.notificationHandler.AddComment(ctx, notify.NError, "Error creating component", err.Error(), notify.WithGithubPRComment(prNumber))
+			if e != nil {
+				logrus.Errorf("Error adding a comment to pull request %d: %s", prNumber, e)
+			}
+		}
+
+		err = c.deleteFromFiles(ctx, prNumber, removedFiles)
+		if err != nil {
+			logrus.Errorf("Error deleting datadog components: %s. Commenting on PR %d", err, prNumber)
+			e := c.notificationHandler.AddComment(ctx, notify.NError, "Error deleting components", err.Error(), notify.WithGithubPRComment(prNumber))
+			if e != nil {
+				logrus.Errorf("Error adding a comment to pull request %d: %s", prNumber, e)
+			}
+		}
+	}
+
 	// allow to restore only a single component file per PR
 	if len(componentFiles) != 1 {
 		return nil
 	}
 
 	// restore components
-	err = c.restoreDatadogComponents(prNumber, componentFiles)
+	err = c.restoreDatadogComponents(ctx, prNumber, componentFiles)
 	if err != nil {
 		logrus.Errorf("Error restoring datadog components: %s. Commenting on PR %d", err, prNumber)
-		e := c.notificationHandler.AddComment(context.Background(), "ERROR", "Error restoring component", err.Error(), notify.WithGithubPRComment(prNumber))
+		e := c.notificationHandler.AddComment(ctx, "ERROR", "Error restoring component", err.Error(), notify.WithGithubPRComment(prNumber))
 		if e != nil {
 			logrus.Errorf("Error adding a comment to pull request %d: %s", prNumber, err)
 		}
@@ -140,10 +183,10 @@ func (c *Controller) restoreFromFiles(prNumber int) error {
 
 }
 
-func (c *Controller) pullRequestFiles(pullRequestNumber int) (componentFiles, configFiles []string, err error) {
-	created, removed, modified, err := c.github.PullRequestFiles(context.Background(), pullRequestNumber)
+func (c *Controller) pullRequestFiles(ctx context.Context, pullRequestNumber int) (componentFiles, createdFiles, removedFiles, configFiles []string, err error) {
+	created, removed, modified, err := c.github.PullRequestFiles(ctx, pullRequestNumber)
 	if err != nil {
-		return nil, nil, errors.Wrapf(err, "unable to find files from pull request %d", pullRequestNumber)
+		return nil, nil, nil, nil, errors.Wrapf(err, "unable to find files from pull request %d", pullRequestNumber)
 	}
 
 	allFiles := append(created, removed...)
@@ -152,10 +195,10 @@ func (c *Controller) pullRequestFiles(pullRequestNumber int) (componentFiles, co
 	logrus.Debugf("The following files have been found in pull request %d, created %v, removed %v, modified %v", pullRequestNumber, created, removed, modified)
 
 	// we should filter the following files:
-	// for datadog component only the files that were modified. If a new component file was created or a file was removed
-	// we should not restore anything.
+	// for datadog component the files that were modified are restored, the files that were created are used to
+	// create new components and the files that were removed are used to delete components if allowed.
 	// for user config files we should handle all possible scenarios: a config can be added, removed or changed.
-	return c.filterComponentFiles(modified), c.filterConfigFiles(allFiles), nil
+	return c.filterComponentFiles(modified), c.filterComponentFiles(created), c.filterComponentFiles(removed), c.filterConfigFiles(allFiles), nil
 }
 
 // filter config files which have datadog prefix path
@@ -182,7 +225,7 @@ func (c *Controller) filterConfigFiles(files []string) []string {
 }
 
 // restore the datadog component, do not fail on error for all files.
-func (c *Controller) restoreDatadogComponents(prNumber int, componentFiles []string) error {
+func (c *Controller) restoreDatadogComponents(ctx context.Context, prNumber int, componentFiles []string) error {
 	c.Lock()
 	defer c.Unlock()
 
@@ -204,13 +247,23 @@ func (c *Controller) restoreDatadogComponents(prNumber int, componentFiles []str
 		}
 
 		logrus.Infof("Restoring datadog component %s from pull request %d", file, prNumber)
-		err = c.datadog.Update(component)
+		err = c.datadog.Update(ctx, component)
 		if err == nil {
-			e := c.notificationHandler.AddComment(context.Background(), "SUCCESS",
+			e := c.notificationHandler.AddComment(ctx, "SUCCESS",
 				fmt.Sprintf("Successfully restored %s file %s", component.Type, file), "", notify.WithGithubPRComment(prNumber))
 			if e != nil {
 				logrus.Errorf("Error commenting on pull request %d: %s", prNumber, err)
 			}
+		} else if alertErr, ok := errors.Cause(err).(*client.AlertFieldsError); ok {
+			// the monitor was restored, but some alert fields were rejected by datadog
+			e := c.notificationHandler.AddComment(ctx, notify.NWarning,
+				fmt.Sprintf("Restored %s file %s, but some alert fields could not be updated", component.Type, file),
+				alertFieldsErrorBody(alertErr), notify.WithGithubPRComment(prNumber))
+			if e != nil {
+				logrus.Errorf("Error commenting on pull request %d: %s", prNumber, e)
+			}
+		} else if client.IsNotFound(err) {
+			errs = append(errs, fmt.Sprintf("%s file %s can not be restored, the component was deleted in datadog: %s", component.Type, file, err))
 		} else {
 			errs = append(errs, err.Error())
 		}
@@ -218,3 +271,19 @@ func (c *Controller) restoreDatadogComponents(prNumber int, componentFiles []str
 
 	return c.error(errs)
 }
+
+// alertFieldsErrorBody lists the alert fields which could not be updated, one per line.
+func alertFieldsErrorBody(alertErr *client.AlertFieldsError) string {
+	var fields []string
+	for field := range alertErr.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	body := fmt.Sprintf("Alert %d:\n", alertErr.AlertID)
+	for _, field := range fields {
+		body += fmt.Sprintf("- `%s`: %s\n", field, alertErr.Fields[field])
+	}
+
+	return body
+}
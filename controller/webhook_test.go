@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"context"
 	"testing"
 
 	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+
+	"github.com/pkg/errors"
 )
 
 func TestFilter(t *testing.T) {
@@ -55,7 +59,7 @@ func TestPullRequestFiles(t *testing.T) {
 		github: &fakeGithubClient{},
 	}
 
-	componentFiles, configFiles, err := c.pullRequestFiles(1)
+	componentFiles, createdFiles, removedFiles, configFiles, err := c.pullRequestFiles(context.Background(), 1)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -68,6 +72,14 @@ func TestPullRequestFiles(t *testing.T) {
 		t.Fatalf("expect component file data/team/dashboard-123. Got %s", configFiles[0])
 	}
 
+	if len(createdFiles) != 0 {
+		t.Fatalf("expect no created component files. Got %v", createdFiles)
+	}
+
+	if len(removedFiles) != 0 {
+		t.Fatalf("expect no removed component files. Got %v", removedFiles)
+	}
+
 	if len(configFiles) != 3 {
 		t.Fatalf("expect 3 config files. Got %v", configFiles)
 	}
@@ -84,3 +96,18 @@ func TestPullRequestFiles(t *testing.T) {
 		t.Fatalf("expect file config/foo/bar/monitor.yml. Got %s", configFiles[2])
 	}
 }
+
+func TestAlertFieldsErrorBody(t *testing.T) {
+	body := alertFieldsErrorBody(&client.AlertFieldsError{
+		AlertID: 10,
+		Fields: map[string]error{
+			"silenced": errors.New("not allowed"),
+			"message":  errors.New("invalid"),
+		},
+	})
+
+	expected := "Alert 10:\n- `message`: invalid\n- `silenced`: not allowed\n"
+	if body != expected {
+		t.Fatalf("expect body %q. Got %q", expected, body)
+	}
+}
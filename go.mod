@@ -12,6 +12,7 @@ require (
 	github.com/mnaboka/ghinstallation v0.1.3
 	github.com/nlopes/slack v0.5.0
 	github.com/pkg/errors v0.8.1
+	github.com/sergi/go-diff v1.0.0
 	github.com/sirupsen/logrus v1.4.0
 	github.com/waigani/diffparser v0.0.0-20190211082042-03c80ab4baa8
 	golang.org/x/crypto v0.0.0-20190320223903-b7391e95e576
@@ -0,0 +1,365 @@
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The kinds of a change.
+const (
+	Added     = "added"
+	Removed   = "removed"
+	Modified  = "modified"
+	Reordered = "reordered"
+)
+
+// maxValueLength is a max length of a value printed in a change, longer values are truncated.
+const maxValueLength = 80
+
+// labelKeys are the fields which identify an object in an array, in the order of preference. The title of
+// a dashboard widget is stored in its definition.
+var labelKeys = [][]string{{"title"}, {"name"}, {"definition", "title"}, {"id"}}
+
+// Change is a single difference between two JSON documents.
+type Change struct {
+	// Path is a human readable path of the changed field, objects in arrays are named after their title,
+	// name or ID, e.g. widget 'Latency p99' definition.requests[0].q.
+	Path string
+	Kind string
+	Old  interface{}
+	New  interface{}
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s added: %s", c.Path, value(c.New))
+	case Removed:
+		return fmt.Sprintf("%s removed: %s", c.Path, value(c.Old))
+	case Reordered:
+		return fmt.Sprintf("%s reordered", c.Path)
+	default:
+		return fmt.Sprintf("%s changed from %s to %s", c.Path, value(c.Old), value(c.New))
+	}
+}
+
+// Compare returns the changes between the old and the new JSON documents.
+func Compare(oldJSON, newJSON []byte) ([]Change, error) {
+	oldTree, err := decode(oldJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the old document")
+	}
+
+	newTree, err := decode(newJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to decode the new document")
+	}
+
+	var changes []Change
+	compare(path{}, oldTree, newTree, &changes)
+	return changes, nil
+}
+
+func decode(body []byte) (interface{}, error) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// path is a human readable path, the label names the last object identified in an array
+// and the keys lead from that object to a field.
+type path struct {
+	label string
+	keys  string
+}
+
+func (p path) key(k string) path {
+	if p.keys == "" {
+		return path{p.label, k}
+	}
+
+	return path{p.label, p.keys + "." + k}
+}
+
+func (p path) index(i int) path {
+	return path{p.label, fmt.Sprintf("%s[%d]", p.keys, i)}
+}
+
+// element returns a path of an object named after its label, e.g. the key widgets and the title
+// Latency p99 make widget 'Latency p99'.
+func (p path) element(label string) path {
+	kind := singular(lastKey(p.keys))
+	if kind == "" {
+		kind = "item"
+	}
+
+	name := fmt.Sprintf("%s %s", kind, label)
+	if p.label != "" {
+		name = p.label + " " + name
+	}
+
+	return path{label: name}
+}
+
+func (p path) String() string {
+	switch {
+	case p.label == "":
+		return p.keys
+	case p.keys == "":
+		return p.label
+	default:
+		return p.label + " " + p.keys
+	}
+}
+
+func compare(p path, oldValue, newValue interface{}, changes *[]Change) {
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		if n, ok := newValue.(map[string]interface{}); ok {
+			compareObjects(p, o, n, changes)
+			return
+		}
+	case []interface{}:
+		if n, ok := newValue.([]interface{}); ok {
+			compareArrays(p, o, n, changes)
+			return
+		}
+	}
+
+	if !equal(oldValue, newValue) {
+		*changes = append(*changes, Change{Path: p.String(), Kind: Modified, Old: oldValue, New: newValue})
+	}
+}
+
+func compareObjects(p path, o, n map[string]interface{}, changes *[]Change) {
+	keys := make(map[string]bool)
+	for k := range o {
+		keys[k] = true
+	}
+	for k := range n {
+		keys[k] = true
+	}
+
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		oldValue, inOld := o[k]
+		newValue, inNew := n[k]
+		switch {
+		case !inOld:
+			*changes = append(*changes, Change{Path: p.key(k).String(), Kind: Added, New: newValue})
+		case !inNew:
+			*changes = append(*changes, Change{Path: p.key(k).String(), Kind: Removed, Old: oldValue})
+		default:
+			compare(p.key(k), oldValue, newValue, changes)
+		}
+	}
+}
+
+// compareArrays matches objects by their label if every object has a unique one, scalars are compared
+// as sets, e.g. tags. Other arrays are compared by index.
+func compareArrays(p path, o, n []interface{}, changes *[]Change) {
+	oldLabels, oldOK := labels(o)
+	newLabels, newOK := labels(n)
+	if oldOK && newOK {
+		for i, label := range oldLabels {
+			j := indexOf(newLabels, label)
+			if j < 0 {
+				*changes = append(*changes, Change{Path: p.element(label).String(), Kind: Removed, Old: o[i]})
+				continue
+			}
+
+			compare(p.element(label), o[i], n[j], changes)
+		}
+
+		for j, label := range newLabels {
+			if indexOf(oldLabels, label) < 0 {
+				*changes = append(*changes, Change{Path: p.element(label).String(), Kind: Added, New: n[j]})
+			}
+		}
+
+		return
+	}
+
+	if scalars(o) && scalars(n) {
+		compareSets(p, o, n, changes)
+		return
+	}
+
+	for i := 0; i < len(o) || i < len(n); i++ {
+		switch {
+		case i >= len(n):
+			*changes = append(*changes, Change{Path: p.index(i).String(), Kind: Removed, Old: o[i]})
+		case i >= len(o):
+			*changes = append(*changes, Change{Path: p.index(i).String(), Kind: Added, New: n[i]})
+		default:
+			compare(p.index(i), o[i], n[i], changes)
+		}
+	}
+}
+
+// compareSets reports the added and removed values named after the singular key, e.g. tag added: `env:prod`.
+func compareSets(p path, o, n []interface{}, changes *[]Change) {
+	valuePath := p
+	if key := lastKey(p.keys); key != "" && strings.HasSuffix(p.keys, key) {
+		valuePath.keys = strings.TrimSuffix(p.keys, key) + singular(key)
+	}
+
+	changed := false
+	for _, v := range o {
+		if !contains(n, v) {
+			*changes = append(*changes, Change{Path: valuePath.String(), Kind: Removed, Old: v})
+			changed = true
+		}
+	}
+
+	for _, v := range n {
+		if !contains(o, v) {
+			*changes = append(*changes, Change{Path: valuePath.String(), Kind: Added, New: v})
+			changed = true
+		}
+	}
+
+	if !changed && !equal(o, n) {
+		*changes = append(*changes, Change{Path: p.String(), Kind: Reordered})
+	}
+}
+
+// labels returns the labels of the objects in an array, false if an element is not an object or
+// the labels are not unique.
+func labels(items []interface{}) ([]string, bool) {
+	if len(items) == 0 {
+		return nil, true
+	}
+
+	var (
+		result []string
+		seen   = make(map[string]bool)
+	)
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		label := objectLabel(obj)
+		if label == "" || seen[label] {
+			return nil, false
+		}
+		seen[label] = true
+		result = append(result, label)
+	}
+
+	return result, true
+}
+
+// objectLabel returns the first label field of an object, strings are quoted.
+func objectLabel(obj map[string]interface{}) string {
+	for _, keys := range labelKeys {
+		var v interface{} = obj
+		for _, k := range keys {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				v = nil
+				break
+			}
+			v = m[k]
+		}
+
+		switch label := v.(type) {
+		case string:
+			if label != "" {
+				return "'" + label + "'"
+			}
+		case json.Number:
+			return label.String()
+		}
+	}
+
+	return ""
+}
+
+func scalars(items []interface{}) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(items []interface{}, v interface{}) bool {
+	for _, item := range items {
+		if equal(item, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indexOf(items []string, s string) int {
+	for i, item := range items {
+		if item == s {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func equal(a, b interface{}) bool {
+	return compact(a) == compact(b)
+}
+
+func compact(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// value returns a compact JSON of a value, truncated to maxValueLength.
+func value(v interface{}) string {
+	s := compact(v)
+	if len(s) > maxValueLength {
+		s = s[:maxValueLength-3] + "..."
+	}
+
+	return "`" + s + "`"
+}
+
+func lastKey(keys string) string {
+	if i := strings.LastIndex(keys, "."); i >= 0 {
+		keys = keys[i+1:]
+	}
+
+	if i := strings.Index(keys, "["); i >= 0 {
+		keys = keys[:i]
+	}
+
+	return keys
+}
+
+func singular(key string) string {
+	if len(key) > 1 && strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss") {
+		return key[:len(key)-1]
+	}
+
+	return key
+}
@@ -0,0 +1,70 @@
+package jsondiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	oldJSON := `{
+  "title": "payments",
+  "tags": ["team:payments", "env:staging"],
+  "widgets": [
+    {"id": 1, "definition": {"title": "Latency p99", "requests": [{"q": "p99:latency{env:staging}"}]}},
+    {"id": 2, "definition": {"title": "Errors", "requests": [{"q": "sum:errors{*}"}]}}
+  ],
+  "options": {"thresholds": {"critical": 5, "warning": 3}},
+  "notify": ["@slack-a", "@slack-b"]
+}`
+
+	newJSON := `{
+  "title": "payments",
+  "tags": ["team:payments", "env:prod"],
+  "widgets": [
+    {"id": 2, "definition": {"title": "Errors", "requests": [{"q": "sum:errors{*}"}]}},
+    {"id": 1, "definition": {"title": "Latency p99", "requests": [{"q": "p99:latency{env:prod}"}]}},
+    {"id": 3, "definition": {"title": "Traffic"}}
+  ],
+  "options": {"thresholds": {"critical": 10}},
+  "notify": ["@slack-b", "@slack-a"],
+  "description": "new"
+}`
+
+	changes, err := Compare([]byte(oldJSON), []byte(newJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+
+	expected := []string{
+		"description added: `\"new\"`",
+		"notify reordered",
+		"options.thresholds.critical changed from `5` to `10`",
+		"options.thresholds.warning removed: `3`",
+		"tag removed: `\"env:staging\"`",
+		"tag added: `\"env:prod\"`",
+		"widget 'Latency p99' definition.requests[0].q changed from `\"p99:latency{env:staging}\"` to `\"p99:latency{env:prod}\"`",
+		"widget 'Traffic' added: `{\"definition\":{\"title\":\"Traffic\"},\"id\":3}`",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expect changes:\n%s\nGot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	changes, err = Compare([]byte(oldJSON), []byte(oldJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 0 {
+		t.Fatalf("expect no changes. Got %v", changes)
+	}
+
+	if _, err := Compare([]byte(`{`), []byte(oldJSON)); err == nil {
+		t.Fatal("expect error decoding invalid JSON")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/coinbase/watchdog/lint"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+)
+
+// lintCommand is the `watchdog lint [flags] <path>` command.
+type lintCommand struct {
+	path       string
+	configPath string
+	dataPath   string
+}
+
+// parseLintArgs parses the arguments of the lint command, the path of the repo defaults to the current directory.
+func parseLintArgs(args []string) (*lintCommand, error) {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+
+	cmd := &lintCommand{path: "."}
+	fs.StringVar(&cmd.configPath, "config-path", "/config", "a base path of user config files, relative to the repo root")
+	fs.StringVar(&cmd.dataPath, "data-path", "data", "a base path of component files, relative to the repo root")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() > 0 {
+		cmd.path = fs.Arg(0)
+	}
+
+	return cmd, nil
+}
+
+// runLint lints a local checkout of the repo and returns the exit code. Datadog is not called, so
+// API keys are not required.
+func runLint(cmd *lintCommand, out io.Writer) int {
+	if _, err := os.Stat(cmd.path); err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+		return exitError
+	}
+
+	ddClient, err := client.New("", "")
+	if err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+		return exitError
+	}
+
+	registry, err := datadog.NewRegistry(ddClient)
+	if err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+		return exitError
+	}
+
+	var (
+		readDir = func(dir string) ([]os.FileInfo, error) {
+			return ioutil.ReadDir(filepath.Join(cmd.path, dir))
+		}
+		readFile = func(file string) ([]byte, error) {
+			return ioutil.ReadFile(filepath.Join(cmd.path, file))
+		}
+	)
+
+	l := lint.New(registry, cmd.configPath, cmd.dataPath)
+	files, err := lint.ReadFiles(readDir, readFile, cmd.configPath, cmd.dataPath)
+	if err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+		return exitError
+	}
+
+	problems := l.Lint(files)
+	for _, problem := range problems {
+		fmt.Fprintln(out, problem)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(out, "%d problems found\n", len(problems))
+		return exitError
+	}
+
+	fmt.Fprintf(out, "%d files checked, no problems found\n", len(files))
+	return 0
+}
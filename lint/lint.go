@@ -0,0 +1,258 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+)
+
+// New returns a new linter of a resources repo. The config path is a base path of user config files and
+// the data path is a base path of component files, both relative to the repo root.
+func New(registry *types.Registry, configPath, dataPath string) *Linter {
+	return &Linter{
+		registry:   registry,
+		configPath: strings.Trim(configPath, "/"),
+		dataPath:   strings.Trim(dataPath, "/"),
+	}
+}
+
+// Linter validates user config files and component files without calling datadog.
+type Linter struct {
+	registry   *types.Registry
+	configPath string
+	dataPath   string
+}
+
+// Problem is a lint error found in a file.
+type Problem struct {
+	File    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// owner is a user config file which lists a component.
+type owner struct {
+	file    string
+	team    string
+	project string
+}
+
+type componentKey struct {
+	component types.Component
+	id        string
+}
+
+// Lint validates the files of a resources repo, a mapping of a file path relative to the repo root to its content.
+// User config files must be valid and their IDs must not be listed by other teams. Component files must be
+// under the directory of a team, named after their type and ID and hold a component of a registered type.
+func (l *Linter) Lint(files map[string][]byte) []Problem {
+	var (
+		problems []Problem
+		owners   = make(map[componentKey][]owner)
+		teamDirs = make(map[string]bool)
+	)
+
+	for _, file := range sortedFiles(files) {
+		if !l.isConfigFile(file) {
+			continue
+		}
+
+		cfgFile, err := config.ParseUserConfig(files[file], l.registry)
+		if err != nil {
+			problems = append(problems, Problem{file, fmt.Sprintf("invalid user config: %s", err)})
+			continue
+		}
+
+		if cfgFile.Meta.Team == "" {
+			problems = append(problems, Problem{file, "meta.team is required"})
+			continue
+		}
+
+		teamDirs[path.Dir(config.ComponentPath(l.dataPath, "", cfgFile.Meta.Team, cfgFile.Meta.Project, ""))] = true
+
+		for component, ids := range cfgFile.Components() {
+			for _, id := range ids {
+				key := componentKey{component, id}
+				owners[key] = append(owners[key], owner{file, cfgFile.Meta.Team, cfgFile.Meta.Project})
+			}
+		}
+	}
+
+	problems = append(problems, duplicates(owners)...)
+
+	for _, file := range sortedFiles(files) {
+		if strings.HasPrefix(file, l.dataPath+"/") {
+			problems = append(problems, l.lintComponentFile(file, files[file], teamDirs, owners)...)
+		}
+	}
+
+	return problems
+}
+
+func (l *Linter) isConfigFile(file string) bool {
+	return strings.HasPrefix(file, l.configPath+"/") && (strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml"))
+}
+
+// duplicates returns a problem for every component listed by several teams.
+func duplicates(owners map[componentKey][]owner) []Problem {
+	var problems []Problem
+	for key, list := range owners {
+		teams := make(map[string]bool)
+		for _, o := range list {
+			teams[o.team] = true
+		}
+
+		if len(teams) < 2 {
+			continue
+		}
+
+		for _, o := range list {
+			var others []string
+			for _, other := range list {
+				if other.team != o.team {
+					others = append(others, fmt.Sprintf("team %s in %s", other.team, other.file))
+				}
+			}
+
+			problems = append(problems, Problem{o.file, fmt.Sprintf("%s %s is also listed by %s", key.component, key.id, strings.Join(others, ", "))})
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+
+		return problems[i].Message < problems[j].Message
+	})
+
+	return problems
+}
+
+func (l *Linter) lintComponentFile(file string, body []byte, teamDirs map[string]bool, owners map[componentKey][]owner) []Problem {
+	if !strings.HasSuffix(file, ".json") {
+		return []Problem{{file, "not a component file, expect a .json file"}}
+	}
+
+	var problems []Problem
+	if !teamDirs[path.Dir(file)] {
+		problems = append(problems, Problem{file, fmt.Sprintf("no user config for directory %s, expect %s/<team>[/<project>]", path.Dir(file), l.dataPath)})
+	}
+
+	component := &datadog.Component{}
+	if err := json.Unmarshal(body, component); err != nil {
+		return append(problems, Problem{file, fmt.Sprintf("unable to unmarshal to datadog component: %s", err)})
+	}
+
+	ct, ok := l.registry.Get(component.Type)
+	if !ok {
+		return append(problems, Problem{file, fmt.Sprintf("unknown component type %q", component.Type)})
+	}
+
+	if len(component.Payload) == 0 || string(component.Payload) == "null" {
+		return append(problems, Problem{file, fmt.Sprintf("empty %s, expect the component under the %q key", component.Type, component.Type)})
+	}
+
+	// a file without an ID in the name is a new component to be created.
+	fileComponent, fileID, ok := config.ParseComponentPath(l.registry, file)
+	if !ok {
+		return problems
+	}
+
+	if fileComponent != component.Type {
+		problems = append(problems, Problem{file, fmt.Sprintf("file name type %s does not match component type %s", fileComponent, component.Type)})
+		return problems
+	}
+
+	if creator, ok := ct.(types.Creator); ok {
+		id, err := creator.PayloadID(component.Payload)
+		if err == nil && id != "" && id != fileID {
+			problems = append(problems, Problem{file, fmt.Sprintf("%s id %s does not match file name id %s", component.Type, id, fileID)})
+		}
+	}
+
+	// a listed component must be stored under the directory of the user config which lists it.
+	list := owners[componentKey{component.Type, fileID}]
+	if len(list) == 0 {
+		return problems
+	}
+
+	var expected []string
+	for _, o := range list {
+		componentPath := config.ComponentPath(l.dataPath, component.Type, o.team, o.project, fileID)
+		if componentPath == file {
+			return problems
+		}
+		expected = append(expected, componentPath)
+	}
+
+	return append(problems, Problem{file, fmt.Sprintf("%s %s is listed in %s, expect file %s",
+		component.Type, fileID, list[0].file, strings.Join(expected, " or "))})
+}
+
+// ReadFiles reads every file under the given directories with readDir and readFile, e.g. of a git client.
+// A directory which does not exist is skipped.
+func ReadFiles(readDir func(string) ([]os.FileInfo, error), readFile func(string) ([]byte, error), dirs ...string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		items, err := readDir(dir)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read directory %s", dir)
+		}
+
+		for _, item := range items {
+			itemPath := path.Join(dir, item.Name())
+			if item.IsDir() {
+				if err := walk(itemPath); err != nil {
+					return err
+				}
+				continue
+			}
+
+			body, err := readFile(itemPath)
+			if err != nil {
+				return errors.Wrapf(err, "unable to read file %s", itemPath)
+			}
+			files[itemPath] = body
+		}
+
+		return nil
+	}
+
+	for _, dir := range dirs {
+		dir = strings.Trim(dir, "/")
+		if _, err := readDir(dir); os.IsNotExist(errors.Cause(err)) {
+			continue
+		}
+
+		if err := walk(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func sortedFiles(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+)
+
+func TestLint(t *testing.T) {
+	payloadID := func(payload json.RawMessage) (string, error) {
+		var p struct {
+			ID json.Number `json:"id"`
+		}
+		err := json.Unmarshal(payload, &p)
+		return p.ID.String(), err
+	}
+
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentDashboard, "dashboards", nil, nil, nil,
+			datadog.WithCreateFn(payloadID, func(context.Context, json.RawMessage) (string, error) { return "", nil })),
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", nil, nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"config/payments.yml":              []byte("meta:\n  team: payments\ndashboards: [1, 2, 3]\n"),
+		"config/sre.yaml":                  []byte("meta:\n  team: sre\ndashboards: [3]\n"),
+		"config/broken.yml":                []byte("meta:\n  team: broken\ndashboards: {1: 2}\n"),
+		"config/noteam.yml":                []byte("dashboards: [4]\n"),
+		"config/README.md":                 []byte("not a config"),
+		"data/payments/dashboard-1.json":   []byte(`{"type":"dashboard","dashboard":{"id":1}}`),
+		"data/payments/dashboard-2.json":   []byte(`{"type":"dashboard","dashboard":{"id":5}}`),
+		"data/payments/monitor-6.json":     []byte(`{"type":"dashboard","dashboard":{"id":6}}`),
+		"data/payments/new-dashboard.json": []byte(`{"type":"dashboard","dashboard":{"title":"new"}}`),
+		"data/payments/broken.json":        []byte(`{"type":`),
+		"data/payments/empty.json":         []byte(`{"type":"dashboard"}`),
+		"data/payments/unknown.json":       []byte(`{"type":"notebook","notebook":{}}`),
+		"data/payments/notes.txt":          []byte(`notes`),
+		"data/sre/dashboard-1.json":        []byte(`{"type":"dashboard","dashboard":{"id":1}}`),
+		"data/unknown/dashboard-7.json":    []byte(`{"type":"dashboard","dashboard":{"id":7}}`),
+	}
+
+	var got []string
+	for _, p := range New(registry, "/config", "data").Lint(files) {
+		got = append(got, p.String())
+	}
+
+	expected := []string{
+		"config/broken.yml: invalid user config",
+		"config/noteam.yml: meta.team is required",
+		"config/payments.yml: dashboard 3 is also listed by team sre in config/sre.yaml",
+		"config/sre.yaml: dashboard 3 is also listed by team payments in config/payments.yml",
+		"data/payments/broken.json: unable to unmarshal to datadog component",
+		"data/payments/dashboard-2.json: dashboard id 5 does not match file name id 2",
+		"data/payments/empty.json: empty dashboard",
+		"data/payments/monitor-6.json: file name type monitor does not match component type dashboard",
+		"data/payments/notes.txt: not a component file",
+		"data/payments/unknown.json: unknown component type \"notebook\"",
+		"data/sre/dashboard-1.json: dashboard 1 is listed in config/payments.yml, expect file data/payments/dashboard-1.json",
+		"data/unknown/dashboard-7.json: no user config for directory data/unknown",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expect %d problems. Got %d:\n%s", len(expected), len(got), strings.Join(got, "\n"))
+	}
+
+	for i := range expected {
+		if !strings.HasPrefix(got[i], expected[i]) {
+			t.Fatalf("expect problem %q. Got %q", expected[i], got[i])
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/coinbase/watchdog/config"
 	"github.com/coinbase/watchdog/controller"
+	"github.com/coinbase/watchdog/primitives/datadog"
 	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/server"
 
@@ -23,13 +24,23 @@ func main() {
 		return
 	}
 
-	cfg, err := config.NewConfig(context.Background(), nil, nil)
+	// the lint command validates a local checkout of the repo and does not require the system config.
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		cmd, err := parseLintArgs(os.Args[2:])
+		if err != nil {
+			logrus.Fatalf("invalid lint arguments: %s", err)
+		}
+
+		os.Exit(runLint(cmd, os.Stdout))
+	}
+
+	sysCfg, err := config.NewSystemConfig()
 	if err != nil {
-		logrus.Fatalf("unable to initialize a config: %s", err)
+		logrus.Fatalf("unable to initialize a system config: %s", err)
 	}
 
 	// enable the json logging if value is set
-	if cfg.GetLoggingJSON() {
+	if sysCfg.GetLoggingJSON() {
 		logrus.SetFormatter(&logrus.JSONFormatter{})
 	}
 
@@ -37,7 +48,7 @@ func main() {
 	logrus.SetOutput(os.Stdout)
 
 	// set the requested logging level
-	loggingLevel := cfg.GetLoggingLevel()
+	loggingLevel := sysCfg.GetLoggingLevel()
 	if loggingLevel != "" {
 		level, err := logrus.ParseLevel(loggingLevel)
 		if err != nil {
@@ -47,19 +58,82 @@ func main() {
 		logrus.SetLevel(level)
 	}
 
-	// setup default fields to be remove from dashboard/monitor/screen board response.
+	// setup default fields to be remove from dashboard/unified dashboard/monitor/screen board/downtime/synthetics/slo response.
 	clientOptions := []client.Option{
 		client.WithRemoveDashboardFields([]string{"dash.modified"}),
+		client.WithRemoveUnifiedDashboardFields([]string{"modified_at"}),
 		client.WithRemoveMonitorFields([]string{"modified", "overall_state", "overall_state_modified"}, []string{"state"}),
 		client.WithRemoveScreenBoardFields([]string{"modified"}),
+		client.WithRemoveDowntimeFields([]string{"active", "child_id"}),
+		client.WithRemoveSyntheticsFields([]string{"modified_at"}),
+		client.WithRemoveSLOFields([]string{"modified_at"}),
+		client.WithRequestTimeout(sysCfg.GetDatadogRequestTimeout()),
+	}
+
+	// select the datadog site, a custom API URL takes precedence.
+	if sysCfg.GetDatadogAPIURL() != "" {
+		clientOptions = append(clientOptions, client.WithBaseURL(sysCfg.GetDatadogAPIURL()))
+	} else {
+		clientOptions = append(clientOptions, client.WithSite(sysCfg.GetDatadogSite()))
+	}
+
+	ddClient, err := client.New(sysCfg.GetDatadogAPIKey(), sysCfg.GetDatadogAPPKey(), clientOptions...)
+	if err != nil {
+		logrus.Fatalf("unable to create a datadog client: %s", err)
+	}
+
+	// the registry holds all supported component types, user config files are parsed
+	// with the config keys of registered types.
+	registry, err := datadog.NewRegistry(ddClient)
+	if err != nil {
+		logrus.Fatalf("unable to create a component type registry: %s", err)
+	}
+
+	// the adopt command onboards existing components of a team, component ID flags depend on the registry.
+	// the plan and apply commands compare managed components in git and datadog, without running the server.
+	var (
+		adoptOpts *controller.AdoptOptions
+		planCmd   *planCommand
+	)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "adopt":
+			adoptOpts, err = parseAdoptArgs(os.Args[2:], registry)
+		case "plan", "apply":
+			planCmd, err = parsePlanArgs(os.Args[1], os.Args[2:])
+		}
+		if err != nil {
+			logrus.Fatalf("invalid %s arguments: %s", os.Args[1], err)
+		}
+	}
+
+	// a local checkout holds both the user configs and the components, e.g. in CI.
+	var userCfg config.UserConfig
+	if planCmd != nil && planCmd.path != "" {
+		userCfg, err = config.NewUserConfigFromPath(context.Background(), registry, planCmd.path)
+	} else {
+		userCfg, err = config.NewUserConfigFromGit(context.Background(), registry)
+	}
+	if err != nil {
+		logrus.Fatalf("unable to initialize a user config: %s", err)
+	}
+
+	cfg, err := config.NewConfig(context.Background(), sysCfg, userCfg)
+	if err != nil {
+		logrus.Fatalf("unable to initialize a config: %s", err)
 	}
 
 	// construct the controller options
 	options := []controller.Option{
-		controller.WithDatadog(cfg.GetDatadogAPIKey(), cfg.GetDatadogAPPKey(), clientOptions...),
+		controller.WithDatadog(cfg.GetDatadogAPIKey(), cfg.GetDatadogAPPKey(), ddClient, datadog.WithRegistry(registry)),
 		controller.WithGithub(cfg.GetGithubProjectOwner(), cfg.GetGithubRepo(), cfg.GithubAPIURL(),
 			cfg.GetGithubIntegrationID(), cfg.GetGithubAppInstallationID(), cfg.GithubAppPrivateKeyBytes()),
-		controller.WithSSHGit(cfg.GitURL(), cfg.GitUser(), cfg.GitEmail(), cfg.GithubAppPrivateKeyBytes(), cfg.GetIgnoreKnownHosts()),
+	}
+
+	if planCmd != nil && planCmd.path != "" {
+		options = append(options, controller.WithLocalGit(planCmd.path))
+	} else {
+		options = append(options, controller.WithSSHGit(cfg.GitURL(), cfg.GitUser(), cfg.GitEmail(), cfg.GithubAppPrivateKeyBytes(), cfg.GetIgnoreKnownHosts()))
 	}
 
 	// use polling scheduler based on config
@@ -67,6 +141,8 @@ func main() {
 	switch cfg.GetDatadogPollingScheduler() {
 	case "simple":
 		options = append(options, controller.WithSimplePollster(cfg.GetDatadogPollingInterval(), cfg))
+	case "hash":
+		options = append(options, controller.WithHashPollster(cfg.GetDatadogPollingInterval(), cfg.GetDatadogPollingStatePath(), cfg))
 	default:
 		logrus.Fatalf("invalid datadog polling scheduler %s", cfg.GetDatadogPollingScheduler())
 	}
@@ -77,6 +153,17 @@ func main() {
 		logrus.Fatalf("unable to initialize controller %s", err)
 	}
 
+	if adoptOpts != nil {
+		if err := runAdopt(context.Background(), c, adoptOpts, os.Stdout); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+
+	if planCmd != nil {
+		os.Exit(runPlan(context.Background(), c, planCmd, os.Stdout))
+	}
+
 	// Start the polling scheduler in the background
 	go c.PollDatadog(context.Background())
 
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/coinbase/watchdog/controller"
+)
+
+const (
+	// exitDrift is returned by the plan command if a managed component drifted.
+	exitDrift = 2
+
+	// exitError is returned if the plan or apply command failed.
+	exitError = 1
+)
+
+// planCommand is the `watchdog plan` or `watchdog apply` command.
+type planCommand struct {
+	apply  bool
+	path   string
+	filter controller.PlanFilter
+}
+
+// parsePlanArgs parses the arguments of the plan and apply commands.
+func parsePlanArgs(name string, args []string) (*planCommand, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	cmd := &planCommand{apply: name == "apply"}
+	fs.StringVar(&cmd.filter.Team, "team", "", "only the user config files of the team")
+	fs.StringVar(&cmd.filter.File, "file", "", "only the user config file, relative to the repo root")
+	fs.StringVar(&cmd.path, "path", "", "use a local checkout of the repo instead of cloning it")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+// runPlan prints the drifted components, or applies them, and returns the exit code. A plan exits
+// with exitDrift if any managed component drifted.
+func runPlan(ctx context.Context, c *controller.Controller, cmd *planCommand, out io.Writer) int {
+	if cmd.apply {
+		applied, err := c.Apply(ctx, cmd.filter)
+		for _, drift := range applied {
+			fmt.Fprintf(out, "applied %s to %s %s\n", drift.File, drift.Component, drift.ID)
+		}
+
+		if err != nil {
+			fmt.Fprintf(out, "error: %s\n", err)
+			return exitError
+		}
+
+		fmt.Fprintf(out, "%d components applied\n", len(applied))
+		return 0
+	}
+
+	drifts, err := c.Plan(ctx, cmd.filter)
+	for _, drift := range drifts {
+		fmt.Fprintf(out, "%s %s (%s, %s): %s\n", drift.Component, drift.ID, drift.File, drift.ConfigFile, drift.Drift)
+		if drift.Diff != "" {
+			fmt.Fprintf(out, "%s\n", drift.Diff)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(out, "error: %s\n", err)
+		return exitError
+	}
+
+	if len(drifts) > 0 {
+		fmt.Fprintf(out, "%d components drifted\n", len(drifts))
+		return exitDrift
+	}
+
+	fmt.Fprintln(out, "no drift")
+	return 0
+}
@@ -1,13 +1,19 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // UpdateAlert updates an alert from raw message.
-func (c Client) UpdateAlert(alert json.RawMessage) error {
-	err := c.genericUpdate(alertType, alert)
+func (c Client) UpdateAlert(ctx context.Context, alert json.RawMessage) error {
+	err := c.genericUpdate(ctx, alertType, alert)
 	if err != nil {
 		if err == errInvalidComponent {
 			return ErrInvalidAlert
@@ -19,14 +25,101 @@ func (c Client) UpdateAlert(alert json.RawMessage) error {
 	return nil
 }
 
+// AlertFieldsError is returned if some fields of an alert could not be updated.
+// Datadog rejects some alert updates, so each changed field is updated separately.
+type AlertFieldsError struct {
+	AlertID int
+
+	// Fields maps a field name to the error returned by datadog.
+	Fields map[string]error
+}
+
+// Error implements error interface.
+func (e *AlertFieldsError) Error() string {
+	var fields []string
+	for field, err := range e.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", field, err))
+	}
+	sort.Strings(fields)
+
+	return fmt.Sprintf("unable to update fields of alert %d: %s", e.AlertID, strings.Join(fields, "; "))
+}
+
+// UpdateAlertFields compares the alert with the current alert in datadog and updates the changed
+// fields one by one. If some fields could not be updated, *AlertFieldsError is returned.
+func (c Client) UpdateAlertFields(ctx context.Context, alert json.RawMessage) error {
+	desired := map[string]json.RawMessage{}
+	if err := json.Unmarshal(alert, &desired); err != nil {
+		return errors.Wrap(err, "unable to unmarshal alert")
+	}
+
+	var id int
+	if err := json.Unmarshal(desired["id"], &id); err != nil || id == 0 {
+		return ErrInvalidAlert
+	}
+
+	currentAlert, err := c.GetAlert(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get alert %d", id)
+	}
+
+	current := map[string]json.RawMessage{}
+	if err := json.Unmarshal(currentAlert, &current); err != nil {
+		return errors.Wrap(err, "unable to unmarshal current alert")
+	}
+
+	fieldErrs := make(map[string]error)
+	for field, value := range desired {
+		if field == "id" || jsonEqual(value, current[field]) {
+			continue
+		}
+
+		body, err := json.Marshal(map[string]json.RawMessage{
+			"id":  desired["id"],
+			field: value,
+		})
+		if err != nil {
+			fieldErrs[field] = err
+			continue
+		}
+
+		if err := c.genericUpdate(ctx, alertType, body); err != nil {
+			fieldErrs[field] = err
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return &AlertFieldsError{
+			AlertID: id,
+			Fields:  fieldErrs,
+		}
+	}
+
+	return nil
+}
+
+// jsonEqual compares two json values ignoring formatting.
+func jsonEqual(a, b json.RawMessage) bool {
+	var av, bv interface{}
+	if err := json.Unmarshal(a, &av); err != nil {
+		return false
+	}
+
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(av, bv)
+}
+
 // GetAlerts returns a list of alerts.
-func (c Client) GetAlerts() (json.RawMessage, error) {
-	return c.do("GET", "alert", nil)
+func (c Client) GetAlerts(ctx context.Context) (json.RawMessage, error) {
+	return c.do(ctx, "GET", "alert", nil)
 }
 
 // GetAlert returns an alert with a given ID.
-func (c Client) GetAlert(id int) (json.RawMessage, error) {
-	resp, err := c.do("GET", fmt.Sprintf("%s/%d", alertType, id), nil)
+func (c Client) GetAlert(ctx context.Context, id int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%d", alertType, id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -33,8 +34,12 @@ func TestClient_GetAlert(t *testing.T) {
 			t.Fatalf("expect url \"/alert/222\" Got %s", r.URL.Path)
 		}
 
-		if r.URL.RawQuery != "api_key=123&application_key=456" {
-			t.Fatalf("expect \"api_key=123&application_key=456\" Got %s", r.URL.RawQuery)
+		if r.URL.RawQuery != "" {
+			t.Fatalf("expect empty query. Got %s", r.URL.RawQuery)
+		}
+
+		if r.Header.Get("DD-API-KEY") != "123" || r.Header.Get("DD-APPLICATION-KEY") != "456" {
+			t.Fatalf("expect api key 123 and application key 456 in headers. Got %v", r.Header)
 		}
 
 		fmt.Fprint(w, alertJSON)
@@ -47,7 +52,7 @@ func TestClient_GetAlert(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	alert, err := c.GetAlert(222)
+	alert, err := c.GetAlert(context.Background(), 222)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -84,7 +89,7 @@ func TestClient_UpdateAlert(t *testing.T) {
 		t.Fatal(err)
 	}
 	c.baseEndpoint = ts.URL
-	err = c.UpdateAlert([]byte(`{"id":55}`))
+	err = c.UpdateAlert(context.Background(), []byte(`{"id":55}`))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -108,7 +113,7 @@ func TestClient_GetAlerts(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetAlerts()
+	_, err = c.GetAlerts(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2,13 +2,16 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/Jeffail/gabs"
 	"github.com/pkg/errors"
@@ -16,29 +19,69 @@ import (
 )
 
 const (
-	datadogAPI = "https://api.datadoghq.com/api/v1"
+	defaultRequestTimeout = time.Second * 30
+
+	defaultMaxRetries = 3
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = time.Second * 30
 )
 
 const (
-	apiKeyField = "api_key"
-	appKeyField = "application_key"
+	apiKeyHeader = "DD-API-KEY"
+	appKeyHeader = "DD-APPLICATION-KEY"
 )
 
+// NotFoundError is returned if a requested component does not exist in datadog, e.g. it was deleted in the UI.
+type NotFoundError struct {
+	URL    string
+	Method string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found, URL %s, method: %s", e.URL, e.Method)
+}
+
+// IsNotFound returns true if the cause of an error is NotFoundError.
+func IsNotFound(err error) bool {
+	_, ok := errors.Cause(err).(*NotFoundError)
+	return ok
+}
+
+// BadRequestError is returned if datadog rejected a request as invalid, the response holds the reason.
+type BadRequestError struct {
+	URL      string
+	Method   string
+	Response []byte
+}
+
+func (e *BadRequestError) Error() string {
+	return fmt.Sprintf("invalid status code %d, URL %s, method: %s, Response: %s", http.StatusBadRequest, e.URL, e.Method, string(e.Response))
+}
+
 // Component stands for datadog component.
 type Component string
 
 const (
 	monitorType     = Component("monitor")
 	dashboardType   = Component("dash")
+	unifiedDashType = Component("dashboard")
 	screenboardType = Component("screen")
 	alertType       = Component("alert")
 	downtimeType    = Component("downtime")
+	syntheticsType  = Component("synthetics/tests")
+	sloType         = Component("slo")
+
+	logPipelineType      = Component("logs/config/pipelines")
+	logPipelineOrderType = Component("logs/config/pipeline-order")
 )
 
 var (
 	// ErrInvalidDashboard is returned if the passed dashboard object is invalid.
 	ErrInvalidDashboard = errors.New("invalid dashboard")
 
+	// ErrInvalidUnifiedDashboard is returned if the passed unified dashboard object is invalid.
+	ErrInvalidUnifiedDashboard = errors.New("invalid unified dashboard")
+
 	// ErrInvalidMonitor is returned if the passed monitor object is invalid.
 	ErrInvalidMonitor = errors.New("invalid monitor")
 
@@ -48,6 +91,15 @@ var (
 	// ErrInvalidScreenboard is returned if the passed screen board object is invalid.
 	ErrInvalidScreenboard = errors.New("invalid screenboard")
 
+	// ErrInvalidSynthetics is returned if the passed synthetic test object is invalid.
+	ErrInvalidSynthetics = errors.New("invalid synthetic test")
+
+	// ErrInvalidSLO is returned if the passed service level objective object is invalid.
+	ErrInvalidSLO = errors.New("invalid service level objective")
+
+	// ErrInvalidLogPipeline is returned if the passed log pipeline object is invalid.
+	ErrInvalidLogPipeline = errors.New("invalid log pipeline")
+
 	// ErrInvalidAlert s returned if the passed alert object in invalid.
 	ErrInvalidAlert = errors.New("invalid alert")
 
@@ -61,9 +113,17 @@ func New(apiKey, appKey string, opts ...Option) (*Client, error) {
 	c := &Client{
 		apiKey:       apiKey,
 		appKey:       appKey,
-		baseEndpoint: datadogAPI,
+		baseEndpoint: siteEndpoint(SiteUS1),
 
 		httpClient: &http.Client{},
+
+		requestTimeout: defaultRequestTimeout,
+
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		rateLimits: newRateLimits(),
+		sleep:      sleepContext,
 	}
 
 	for _, opt := range opts {
@@ -85,50 +145,204 @@ type Client struct {
 	appKey       string
 	httpClient   *http.Client
 
-	removeDashboardFields   []string
-	removeMonitorFields     []string
-	removeAlertFields       []string
-	removeScreenboardFields []string
+	// retries of failed requests
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// requestTimeout limits the time of a single request, zero means no limit.
+	requestTimeout time.Duration
+
+	rateLimits *rateLimits
+	sleep      func(ctx context.Context, d time.Duration) error
+
+	removeDashboardFields        []string
+	removeUnifiedDashboardFields []string
+	removeMonitorFields          []string
+	removeAlertFields            []string
+	removeScreenboardFields      []string
+	removeDowntimeFields         []string
+	removeSyntheticsFields       []string
+	removeSLOFields              []string
 }
 
-func (c Client) do(method, apiCall string, b io.Reader) ([]byte, error) {
-	values := url.Values{}
-	values.Add(apiKeyField, c.apiKey)
-	values.Add(appKeyField, c.appKey)
-
+// do makes a request to datadog API. If the rate limit of the endpoint is exhausted, it waits
+// until the limit resets. Requests rejected with 429 are retried with jittered exponential backoff,
+// idempotent requests are retried on 5xx and network errors as well. A POST which failed that way
+// could have been applied, so it is not retried to not create a component twice.
+func (c Client) do(ctx context.Context, method, apiCall string, b io.Reader) ([]byte, error) {
 	apiURL := strings.Join([]string{c.baseEndpoint, apiCall}, "/")
 	u, err := url.Parse(apiURL)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse URL: %s", apiURL)
 	}
 
-	u.RawQuery = values.Encode()
+	// the body is read once, so it could be sent again on retry.
+	var reqBody []byte
+	if b != nil {
+		reqBody, err = ioutil.ReadAll(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read request body, URL: %s", apiURL)
+		}
+	}
+
+	endpoint := rateLimitEndpoint(apiCall)
+	for attempt := 0; ; attempt++ {
+		if err := c.waitRateLimit(ctx, endpoint); err != nil {
+			return nil, err
+		}
+
+		body, retryAfter, err := c.doOnce(ctx, method, u.String(), endpoint, reqBody)
+		if err == nil {
+			return body, nil
+		}
+
+		if retryAfter < 0 || attempt >= c.maxRetries {
+			return nil, err
+		}
 
-	req, err := http.NewRequest(method, u.String(), b)
+		wait := c.backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+
+		logrus.Warnf("retrying request in %s, attempt %d of %d: %s", wait, attempt+1, c.maxRetries, err)
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, errors.Wrapf(err, "request cancelled, URL: %s", apiURL)
+		}
+	}
+}
+
+// doOnce makes a single request. If the request could be retried, a non negative retry delay is returned
+// along with an error.
+func (c Client) doOnce(ctx context.Context, method, apiURL, endpoint string, reqBody []byte) ([]byte, time.Duration, error) {
+	var b io.Reader
+	if reqBody != nil {
+		b = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequest(method, apiURL, b)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to create a new request, URL: %s", apiURL)
+		return nil, -1, errors.Wrapf(err, "unable to create a new request, URL: %s", apiURL)
+	}
+
+	// the retry delay of the failures after which the request could have been applied.
+	var retryFailed time.Duration
+	if !idempotent(method) {
+		retryFailed = -1
 	}
 
+	// the timeout is applied to every attempt, a timed out attempt is retried.
+	reqCtx := ctx
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+	req = req.WithContext(reqCtx)
+
+	// credentials are sent in headers, so they don't end up in access logs of proxies.
+	req.Header.Set(apiKeyHeader, c.apiKey)
+	req.Header.Set(appKeyHeader, c.appKey)
+
 	logrus.Debugf("[%s] %s", req.Method, req.URL.Path)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to make a request, URL: %s", apiURL)
+		if ctx.Err() != nil {
+			return nil, -1, errors.Wrapf(ctx.Err(), "request cancelled, URL: %s", apiURL)
+		}
+
+		return nil, retryFailed, errors.Wrapf(err, "unable to make a request, URL: %s", apiURL)
 	}
 	defer resp.Body.Close()
 
+	rl, hasRateLimit := parseRateLimit(resp.Header, time.Now())
+	if hasRateLimit {
+		c.rateLimits.set(endpoint, rl)
+	}
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to read response, URL: %s", apiURL)
+		return nil, retryFailed, errors.Wrapf(err, "unable to read response, URL: %s", apiURL)
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		return nil, errors.Errorf("invalid status code %d, URL %s, method: %s, Response: %s", resp.StatusCode, apiURL, method, string(body))
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+		return body, 0, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, -1, &NotFoundError{URL: apiURL, Method: req.Method}
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, -1, &BadRequestError{URL: apiURL, Method: req.Method, Response: body}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if hasRateLimit {
+			retryAfter = rl.Wait(time.Now())
+		}
+
+		return nil, retryAfter, errors.Errorf("rate limited, URL %s, method: %s, Response: %s", apiURL, req.Method, string(body))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, retryFailed, errors.Errorf("invalid status code %d, URL %s, method: %s, Response: %s", resp.StatusCode, apiURL, req.Method, string(body))
+	default:
+		return nil, -1, errors.Errorf("invalid status code %d, URL %s, method: %s, Response: %s", resp.StatusCode, apiURL, req.Method, string(body))
 	}
+}
 
-	return body, nil
+// idempotent returns true if a request with the method could be repeated without changing the result.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
 }
 
-func (c Client) genericUpdate(component Component, monitor json.RawMessage) error {
+// waitRateLimit blocks until the rate limit of the endpoint resets, if there are no requests left.
+func (c Client) waitRateLimit(ctx context.Context, endpoint string) error {
+	rl, ok := c.rateLimits.get(endpoint)
+	if !ok || !rl.Exhausted(time.Now()) {
+		return nil
+	}
+
+	wait := rl.Wait(time.Now())
+	logrus.Warnf("rate limit of %s endpoint is exhausted, waiting %s", endpoint, wait)
+	if err := c.sleep(ctx, wait); err != nil {
+		return errors.Wrapf(err, "waiting for %s rate limit reset cancelled", endpoint)
+	}
+
+	return nil
+}
+
+// backoff returns a random delay between zero and an exponentially growing cap (full jitter).
+func (c Client) backoff(attempt int) time.Duration {
+	ceiling := c.maxBackoff
+	if attempt < 32 {
+		if exp := c.minBackoff << uint(attempt); exp > 0 && exp < ceiling {
+			ceiling = exp
+		}
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// sleepContext sleeps for a given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c Client) genericUpdate(ctx context.Context, component Component, monitor json.RawMessage) error {
 	m := struct {
 		ID int `json:"id"`
 	}{}
@@ -142,7 +356,29 @@ func (c Client) genericUpdate(component Component, monitor json.RawMessage) erro
 		return errInvalidComponent
 	}
 
-	_, err = c.do("PUT", fmt.Sprintf("%s/%d", component, m.ID), bytes.NewReader(monitor))
+	_, err = c.do(ctx, "PUT", fmt.Sprintf("%s/%d", component, m.ID), bytes.NewReader(monitor))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// genericUpdateByStringID updates a component which is identified by a string ID stored in idField.
+func (c Client) genericUpdateByStringID(ctx context.Context, component Component, idField string, body json.RawMessage) error {
+	m := map[string]interface{}{}
+
+	err := json.Unmarshal(body, &m)
+	if err != nil {
+		return errors.Wrapf(err, "unable to unmarshal %s", component)
+	}
+
+	id, _ := m[idField].(string)
+	if id == "" {
+		return errInvalidComponent
+	}
+
+	_, err = c.do(ctx, "PUT", fmt.Sprintf("%s/%s", component, url.PathEscape(id)), bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
@@ -150,6 +386,63 @@ func (c Client) genericUpdate(component Component, monitor json.RawMessage) erro
 	return nil
 }
 
+// genericCreate creates a component from the body without the read only fields. The response body is returned.
+func (c Client) genericCreate(ctx context.Context, component Component, body json.RawMessage, readOnlyFields []string) ([]byte, error) {
+	stripped, err := c.stripJSONFields(body, readOnlyFields)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, "POST", string(component), bytes.NewReader(stripped))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create %s", component)
+	}
+
+	return resp, nil
+}
+
+// genericDelete deletes a component by ID. A component which does not exist is considered deleted.
+func (c Client) genericDelete(ctx context.Context, component Component, id string) error {
+	_, err := c.do(ctx, "DELETE", fmt.Sprintf("%s/%s", component, url.PathEscape(id)), nil)
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete %s %s", component, id)
+	}
+
+	return nil
+}
+
+// createdID reads an ID of a created component from the field of a response.
+// Integer and string IDs are returned as strings.
+func createdID(resp []byte, field string) (string, error) {
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		return "", errors.Wrapf(err, "unable to unmarshal response %s", string(resp))
+	}
+
+	raw, ok := m[field]
+	if !ok {
+		return "", errInvalidComponent
+	}
+
+	var id interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&id); err != nil {
+		return "", errors.Wrapf(err, "unable to unmarshal field %s", field)
+	}
+
+	switch v := id.(type) {
+	case json.Number:
+		return v.String(), nil
+	case string:
+		if v != "" {
+			return v, nil
+		}
+	}
+
+	return "", errInvalidComponent
+}
+
 func (c Client) stripJSONFields(body []byte, fields []string) ([]byte, error) {
 
 	if len(fields) == 0 {
@@ -162,7 +455,12 @@ func (c Client) stripJSONFields(body []byte, fields []string) ([]byte, error) {
 	}
 
 	for _, field := range fields {
-		err = container.Delete(strings.Split(field, ".")...)
+		path := strings.Split(field, ".")
+		if !container.Exists(path...) {
+			continue
+		}
+
+		err = container.Delete(path...)
 		if err != nil {
 			logrus.Errorf("unable to strip fields [%v] from json %s: %s", fields, container, err)
 		}
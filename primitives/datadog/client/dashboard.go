@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -55,8 +56,8 @@ func (dr DashboardsResponse) GetModifiedIDsWithin(interval time.Duration, fn fun
 }
 
 // GetDashboard returns a raw json of dashboard.
-func (c Client) GetDashboard(id int) (json.RawMessage, error) {
-	resp, err := c.do("GET", fmt.Sprintf("%s/%d", dashboardType, id), nil)
+func (c Client) GetDashboard(ctx context.Context, id int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%d", dashboardType, id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +66,7 @@ func (c Client) GetDashboard(id int) (json.RawMessage, error) {
 }
 
 // UpdateDashboard updates the dashboard from json raw message.
-func (c Client) UpdateDashboard(dash json.RawMessage) error {
+func (c Client) UpdateDashboard(ctx context.Context, dash json.RawMessage) error {
 	dashboard := struct {
 		Dash json.RawMessage `json:"dash"`
 	}{}
@@ -75,7 +76,7 @@ func (c Client) UpdateDashboard(dash json.RawMessage) error {
 		return errors.Wrap(err, "unable to unmarshal dashboard, field dash")
 	}
 
-	err = c.genericUpdate(dashboardType, dashboard.Dash)
+	err = c.genericUpdate(ctx, dashboardType, dashboard.Dash)
 	if err != nil {
 		if err == errInvalidComponent {
 			return ErrInvalidDashboard
@@ -87,7 +88,73 @@ func (c Client) UpdateDashboard(dash json.RawMessage) error {
 	return nil
 }
 
+// readOnlyDashboardFields are set by datadog and must not be sent on create.
+var readOnlyDashboardFields = []string{"id", "created", "modified", "created_by", "resource", "url"}
+
+// CreateDashboard creates a dashboard from the same json as UpdateDashboard and returns the new ID.
+func (c Client) CreateDashboard(ctx context.Context, dash json.RawMessage) (int, error) {
+	dashboard := struct {
+		Dash json.RawMessage `json:"dash"`
+	}{}
+
+	err := json.Unmarshal(dash, &dashboard)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to unmarshal dashboard, field dash")
+	}
+
+	if len(dashboard.Dash) == 0 {
+		return 0, ErrInvalidDashboard
+	}
+
+	resp, err := c.genericCreate(ctx, dashboardType, dashboard.Dash, readOnlyDashboardFields)
+	if err != nil {
+		return 0, err
+	}
+
+	created := struct {
+		Dash json.RawMessage `json:"dash"`
+	}{}
+	if err := json.Unmarshal(resp, &created); err != nil {
+		return 0, errors.Wrap(err, "unable to unmarshal created dashboard")
+	}
+
+	id, err := createdID(created.Dash, "id")
+	if err != nil {
+		return 0, ErrInvalidDashboard
+	}
+
+	return strconv.Atoi(id)
+}
+
 // GetDashboards returns a list of dashboards.
-func (c Client) GetDashboards() (DashboardsResponse, error) {
-	return c.do("GET", "dash", nil)
+func (c Client) GetDashboards(ctx context.Context) (DashboardsResponse, error) {
+	return c.do(ctx, "GET", "dash", nil)
+}
+
+// DeleteDashboard deletes a dashboard by ID.
+func (c Client) DeleteDashboard(ctx context.Context, id int) error {
+	return c.genericDelete(ctx, dashboardType, strconv.Itoa(id))
+}
+
+// Summaries returns IDs, titles and creators of the dashboards.
+func (dr DashboardsResponse) Summaries() ([]Summary, error) {
+	var resp struct {
+		Dashes []struct {
+			ID        string   `json:"id"`
+			Title     string   `json:"title"`
+			CreatedBy *creator `json:"created_by"`
+		}
+	}
+
+	err := json.Unmarshal(dr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal dashboards response")
+	}
+
+	var summaries []Summary
+	for _, d := range resp.Dashes {
+		summaries = append(summaries, Summary{ID: d.ID, Title: d.Title, Creator: d.CreatedBy.String()})
+	}
+
+	return summaries, nil
 }
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -82,7 +83,7 @@ func TestClient_UpdateDashboard(t *testing.T) {
 }
 `)
 
-	err = c.UpdateDashboard(exampleDashboard)
+	err = c.UpdateDashboard(context.Background(), exampleDashboard)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -106,7 +107,7 @@ func TestClient_GetDashboard(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetDashboard(42)
+	_, err = c.GetDashboard(context.Background(), 42)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -144,7 +145,7 @@ func TestClient_GetDashboards(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	dashes, err := c.GetDashboards()
+	dashes, err := c.GetDashboards(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -165,3 +166,104 @@ func TestClient_GetDashboards(t *testing.T) {
 		t.Fatalf("expect ids 22 and 33. Got %v", ids)
 	}
 }
+
+func TestClient_CreateDashboard(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/dash" {
+			t.Fatalf("expect POST /dash. Got %s %s", r.Method, r.URL.Path)
+		}
+
+		body := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := body["id"]; ok {
+			t.Fatalf("expect id not to be sent. Got %v", body)
+		}
+
+		if body["title"] != "new dashboard" {
+			t.Fatalf("expect title \"new dashboard\". Got %v", body["title"])
+		}
+
+		fmt.Fprint(w, `{"dash":{"id":1234,"title":"new dashboard"}}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	id, err := c.CreateDashboard(context.Background(), json.RawMessage(`{"dash":{"id":1,"title":"new dashboard"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 1234 {
+		t.Fatalf("expect id 1234. Got %d", id)
+	}
+
+	if _, err := c.CreateDashboard(context.Background(), json.RawMessage(`{}`)); err != ErrInvalidDashboard {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidDashboard, err)
+	}
+}
+
+func TestClient_DeleteDashboard(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("expect DELETE. Got %s", r.Method)
+		}
+
+		deleted = append(deleted, r.URL.Path)
+
+		// a dashboard which was already deleted is not an error
+		if r.URL.Path == "/dash/2" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	for _, id := range []int{1, 2} {
+		if err := c.DeleteDashboard(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(deleted) != 2 || deleted[0] != "/dash/1" || deleted[1] != "/dash/2" {
+		t.Fatalf("expect DELETE /dash/1 and /dash/2. Got %v", deleted)
+	}
+}
+
+func TestClient_GetDashboardNotFound(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":["Dashboard not found"]}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	_, err = c.GetDashboard(context.Background(), 1)
+	if !IsNotFound(err) {
+		t.Fatalf("expect not found error. Got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expect a not found request not to be retried. Got %d calls", calls)
+	}
+}
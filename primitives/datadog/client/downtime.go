@@ -1,8 +1,13 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -18,15 +23,117 @@ type Downtime struct {
 	ID        int    `json:"id"`
 	UpdaterID int    `json:"updater_id"`
 	Message   string `json:"message"`
+
+	// Canceled is a time the downtime was canceled, nil if the downtime is not canceled.
+	Canceled *uint64 `json:"canceled"`
+}
+
+// readOnlyDowntimeFields are set by datadog and must not be sent on create.
+var readOnlyDowntimeFields = []string{"id", "org_id", "creator_id", "updater_id", "active", "canceled", "child_id", "parent_id", "created", "modified"}
+
+// volatileDowntimeFields are changed by datadog on its own, e.g. when a downtime starts or ends.
+var volatileDowntimeFields = []string{"active", "child_id"}
+
+// recurringDowntimeFields are rewritten by datadog on every occurrence of a recurring downtime.
+var recurringDowntimeFields = []string{"start", "end"}
+
+// DowntimesResponse represents a response from calling /api/v1/downtime endpoint.
+type DowntimesResponse json.RawMessage
+
+// Fingerprints returns a mapping of a downtime ID to a hash of the downtime content. Downtimes do not
+// have a modified field, the fingerprints are used to detect a change between two calls.
+// The fields datadog changes on its own are not included, so a recurring downtime moving to its next
+// occurrence is not considered a change.
+func (dr DowntimesResponse) Fingerprints() (map[string]string, error) {
+	var downtimes []json.RawMessage
+	err := json.Unmarshal(dr, &downtimes)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal downtimes response")
+	}
+
+	fingerprints := make(map[string]string)
+	for _, downtime := range downtimes {
+		fields := map[string]interface{}{}
+
+		// decode numbers as json.Number, so IDs and timestamps are hashed as returned
+		dec := json.NewDecoder(bytes.NewReader(downtime))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal downtime")
+		}
+
+		id, ok := fields["id"].(json.Number)
+		if !ok {
+			return nil, ErrInvalidDowntime
+		}
+
+		removeVolatileDowntimeFields(fields)
+
+		// map keys are sorted by encoding/json, the hash does not depend on the order of fields.
+		body, err := json.Marshal(fields)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to marshal downtime %s", id)
+		}
+
+		sum := sha256.Sum256(body)
+		fingerprints[id.String()] = hex.EncodeToString(sum[:])
+	}
+
+	return fingerprints, nil
+}
+
+// NormalizeDowntime returns a downtime payload without the fields datadog changes on its own, the same
+// fields are not included in the fingerprints. It is used to compare a downtime by content, e.g. a
+// recurring downtime moving to its next occurrence is not considered a change.
+func NormalizeDowntime(payload json.RawMessage) (json.RawMessage, error) {
+	fields := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal downtime")
+	}
+
+	removeVolatileDowntimeFields(fields)
+
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal downtime")
+	}
+
+	return body, nil
+}
+
+// removeVolatileDowntimeFields removes the fields datadog changes on its own from a decoded downtime.
+func removeVolatileDowntimeFields(fields map[string]interface{}) {
+	for _, field := range volatileDowntimeFields {
+		delete(fields, field)
+	}
+
+	if fields["recurrence"] != nil {
+		for _, field := range recurringDowntimeFields {
+			delete(fields, field)
+		}
+	}
 }
 
 // Downtimes is a list of downtimes
 type Downtimes []*Downtime
 
-// GetByMonitorID returns a downtime by a monitor ID.
+// GetByMonitorID returns a downtime by a monitor ID. Canceled downtimes are ignored.
 func (d Downtimes) GetByMonitorID(id int) *Downtime {
 	for _, downtime := range d {
-		if downtime.MonitorID == id {
+		if downtime.MonitorID == id && downtime.Canceled == nil {
+			return downtime
+		}
+	}
+
+	return nil
+}
+
+// GetByID returns a downtime by ID. Canceled downtimes are ignored.
+func (d Downtimes) GetByID(id int) *Downtime {
+	for _, downtime := range d {
+		if downtime.ID == id && downtime.Canceled == nil {
 			return downtime
 		}
 	}
@@ -35,8 +142,8 @@ func (d Downtimes) GetByMonitorID(id int) *Downtime {
 }
 
 // GetDowntimes returns a downtimes
-func (c Client) GetDowntimes() (Downtimes, error) {
-	body, err := c.do("GET", "downtime", nil)
+func (c Client) GetDowntimes(ctx context.Context) (Downtimes, error) {
+	body, err := c.do(ctx, "GET", "downtime", nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get downtimes")
 	}
@@ -50,19 +157,59 @@ func (c Client) GetDowntimes() (Downtimes, error) {
 	return downtimes, nil
 }
 
+// GetDowntimesResponse returns all downtimes as they are returned by datadog.
+func (c Client) GetDowntimesResponse(ctx context.Context) (DowntimesResponse, error) {
+	return c.do(ctx, "GET", string(downtimeType), nil)
+}
+
 // GetDowntime returns a downtime
-func (c Client) GetDowntime(id int) (json.RawMessage, error) {
-	resp, err := c.do("GET", fmt.Sprintf("%s/%d", downtimeType, id), nil)
+func (c Client) GetDowntime(ctx context.Context, id int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%d", downtimeType, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	return c.stripJSONFields(resp, c.removeDowntimeFields)
+}
+
+// CreateDowntime creates a new downtime and returns its ID. The fields set by datadog are removed
+// from the payload, so a downtime stored in git could be created again.
+func (c Client) CreateDowntime(ctx context.Context, downtime json.RawMessage) (int, error) {
+	body, err := c.stripJSONFields(downtime, readOnlyDowntimeFields)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(ctx, "POST", string(downtimeType), bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create a downtime")
+	}
+
+	created := &Downtime{}
+	if err := json.Unmarshal(resp, created); err != nil {
+		return 0, errors.Wrap(err, "unable to unmarshal created downtime")
+	}
+
+	if created.ID == 0 {
+		return 0, ErrInvalidDowntime
+	}
+
+	return created.ID, nil
+}
+
+// CancelDowntime cancels a downtime by ID.
+func (c Client) CancelDowntime(ctx context.Context, id int) error {
+	_, err := c.do(ctx, "DELETE", fmt.Sprintf("%s/%d", downtimeType, id), nil)
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "unable to cancel downtime %d", id)
+	}
+
+	return nil
 }
 
 // UpdateDowntime updates a downtime.
-func (c Client) UpdateDowntime(downtime json.RawMessage) error {
-	err := c.genericUpdate(downtimeType, downtime)
+func (c Client) UpdateDowntime(ctx context.Context, downtime json.RawMessage) error {
+	err := c.genericUpdate(ctx, downtimeType, downtime)
 	if err != nil {
 		if err == errInvalidComponent {
 			return ErrInvalidDowntime
@@ -73,3 +220,36 @@ func (c Client) UpdateDowntime(downtime json.RawMessage) error {
 
 	return nil
 }
+
+// Summaries returns IDs, messages, scopes and creator IDs of the active downtimes. Downtimes have no title
+// and tags, the message and the scope are used instead.
+func (dr DowntimesResponse) Summaries() ([]Summary, error) {
+	var resp []struct {
+		ID        int      `json:"id"`
+		Message   string   `json:"message"`
+		Scope     []string `json:"scope"`
+		CreatorID int      `json:"creator_id"`
+		Canceled  *uint64  `json:"canceled"`
+	}
+
+	err := json.Unmarshal(dr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal downtimes response")
+	}
+
+	var summaries []Summary
+	for _, d := range resp {
+		if d.Canceled != nil {
+			continue
+		}
+
+		var creatorID string
+		if d.CreatorID != 0 {
+			creatorID = strconv.Itoa(d.CreatorID)
+		}
+
+		summaries = append(summaries, Summary{ID: strconv.Itoa(d.ID), Title: d.Message, Tags: d.Scope, Creator: creatorID})
+	}
+
+	return summaries, nil
+}
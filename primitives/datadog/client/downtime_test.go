@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDowntimesFingerprints(t *testing.T) {
+	before := DowntimesResponse(`[
+  {"id": 1, "message": "maintenance", "start": 1000, "end": 2000, "active": false, "child_id": null, "recurrence": {"type": "weeks", "period": 1}},
+  {"id": 2, "message": "deploy", "start": 1000, "end": 2000, "active": false, "recurrence": null},
+  {"id": 3, "message": "migration", "start": 1000, "end": 2000, "active": false, "recurrence": null}
+]`)
+
+	// datadog moved the recurring downtime to its next occurrence and started downtime 2,
+	// downtime 3 was extended by a user.
+	after := DowntimesResponse(`[
+  {"id": 1, "message": "maintenance", "start": 5000, "end": 6000, "active": true, "child_id": 10, "recurrence": {"type": "weeks", "period": 1}},
+  {"id": 2, "message": "deploy", "start": 1000, "end": 2000, "active": true, "recurrence": null},
+  {"id": 3, "message": "migration", "start": 1000, "end": 3000, "active": false, "recurrence": null}
+]`)
+
+	beforeFingerprints, err := before.Fingerprints()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	afterFingerprints, err := after.Fingerprints()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(afterFingerprints) != 3 {
+		t.Fatalf("expect 3 fingerprints. Got %d", len(afterFingerprints))
+	}
+
+	for _, id := range []string{"1", "2"} {
+		if beforeFingerprints[id] != afterFingerprints[id] {
+			t.Fatalf("expect downtime %s to have the same fingerprint", id)
+		}
+	}
+
+	if beforeFingerprints["3"] == afterFingerprints["3"] {
+		t.Fatal("expect downtime 3 to have a different fingerprint")
+	}
+
+	if _, err := DowntimesResponse(`[{"message": "no id"}]`).Fingerprints(); err != ErrInvalidDowntime {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidDowntime, err)
+	}
+}
+
+func TestNormalizeDowntime(t *testing.T) {
+	before, err := NormalizeDowntime([]byte(`{"id": 1, "message": "maintenance", "start": 1000, "end": 2000, "active": false, "recurrence": {"type": "weeks", "period": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NormalizeDowntime([]byte(`{"id": 1, "message": "maintenance", "start": 5000, "end": 6000, "active": true, "child_id": 10, "recurrence": {"type": "weeks", "period": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(before) != string(after) {
+		t.Fatalf("expect the next occurrence of a recurring downtime to be the same. Got %s and %s", before, after)
+	}
+
+	normalized, err := NormalizeDowntime([]byte(`{"id": 2, "start": 1000, "end": 2000, "recurrence": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expect := `{"end":2000,"id":2,"recurrence":null,"start":1000}`; string(normalized) != expect {
+		t.Fatalf("expect %s. Got %s", expect, normalized)
+	}
+}
+
+func TestGetDowntime(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/downtime/55" {
+			t.Fatalf("expect url \"/downtime/55\" Got %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"id":55,"message":"deploy","active":true}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456", WithRemoveDowntimeFields([]string{"active"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	downtime, err := c.GetDowntime(context.Background(), 55)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(downtime) != `{"id":55,"message":"deploy"}` {
+		t.Fatalf("expect downtime without active field. Got %s", downtime)
+	}
+}
+
+func TestDowntimesResponse_Summaries(t *testing.T) {
+	dr := DowntimesResponse(`[{"id":1,"message":"deploy","scope":["env:prod"],"creator_id":42},{"id":2,"canceled":1412799983}]`)
+	summaries, err := dr.Summaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summaries) != 1 {
+		t.Fatalf("expect canceled downtime to be skipped. Got %+v", summaries)
+	}
+
+	if s := summaries[0]; s.ID != "1" || s.Title != "deploy" || len(s.Tags) != 1 || s.Tags[0] != "env:prod" || s.Creator != "42" {
+		t.Fatalf("expect downtime 1 created by 42. Got %+v", s)
+	}
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// readOnlyLogPipelineFields are returned by datadog, but must not be sent back on update.
+var readOnlyLogPipelineFields = []string{"id", "type", "is_read_only"}
+
+// LogPipeline represents a log pipeline with its processors and its position in the pipeline order.
+type LogPipeline struct {
+	Pipeline json.RawMessage `json:"pipeline"`
+	Position int             `json:"position"`
+}
+
+// LogPipelinesResponse represents a response from calling /api/v1/logs/config/pipelines endpoint.
+type LogPipelinesResponse json.RawMessage
+
+// Fingerprints returns a mapping of a pipeline ID to a hash of the pipeline content and its position
+// in the given pipeline order. Log pipelines do not have a modified field, the fingerprints are used to
+// detect a change between two calls.
+func (lr LogPipelinesResponse) Fingerprints(order []string) (map[string]string, error) {
+	var pipelines []json.RawMessage
+	err := json.Unmarshal(lr, &pipelines)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal log pipelines response")
+	}
+
+	positions := make(map[string]int)
+	for position, id := range order {
+		positions[id] = position
+	}
+
+	fingerprints := make(map[string]string)
+	for _, pipeline := range pipelines {
+		p := struct {
+			ID string `json:"id"`
+		}{}
+
+		if err := json.Unmarshal(pipeline, &p); err != nil {
+			return nil, errors.Wrap(err, "unable to unmarshal log pipeline")
+		}
+
+		position, ok := positions[p.ID]
+		if !ok {
+			position = -1
+		}
+
+		buf := new(bytes.Buffer)
+		if err := json.Compact(buf, pipeline); err != nil {
+			return nil, errors.Wrapf(err, "unable to compact log pipeline %s", p.ID)
+		}
+
+		sum := sha256.Sum256(append(buf.Bytes(), []byte(fmt.Sprintf("/%d", position))...))
+		fingerprints[p.ID] = hex.EncodeToString(sum[:])
+	}
+
+	return fingerprints, nil
+}
+
+// GetLogPipelines returns a list of all log pipelines.
+func (c Client) GetLogPipelines(ctx context.Context) (LogPipelinesResponse, error) {
+	return c.do(ctx, "GET", string(logPipelineType), nil)
+}
+
+// GetLogPipelineOrder returns a list of pipeline IDs in the order they are applied.
+func (c Client) GetLogPipelineOrder(ctx context.Context) ([]string, error) {
+	resp, err := c.do(ctx, "GET", string(logPipelineOrderType), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	order := struct {
+		PipelineIDs []string `json:"pipeline_ids"`
+	}{}
+
+	err = json.Unmarshal(resp, &order)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal log pipeline order")
+	}
+
+	return order.PipelineIDs, nil
+}
+
+// UpdateLogPipelineOrder updates the order of log pipelines. The list must contain all pipeline IDs.
+func (c Client) UpdateLogPipelineOrder(ctx context.Context, ids []string) error {
+	body, err := json.Marshal(struct {
+		PipelineIDs []string `json:"pipeline_ids"`
+	}{ids})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal log pipeline order")
+	}
+
+	_, err = c.do(ctx, "PUT", string(logPipelineOrderType), bytes.NewReader(body))
+	return err
+}
+
+// GetLogPipeline returns a log pipeline and its position in the pipeline order.
+func (c Client) GetLogPipeline(ctx context.Context, id string) (*LogPipeline, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%s", logPipelineType, url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := c.GetLogPipelineOrder(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get log pipeline order")
+	}
+
+	position := indexOf(order, id)
+	if position == -1 {
+		return nil, errors.Errorf("log pipeline %s not found in pipeline order %v", id, order)
+	}
+
+	return &LogPipeline{
+		Pipeline: resp,
+		Position: position,
+	}, nil
+}
+
+// UpdateLogPipeline updates a log pipeline and moves it to the stored position in the pipeline order.
+func (c Client) UpdateLogPipeline(ctx context.Context, p *LogPipeline) error {
+	if p == nil {
+		return ErrInvalidLogPipeline
+	}
+
+	m := struct {
+		ID string `json:"id"`
+	}{}
+
+	err := json.Unmarshal(p.Pipeline, &m)
+	if err != nil {
+		return errors.Wrap(err, "unable to unmarshal log pipeline")
+	}
+
+	if m.ID == "" {
+		return ErrInvalidLogPipeline
+	}
+
+	body, err := c.stripJSONFields(p.Pipeline, readOnlyLogPipelineFields)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, "PUT", fmt.Sprintf("%s/%s", logPipelineType, url.PathEscape(m.ID)), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	order, err := c.GetLogPipelineOrder(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get log pipeline order")
+	}
+
+	newOrder, changed := moveTo(order, m.ID, p.Position)
+	if !changed {
+		return nil
+	}
+
+	return c.UpdateLogPipelineOrder(ctx, newOrder)
+}
+
+// CreateLogPipeline creates a log pipeline, moves it to the stored position in the pipeline order
+// and returns the new ID.
+func (c Client) CreateLogPipeline(ctx context.Context, p *LogPipeline) (string, error) {
+	if p == nil || len(p.Pipeline) == 0 {
+		return "", ErrInvalidLogPipeline
+	}
+
+	resp, err := c.genericCreate(ctx, logPipelineType, p.Pipeline, readOnlyLogPipelineFields)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := createdID(resp, "id")
+	if err != nil {
+		return "", ErrInvalidLogPipeline
+	}
+
+	order, err := c.GetLogPipelineOrder(ctx)
+	if err != nil {
+		return id, errors.Wrap(err, "unable to get log pipeline order")
+	}
+
+	newOrder, changed := moveTo(order, id, p.Position)
+	if !changed {
+		return id, nil
+	}
+
+	return id, c.UpdateLogPipelineOrder(ctx, newOrder)
+}
+
+func indexOf(ids []string, id string) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// moveTo moves the id to a given position, the position is capped by the size of a slice.
+// Returns false if the id is already at the position or not found.
+func moveTo(ids []string, id string, position int) ([]string, bool) {
+	current := indexOf(ids, id)
+	if current == -1 || current == position {
+		return ids, false
+	}
+
+	var out []string
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+
+	if position < 0 {
+		position = 0
+	}
+
+	if position > len(out) {
+		position = len(out)
+	}
+
+	out = append(out[:position], append([]string{id}, out[position:]...)...)
+	return out, true
+}
+
+// DeleteLogPipeline deletes a log pipeline by ID.
+func (c Client) DeleteLogPipeline(ctx context.Context, id string) error {
+	return c.genericDelete(ctx, logPipelineType, id)
+}
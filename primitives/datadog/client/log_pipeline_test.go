@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var exampleLogPipelinesResponse = `
+[
+  {
+    "id": "aaa",
+    "type": "pipeline",
+    "name": "nginx",
+    "is_enabled": true,
+    "is_read_only": false,
+    "filter": {"query": "source:nginx"},
+    "processors": [{"type": "grok-parser", "name": "parse", "is_enabled": true, "source": "message"}]
+  },
+  {
+    "id": "bbb",
+    "type": "pipeline",
+    "name": "api",
+    "is_enabled": true,
+    "is_read_only": false,
+    "filter": {"query": "service:api"},
+    "processors": []
+  }
+]
+`
+
+func TestLogPipelinesResponse_Fingerprints(t *testing.T) {
+	lr := LogPipelinesResponse(json.RawMessage(exampleLogPipelinesResponse))
+
+	fingerprints, err := lr.Fingerprints([]string{"aaa", "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fingerprints) != 2 {
+		t.Fatalf("expect 2 fingerprints. Got %d", len(fingerprints))
+	}
+
+	// changing the order must change the fingerprints
+	reordered, err := lr.Fingerprints([]string{"bbb", "aaa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"aaa", "bbb"} {
+		if fingerprints[id] == reordered[id] {
+			t.Fatalf("expect fingerprint of %s to change after reorder", id)
+		}
+	}
+
+	// the same input must produce the same fingerprints
+	same, err := lr.Fingerprints([]string{"aaa", "bbb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(fingerprints, same) {
+		t.Fatalf("expect the same fingerprints %v. Got %v", fingerprints, same)
+	}
+}
+
+func TestClient_GetLogPipeline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logs/config/pipelines/bbb":
+			fmt.Fprint(w, `{"id":"bbb","name":"api"}`)
+		case "/logs/config/pipeline-order":
+			fmt.Fprint(w, `{"pipeline_ids":["aaa","bbb"]}`)
+		default:
+			t.Fatalf("unexpected url %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	p, err := c.GetLogPipeline(context.Background(), "bbb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Position != 1 {
+		t.Fatalf("expect position 1. Got %d", p.Position)
+	}
+}
+
+func TestClient_UpdateLogPipeline(t *testing.T) {
+	var newOrder []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/logs/config/pipelines/bbb":
+			pipeline := map[string]interface{}{}
+			if err := json.Unmarshal(body, &pipeline); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, field := range readOnlyLogPipelineFields {
+				if _, ok := pipeline[field]; ok {
+					t.Fatalf("expect field %s to be removed. Got %s", field, body)
+				}
+			}
+		case r.Method == "GET" && r.URL.Path == "/logs/config/pipeline-order":
+			fmt.Fprint(w, `{"pipeline_ids":["aaa","bbb","ccc"]}`)
+		case r.Method == "PUT" && r.URL.Path == "/logs/config/pipeline-order":
+			order := struct {
+				PipelineIDs []string `json:"pipeline_ids"`
+			}{}
+			if err := json.Unmarshal(body, &order); err != nil {
+				t.Fatal(err)
+			}
+			newOrder = order.PipelineIDs
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	err = c.UpdateLogPipeline(context.Background(), &LogPipeline{
+		Pipeline: []byte(`{"id":"bbb","type":"pipeline","is_read_only":false,"name":"api"}`),
+		Position: 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOrder := []string{"bbb", "aaa", "ccc"}
+	if !reflect.DeepEqual(newOrder, expectedOrder) {
+		t.Fatalf("expect order %v. Got %v", expectedOrder, newOrder)
+	}
+
+	err = c.UpdateLogPipeline(context.Background(), &LogPipeline{Pipeline: []byte(`{"name":"api"}`)})
+	if err != ErrInvalidLogPipeline {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidLogPipeline, err)
+	}
+}
+
+func TestCreateLogPipeline(t *testing.T) {
+	var order []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/logs/config/pipelines":
+			fmt.Fprint(w, `{"id":"ccc","name":"new"}`)
+		case r.Method == "GET" && r.URL.Path == "/logs/config/pipeline-order":
+			fmt.Fprint(w, `{"pipeline_ids":["aaa","bbb","ccc"]}`)
+		case r.Method == "PUT" && r.URL.Path == "/logs/config/pipeline-order":
+			body := struct {
+				PipelineIDs []string `json:"pipeline_ids"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			order = body.PipelineIDs
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	id, err := c.CreateLogPipeline(context.Background(), &LogPipeline{
+		Pipeline: json.RawMessage(`{"id":"old","name":"new","is_read_only":false}`),
+		Position: 0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "ccc" {
+		t.Fatalf("expect id ccc. Got %s", id)
+	}
+
+	if !reflect.DeepEqual(order, []string{"ccc", "aaa", "bbb"}) {
+		t.Fatalf("expect the new pipeline to be moved to the first position. Got %v", order)
+	}
+}
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -47,32 +50,103 @@ func (mr MonitorsResponse) GetModifiedIDsWithin(interval time.Duration, fn func(
 }
 
 // MonitorWithDependencies represents a monitor with dependencies like Alert and Downtime.
+// DowntimeTracked is set if the downtime of the monitor was requested. The monitor files written before
+// the downtime was tracked hold a null downtime without the marker, so a null downtime means "removed"
+// only if the marker is set.
 type MonitorWithDependencies struct {
-	Monitor  json.RawMessage `json:"monitor"`
-	Alert    json.RawMessage `json:"alert"`
-	Downtime json.RawMessage `json:"downtime"`
+	Monitor         json.RawMessage `json:"monitor"`
+	Alert           json.RawMessage `json:"alert"`
+	Downtime        json.RawMessage `json:"downtime"`
+	DowntimeTracked bool            `json:"downtime_tracked,omitempty"`
 }
 
-// UpdateMonitorWithDependencies will update the dashboard and dependencies.
-func (c Client) UpdateMonitorWithDependencies(m *MonitorWithDependencies) error {
-	err := c.UpdateMonitor(m.Monitor)
+// UpdateMonitorWithDependencies will update the monitor and its dependencies.
+// The alert fields are updated one by one, if some of them fail *AlertFieldsError is returned after
+// the downtime is restored. The downtime attached to the monitor is updated, created if it does not
+// exist in datadog anymore or canceled if there is no downtime in the given payload. The downtime in
+// datadog is left as it is if the payload did not track the downtime.
+func (c Client) UpdateMonitorWithDependencies(ctx context.Context, m *MonitorWithDependencies) error {
+	err := c.UpdateMonitor(ctx, m.Monitor)
 	if err != nil {
 		return errors.Wrap(err, "unable to update the monitor")
 	}
 
-	// TODO: we are not updating alerts or downtimes on purpose due to a bug
-	//       in datadog, which will throw an error anytime we try to update an alert.
-	return nil
+	// the alert error is returned after the downtime is restored
+	var alertErr error
+	if !isNull(m.Alert) {
+		alertErr = c.UpdateAlertFields(ctx, m.Alert)
+	}
+
+	if isNull(m.Downtime) && !m.DowntimeTracked {
+		return alertErr
+	}
+
+	monitor := struct {
+		ID int `json:"id"`
+	}{}
+	if err := json.Unmarshal(m.Monitor, &monitor); err != nil {
+		return errors.Wrap(err, "unable to unmarshal the monitor")
+	}
+
+	if err := c.restoreMonitorDowntime(ctx, monitor.ID, m.Downtime); err != nil {
+		return errors.Wrap(err, "unable to restore the monitor downtime")
+	}
+
+	return alertErr
+}
+
+// restoreMonitorDowntime makes the downtime of a monitor in datadog match the given downtime.
+func (c Client) restoreMonitorDowntime(ctx context.Context, monitorID int, downtime json.RawMessage) error {
+	downtimes, err := c.GetDowntimes(ctx)
+	if err != nil {
+		return err
+	}
+	current := downtimes.GetByMonitorID(monitorID)
+
+	// the downtime was removed from git, cancel the downtime in datadog
+	if isNull(downtime) {
+		if current == nil {
+			return nil
+		}
+
+		return c.CancelDowntime(ctx, current.ID)
+	}
+
+	desired := &Downtime{}
+	if err := json.Unmarshal(downtime, desired); err != nil {
+		return errors.Wrap(err, "unable to unmarshal the downtime")
+	}
+
+	// a different downtime was attached to the monitor, cancel it
+	if current != nil && current.ID != desired.ID {
+		if err := c.CancelDowntime(ctx, current.ID); err != nil {
+			return err
+		}
+	}
+
+	// the stored downtime still exists, update it
+	if downtimes.GetByID(desired.ID) != nil {
+		return c.UpdateDowntime(ctx, downtime)
+	}
+
+	// the stored downtime was canceled or removed in datadog, create it again
+	_, err = c.CreateDowntime(ctx, downtime)
+	return err
+}
+
+// isNull returns true if the raw message is empty or json null.
+func isNull(m json.RawMessage) bool {
+	return len(m) == 0 || string(m) == "null"
 }
 
 // GetMonitorWithDependencies returns a monitor with dependencies.
-func (c Client) GetMonitorWithDependencies(id int, includeDowntime bool) (*MonitorWithDependencies, error) {
-	monitor, err := c.GetMonitor(id)
+func (c Client) GetMonitorWithDependencies(ctx context.Context, id int, includeDowntime bool) (*MonitorWithDependencies, error) {
+	monitor, err := c.GetMonitor(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	alert, err := c.GetAlert(id)
+	alert, err := c.GetAlert(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +156,7 @@ func (c Client) GetMonitorWithDependencies(id int, includeDowntime bool) (*Monit
 	// try to find a downtime for a given monitor
 	if includeDowntime {
 		// get a list of all downtimes
-		downtimes, err := c.GetDowntimes()
+		downtimes, err := c.GetDowntimes(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -91,25 +165,134 @@ func (c Client) GetMonitorWithDependencies(id int, includeDowntime bool) (*Monit
 		d := downtimes.GetByMonitorID(id)
 		if d != nil {
 			// if the monitor was found, get the raw message for the full downtime
-			downtime, err = c.GetDowntime(d.ID)
+			downtime, err = c.GetDowntime(ctx, d.ID)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return &MonitorWithDependencies{
-		Monitor:  monitor,
-		Alert:    alert,
-		Downtime: downtime,
+		Monitor:         monitor,
+		Alert:           alert,
+		Downtime:        downtime,
+		DowntimeTracked: includeDowntime,
 	}, nil
 }
 
+// NormalizeMonitorWithDependencies returns a monitor payload with the downtime normalized by NormalizeDowntime.
+func NormalizeMonitorWithDependencies(payload json.RawMessage) (json.RawMessage, error) {
+	monitor := &MonitorWithDependencies{}
+	if err := json.Unmarshal(payload, monitor); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal a monitor")
+	}
+
+	if isNull(monitor.Downtime) {
+		return payload, nil
+	}
+
+	downtime, err := NormalizeDowntime(monitor.Downtime)
+	if err != nil {
+		return nil, err
+	}
+	monitor.Downtime = downtime
+
+	body, err := json.Marshal(monitor)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal a monitor")
+	}
+
+	return body, nil
+}
+
+// readOnlyMonitorFields are set by datadog and must not be sent on create.
+var readOnlyMonitorFields = []string{"id", "org_id", "created", "created_at", "modified", "creator", "deleted",
+	"overall_state", "overall_state_modified", "state", "matching_downtimes", "multi"}
+
+// CreateMonitor creates a monitor and returns the new ID.
+func (c Client) CreateMonitor(ctx context.Context, monitor json.RawMessage) (int, error) {
+	resp, err := c.genericCreate(ctx, monitorType, monitor, readOnlyMonitorFields)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createdID(resp, "id")
+	if err != nil {
+		return 0, ErrInvalidMonitor
+	}
+
+	return strconv.Atoi(id)
+}
+
+// CreateMonitorWithDependencies creates a monitor and its downtime, if there is one, and returns the new
+// monitor ID. The alert is a different representation of the same monitor, so it is not created separately.
+func (c Client) CreateMonitorWithDependencies(ctx context.Context, m *MonitorWithDependencies) (int, error) {
+	id, err := c.CreateMonitor(ctx, m.Monitor)
+	if err != nil {
+		return 0, err
+	}
+
+	if isNull(m.Downtime) {
+		return id, nil
+	}
+
+	downtime := map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(m.Downtime))
+	dec.UseNumber()
+	if err := dec.Decode(&downtime); err != nil {
+		return id, errors.Wrap(err, "unable to unmarshal the monitor downtime")
+	}
+
+	// attach the downtime to the new monitor
+	downtime["monitor_id"] = id
+	body, err := json.Marshal(downtime)
+	if err != nil {
+		return id, errors.Wrap(err, "unable to marshal the monitor downtime")
+	}
+
+	if _, err := c.CreateDowntime(ctx, body); err != nil {
+		return id, errors.Wrapf(err, "monitor %d was created, but the downtime was not", id)
+	}
+
+	return id, nil
+}
+
+// ValidateMonitor validates a monitor definition without saving it and returns the errors found by datadog,
+// e.g. an invalid query. Only the fields accepted on create are sent, so a monitor with an ID could be validated.
+func (c Client) ValidateMonitor(ctx context.Context, monitor json.RawMessage) ([]string, error) {
+	stripped, err := c.stripJSONFields(monitor, readOnlyMonitorFields)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.do(ctx, "POST", fmt.Sprintf("%s/validate", monitorType), bytes.NewReader(stripped))
+	if err == nil {
+		return nil, nil
+	}
+
+	badRequest, ok := errors.Cause(err).(*BadRequestError)
+	if !ok {
+		return nil, errors.Wrap(err, "unable to validate monitor")
+	}
+
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(badRequest.Response, &resp); err != nil || len(resp.Errors) == 0 {
+		return []string{string(badRequest.Response)}, nil
+	}
+
+	return resp.Errors, nil
+}
+
 // GetMonitors returns a list of all monitors.
-func (c Client) GetMonitors() (MonitorsResponse, error) {
-	return c.do("GET", "monitor", nil)
+func (c Client) GetMonitors(ctx context.Context) (MonitorsResponse, error) {
+	return c.do(ctx, "GET", "monitor", nil)
 }
 
 // UpdateMonitor updates a monitor from raw message.
-func (c Client) UpdateMonitor(monitor json.RawMessage) error {
-	err := c.genericUpdate(monitorType, monitor)
+func (c Client) UpdateMonitor(ctx context.Context, monitor json.RawMessage) error {
+	err := c.genericUpdate(ctx, monitorType, monitor)
 	if err != nil {
 		if err == errInvalidComponent {
 			return ErrInvalidDashboard
@@ -122,11 +305,38 @@ func (c Client) UpdateMonitor(monitor json.RawMessage) error {
 }
 
 // GetMonitor returns a monitor by ID
-func (c Client) GetMonitor(id int) (json.RawMessage, error) {
-	resp, err := c.do("GET", fmt.Sprintf("%s/%d", monitorType, id), nil)
+func (c Client) GetMonitor(ctx context.Context, id int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%d", monitorType, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	return c.stripJSONFields(resp, c.removeMonitorFields)
 }
+
+// DeleteMonitor deletes a monitor by ID.
+func (c Client) DeleteMonitor(ctx context.Context, id int) error {
+	return c.genericDelete(ctx, monitorType, strconv.Itoa(id))
+}
+
+// Summaries returns IDs, names, tags and creators of the monitors.
+func (mr MonitorsResponse) Summaries() ([]Summary, error) {
+	var resp []struct {
+		ID      int      `json:"id"`
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Creator *creator `json:"creator"`
+	}
+
+	err := json.Unmarshal(mr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal monitors response")
+	}
+
+	var summaries []Summary
+	for _, m := range resp {
+		summaries = append(summaries, Summary{ID: strconv.Itoa(m.ID), Title: m.Name, Tags: m.Tags, Creator: m.Creator.String()})
+	}
+
+	return summaries, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -127,7 +128,7 @@ func TestClient_GetMonitors(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetMonitors()
+	_, err = c.GetMonitors(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -151,7 +152,7 @@ func TestClient_GetMonitor(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetMonitor(25)
+	_, err = c.GetMonitor(context.Background(), 25)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -176,7 +177,7 @@ func TestClient_UpdateMonitor(t *testing.T) {
 	c.baseEndpoint = ts.URL
 
 	exampleMonitor := []byte(`{"id": 25}`)
-	err = c.UpdateMonitor(exampleMonitor)
+	err = c.UpdateMonitor(context.Background(), exampleMonitor)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -205,14 +206,14 @@ func TestClient_MonitorWithDependencies(t *testing.T) {
 					t.Fatal(err)
 				}
 			case "/downtime/2":
-				fmt.Fprint(w, `{"id":3}`)
+				fmt.Fprint(w, `{"id":2,"monitor_id":1}`)
 			default:
 				t.Fatalf("invalid URL %s", r.URL.Path)
 			}
 		} else if r.Method == "PUT" {
 			switch r.URL.Path {
 			case "/alert/1":
-			case "/downtime/3":
+			case "/downtime/2":
 			case "/monitor/1":
 			default:
 				t.Fatalf("invalid URL %s", r.URL.Path)
@@ -229,7 +230,7 @@ func TestClient_MonitorWithDependencies(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	m, err := c.GetMonitorWithDependencies(1, true)
+	m, err := c.GetMonitorWithDependencies(context.Background(), 1, true)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -242,12 +243,341 @@ func TestClient_MonitorWithDependencies(t *testing.T) {
 		t.Fatalf("expect %s. Got %s", alert, string(m.Alert))
 	}
 
-	if `{"id":3}` != string(m.Downtime) {
-		t.Fatalf("expect {\"id\":3}. Got %s", string(m.Downtime))
+	if `{"id":2,"monitor_id":1}` != string(m.Downtime) {
+		t.Fatalf("expect {\"id\":2,\"monitor_id\":1}. Got %s", string(m.Downtime))
+	}
+
+	if !m.DowntimeTracked {
+		t.Fatal("expect the downtime to be tracked")
+	}
+
+	err = c.UpdateMonitorWithDependencies(context.Background(), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_MonitorWithDependenciesDowntimeError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/monitor/1", "/alert/1":
+			fmt.Fprint(w, `{"id":1}`)
+		case "/downtime":
+			fmt.Fprint(w, `[{"id":2,"monitor_id":1}]`)
+		case "/downtime/2":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			t.Fatalf("invalid URL %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	// a monitor without its downtime must not be returned, restoring it would cancel the downtime.
+	m, err := c.GetMonitorWithDependencies(context.Background(), 1, true)
+	if err == nil {
+		t.Fatalf("expect an error getting the downtime. Got %+v", m)
+	}
+}
+
+func TestClient_RestoreMonitorDowntime(t *testing.T) {
+	canceled := uint64(1500000000)
+	downtimes := []*Downtime{
+		&Downtime{ID: 2, MonitorID: 1},
+		&Downtime{ID: 3, MonitorID: 5, Canceled: &canceled},
+	}
+
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && r.URL.Path == "/downtime" {
+			if err := json.NewEncoder(w).Encode(downtimes); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "POST":
+			body := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := body["id"]; ok {
+				t.Fatalf("expect id not to be sent on create. Got %v", body)
+			}
+
+			fmt.Fprint(w, `{"id":10,"monitor_id":5}`)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	tests := []struct {
+		monitorID int
+		downtime  string
+		expected  []string
+	}{
+		// the downtime still exists
+		{1, `{"id":2,"monitor_id":1}`, []string{"PUT /downtime/2"}},
+		// the downtime was removed from git
+		{1, `null`, []string{"DELETE /downtime/2"}},
+		// the downtime was canceled in datadog
+		{5, `{"id":3,"monitor_id":5}`, []string{"POST /downtime"}},
+		// another downtime was attached to the monitor in datadog
+		{1, `{"id":7,"monitor_id":1}`, []string{"DELETE /downtime/2", "POST /downtime"}},
+		// no downtime in git and datadog
+		{5, ``, nil},
+	}
+
+	for _, test := range tests {
+		requests = nil
+		err := c.restoreMonitorDowntime(context.Background(), test.monitorID, json.RawMessage(test.downtime))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fmt.Sprint(requests) != fmt.Sprint(test.expected) {
+			t.Fatalf("expect requests %v for downtime %s. Got %v", test.expected, test.downtime, requests)
+		}
+	}
+}
+
+func TestClient_UpdateMonitorUntrackedDowntime(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" && r.URL.Path == "/downtime" {
+			fmt.Fprint(w, `[{"id":2,"monitor_id":1}]`)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	tests := []struct {
+		monitor  *MonitorWithDependencies
+		expected []string
+	}{
+		// a file written before the downtime was tracked keeps the downtime in datadog
+		{&MonitorWithDependencies{Monitor: []byte(`{"id":1}`), Downtime: []byte(`null`)}, []string{"PUT /monitor/1"}},
+		// the downtime was removed from git
+		{&MonitorWithDependencies{Monitor: []byte(`{"id":1}`), Downtime: []byte(`null`), DowntimeTracked: true},
+			[]string{"PUT /monitor/1", "GET /downtime", "DELETE /downtime/2"}},
 	}
 
-	err = c.UpdateMonitorWithDependencies(m)
+	for _, test := range tests {
+		requests = nil
+		if err := c.UpdateMonitorWithDependencies(context.Background(), test.monitor); err != nil {
+			t.Fatal(err)
+		}
+
+		if fmt.Sprint(requests) != fmt.Sprint(test.expected) {
+			t.Fatalf("expect requests %v. Got %v", test.expected, requests)
+		}
+	}
+}
+
+func TestClient_UpdateAlertFields(t *testing.T) {
+	var updated []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"id":1,"message":"old","silenced":false,"renotify_interval":20}`)
+		case "PUT":
+			body := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(body) != 2 {
+				t.Fatalf("expect id and a single field. Got %v", body)
+			}
+
+			if _, ok := body["silenced"]; ok {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":["silenced is not allowed"]}`)
+				return
+			}
+
+			for field := range body {
+				if field != "id" {
+					updated = append(updated, field)
+				}
+			}
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
 	if err != nil {
 		t.Fatal(err)
 	}
+	c.baseEndpoint = ts.URL
+
+	err = c.UpdateAlertFields(context.Background(), json.RawMessage(`{"id":1,"message":"new","silenced":true,"renotify_interval":20}`))
+	alertErr, ok := err.(*AlertFieldsError)
+	if !ok {
+		t.Fatalf("expect *AlertFieldsError. Got %v", err)
+	}
+
+	if len(alertErr.Fields) != 1 || alertErr.Fields["silenced"] == nil {
+		t.Fatalf("expect silenced field to fail. Got %v", alertErr.Fields)
+	}
+
+	if len(updated) != 1 || updated[0] != "message" {
+		t.Fatalf("expect message field to be updated. Got %v", updated)
+	}
+}
+
+func TestClient_CreateMonitorWithDependencies(t *testing.T) {
+	var downtime map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("expect method POST. Got %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/monitor":
+			fmt.Fprint(w, `{"id":77}`)
+		case "/downtime":
+			if err := json.NewDecoder(r.Body).Decode(&downtime); err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprint(w, `{"id":88,"monitor_id":77}`)
+		default:
+			t.Fatalf("invalid URL %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	id, err := c.CreateMonitorWithDependencies(context.Background(), &MonitorWithDependencies{
+		Monitor:  json.RawMessage(`{"id":1,"name":"cpu","overall_state":"OK"}`),
+		Alert:    json.RawMessage(`{"id":1}`),
+		Downtime: json.RawMessage(`{"id":2,"monitor_id":1,"message":"deploy","active":true}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 77 {
+		t.Fatalf("expect monitor id 77. Got %d", id)
+	}
+
+	if fmt.Sprint(downtime["monitor_id"]) != "77" {
+		t.Fatalf("expect the downtime to be attached to monitor 77. Got %v", downtime["monitor_id"])
+	}
+
+	if _, ok := downtime["id"]; ok {
+		t.Fatalf("expect downtime id not to be sent. Got %v", downtime)
+	}
+}
+
+func TestMonitorsResponse_Summaries(t *testing.T) {
+	mr := MonitorsResponse(`[{"id":1,"name":"cpu","tags":["team:payments","env:prod"]},{"id":2,"name":"disk"}]`)
+	summaries, err := mr.Summaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("expect 2 summaries. Got %+v", summaries)
+	}
+
+	if s := summaries[0]; s.ID != "1" || s.Title != "cpu" || len(s.Tags) != 2 || s.Tags[0] != "team:payments" {
+		t.Fatalf("expect monitor 1 cpu with tags. Got %+v", s)
+	}
+
+	if s := summaries[1]; s.ID != "2" || s.Title != "disk" || len(s.Tags) != 0 {
+		t.Fatalf("expect monitor 2 disk without tags. Got %+v", s)
+	}
+}
+
+func TestMonitorsResponse_SummariesCreator(t *testing.T) {
+	mr := MonitorsResponse(`[{"id":1,"name":"cpu","creator":{"handle":"jane","email":"jane@example.com"}},{"id":2,"name":"disk","creator":{"email":"joe@example.com"}}]`)
+	summaries, err := mr.Summaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summaries) != 2 || summaries[0].Creator != "jane" || summaries[1].Creator != "joe@example.com" {
+		t.Fatalf("expect creators jane and joe@example.com. Got %+v", summaries)
+	}
+}
+
+func TestClient_ValidateMonitor(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/monitor/validate" {
+			t.Fatalf("expect URL /monitor/validate. Got %s", r.URL.Path)
+		}
+
+		if r.Method != "POST" {
+			t.Fatalf("expect method POST. Got %s", r.Method)
+		}
+
+		monitor := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&monitor); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := monitor["id"]; ok {
+			t.Fatalf("expect id removed. Got %v", monitor)
+		}
+
+		if monitor["query"] == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":["The value provided for parameter 'query' is invalid"]}`)
+			return
+		}
+
+		fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	errs, err := c.ValidateMonitor(context.Background(), []byte(`{"id":1,"query":"avg(last_5m):avg:system.load.1{*} > 1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 0 {
+		t.Fatalf("expect no validation errors. Got %v", errs)
+	}
+
+	errs, err = c.ValidateMonitor(context.Background(), []byte(`{"id":1,"query":"invalid"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 1 || errs[0] != "The value provided for parameter 'query' is invalid" {
+		t.Fatalf("expect query validation error. Got %v", errs)
+	}
 }
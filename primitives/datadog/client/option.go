@@ -1,8 +1,43 @@
 package client
 
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 // Option is a functional parameter for datadog client.
 type Option func(c *Client) error
 
+// WithSite sets the Datadog site to send requests to. A site could be set by its
+// short name (US1, US3, US5, EU, gov) or by its domain, for example datadoghq.eu.
+// The default site is US1.
+func WithSite(site string) Option {
+	return func(c *Client) error {
+		domain, err := resolveSite(site)
+		if err != nil {
+			return err
+		}
+
+		c.baseEndpoint = siteEndpoint(domain)
+		return nil
+	}
+}
+
+// WithBaseURL sets a custom base URL of Datadog API, for example a proxy.
+// The API version path is appended to the URL, https://dd.example.com becomes https://dd.example.com/api/v1
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		endpoint, err := baseURLEndpoint(baseURL)
+		if err != nil {
+			return err
+		}
+
+		c.baseEndpoint = endpoint
+		return nil
+	}
+}
+
 // WithRemoveDashboardFields sets fields to be removed from a dashboard response.
 // Nested fields are supported via comma for example (dash.modified) will remove the modified field from
 // a nested dict under "dash"
@@ -14,6 +49,15 @@ func WithRemoveDashboardFields(fields []string) Option {
 	}
 }
 
+// WithRemoveUnifiedDashboardFields sets fields to be removed from a unified dashboard response.
+// endpoint /api/v1/dashboard/<id>
+func WithRemoveUnifiedDashboardFields(fields []string) Option {
+	return func(c *Client) error {
+		c.removeUnifiedDashboardFields = fields
+		return nil
+	}
+}
+
 // WithRemoveMonitorFields sets fields to be removed from monitor response.
 // endpoint /api/v1/monitor/<id>
 func WithRemoveMonitorFields(monitorFields, alertFields []string) Option {
@@ -32,3 +76,59 @@ func WithRemoveScreenBoardFields(fields []string) Option {
 		return nil
 	}
 }
+
+// WithRemoveDowntimeFields sets fields to be removed from downtime response.
+// endpoint /api/v1/downtime/<id>
+func WithRemoveDowntimeFields(fields []string) Option {
+	return func(c *Client) error {
+		c.removeDowntimeFields = fields
+		return nil
+	}
+}
+
+// WithRemoveSyntheticsFields sets fields to be removed from synthetic test response.
+// endpoint /api/v1/synthetics/tests/<public_id>
+func WithRemoveSyntheticsFields(fields []string) Option {
+	return func(c *Client) error {
+		c.removeSyntheticsFields = fields
+		return nil
+	}
+}
+
+// WithRemoveSLOFields sets fields to be removed from service level objective response.
+// endpoint /api/v1/slo/<id>
+func WithRemoveSLOFields(fields []string) Option {
+	return func(c *Client) error {
+		c.removeSLOFields = fields
+		return nil
+	}
+}
+
+// WithRetries sets the number of retries of failed requests and the backoff bounds.
+// Requests are retried on network errors, 429 and 5xx status codes, with a random delay
+// between zero and min(maxBackoff, minBackoff * 2^attempt).
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) error {
+		if maxRetries < 0 || minBackoff < 0 || maxBackoff < minBackoff {
+			return errors.Errorf("invalid retries %d or backoff [%s, %s]", maxRetries, minBackoff, maxBackoff)
+		}
+
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+		return nil
+	}
+}
+
+// WithRequestTimeout sets a timeout of a single request to datadog API, including reading the response.
+// Zero disables the timeout. Timed out requests are retried.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return errors.Errorf("invalid request timeout %s", timeout)
+		}
+
+		c.requestTimeout = timeout
+		return nil
+	}
+}
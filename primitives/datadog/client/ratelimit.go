@@ -0,0 +1,127 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Datadog rate limit headers.
+// https://docs.datadoghq.com/api/latest/rate-limits/
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitPeriodHeader    = "X-RateLimit-Period"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+	rateLimitNameHeader      = "X-RateLimit-Name"
+)
+
+// RateLimit is a rate limit state of an endpoint as reported by Datadog in the last response.
+type RateLimit struct {
+	// Name is a name of the rate limit as reported by Datadog, could be empty.
+	Name string
+
+	// Limit is a number of requests allowed in a period.
+	Limit int
+
+	// Period is a length of the rate limit period.
+	Period time.Duration
+
+	// Remaining is a number of requests left in the current period.
+	Remaining int
+
+	// ResetAt is a time when the current period ends.
+	ResetAt time.Time
+}
+
+// Exhausted returns true if there are no requests left until the period ends.
+func (r RateLimit) Exhausted(now time.Time) bool {
+	return r.Remaining <= 0 && now.Before(r.ResetAt)
+}
+
+// Wait returns a duration until the period ends, zero if it has already ended.
+func (r RateLimit) Wait(now time.Time) time.Duration {
+	if now.After(r.ResetAt) {
+		return 0
+	}
+
+	return r.ResetAt.Sub(now)
+}
+
+// parseRateLimit reads the rate limit headers. It returns false if the response has no rate limit headers.
+func parseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get(rateLimitLimitHeader))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	if err != nil {
+		return RateLimit{}, false
+	}
+
+	period, _ := strconv.Atoi(header.Get(rateLimitPeriodHeader))
+	reset, _ := strconv.Atoi(header.Get(rateLimitResetHeader))
+
+	return RateLimit{
+		Name:      header.Get(rateLimitNameHeader),
+		Limit:     limit,
+		Period:    time.Duration(period) * time.Second,
+		Remaining: remaining,
+		ResetAt:   now.Add(time.Duration(reset) * time.Second),
+	}, true
+}
+
+// rateLimitEndpoint returns a key used to track rate limits of an API call. Datadog applies
+// limits per resource, so all calls to the same resource share the key, e.g. "monitor" for
+// "monitor/123".
+func rateLimitEndpoint(apiCall string) string {
+	endpoint := strings.SplitN(apiCall, "?", 2)[0]
+	return strings.SplitN(endpoint, "/", 2)[0]
+}
+
+// rateLimits holds the last known rate limit state per endpoint.
+type rateLimits struct {
+	sync.RWMutex
+	endpoints map[string]RateLimit
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{
+		endpoints: make(map[string]RateLimit),
+	}
+}
+
+func (r *rateLimits) get(endpoint string) (RateLimit, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	rl, ok := r.endpoints[endpoint]
+	return rl, ok
+}
+
+func (r *rateLimits) set(endpoint string, rl RateLimit) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.endpoints[endpoint] = rl
+}
+
+func (r *rateLimits) all() map[string]RateLimit {
+	r.RLock()
+	defer r.RUnlock()
+
+	out := make(map[string]RateLimit, len(r.endpoints))
+	for endpoint, rl := range r.endpoints {
+		out[endpoint] = rl
+	}
+
+	return out
+}
+
+// RateLimits returns the last known rate limit state per endpoint, e.g. "monitor" or "dashboard".
+func (c Client) RateLimits() map[string]RateLimit {
+	return c.rateLimits.all()
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func newTestClient(t *testing.T, url string, sleeps *[]time.Duration) *Client {
+	c, err := New("123", "456", WithRetries(3, time.Millisecond, time.Millisecond*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = url
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		return ctx.Err()
+	}
+
+	return c
+}
+
+func TestRetryServerError(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	body, err := c.GetAlert(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != `{"id": 1}` {
+		t.Fatalf("expect body {\"id\": 1}. Got %s", body)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expect 3 calls. Got %d", calls)
+	}
+
+	for _, sleep := range sleeps {
+		if sleep <= 0 || sleep > time.Millisecond*10 {
+			t.Fatalf("expect backoff between 0 and 10ms. Got %s", sleep)
+		}
+	}
+}
+
+func TestRetryGiveUp(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	_, err := c.GetAlert(context.Background(), 1)
+	if err == nil {
+		t.Fatal("expect an error")
+	}
+
+	if calls != 4 {
+		t.Fatalf("expect 4 calls. Got %d", calls)
+	}
+}
+
+func TestNoRetryCreate(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost {
+			t.Errorf("expect method POST. Got %s", r.Method)
+		}
+
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	// a rejected create is retried, a create which could have been applied is not.
+	_, err := c.do(context.Background(), http.MethodPost, "monitor", strings.NewReader(`{}`))
+	if err == nil {
+		t.Fatal("expect an error")
+	}
+
+	if calls != 2 {
+		t.Fatalf("expect 2 calls. Got %d", calls)
+	}
+}
+
+func TestNoRetryClientError(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	_, err := c.GetAlert(context.Background(), 1)
+	if err == nil {
+		t.Fatal("expect an error")
+	}
+
+	if calls != 1 {
+		t.Fatalf("expect 1 call. Got %d", calls)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Period", "60")
+		w.Header().Set("X-RateLimit-Name", "monitor_get")
+
+		if calls == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "20")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	_, err := c.GetAlert(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expect 2 calls. Got %d", calls)
+	}
+
+	// the client waits before retrying and before making a next request to exhausted endpoint
+	if len(sleeps) == 0 || sleeps[0] < time.Second*15 {
+		t.Fatalf("expect to wait until the rate limit resets. Got %v", sleeps)
+	}
+
+	rl, ok := c.RateLimits()["alert"]
+	if !ok {
+		t.Fatal("expect a rate limit state of alert endpoint")
+	}
+
+	if rl.Name != "monitor_get" || rl.Limit != 100 || rl.Remaining != 99 || rl.Period != time.Minute {
+		t.Fatalf("unexpected rate limit state %+v", rl)
+	}
+}
+
+func TestWaitExhaustedRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "10")
+		fmt.Fprint(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+
+	if _, err := c.GetAlert(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sleeps) != 0 {
+		t.Fatalf("expect no waiting on the first request. Got %v", sleeps)
+	}
+
+	if _, err := c.GetAlert(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sleeps) != 1 || sleeps[0] < time.Second*5 {
+		t.Fatalf("expect to wait for rate limit reset before the second request. Got %v", sleeps)
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456", WithRetries(3, time.Hour, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	start := time.Now()
+	_, err = c.do(ctx, "GET", "alert/1", nil)
+	if errors.Cause(err) != context.DeadlineExceeded {
+		t.Fatalf("expect error %s. Got %v", context.DeadlineExceeded, err)
+	}
+
+	if time.Since(start) > time.Second*5 {
+		t.Fatalf("expect the request to be cancelled with context. Took %s", time.Since(start))
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	// the handler is still running when a timed out request returns.
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	var sleeps []time.Duration
+	c := newTestClient(t, ts.URL, &sleeps)
+	c.requestTimeout = time.Millisecond * 20
+	c.maxRetries = 1
+
+	_, err := c.GetAlert(context.Background(), 1)
+	if err == nil {
+		t.Fatal("expect a timeout error")
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Fatalf("expect a timed out request to be retried once. Got %d calls", calls)
+	}
+}
@@ -1,8 +1,10 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -49,13 +51,13 @@ func (sr ScreenBoardsResponse) GetModifiedIDsWithin(interval time.Duration, fn f
 }
 
 // GetScreenboards returns a json raw message response from calling /api/v1/dash
-func (c Client) GetScreenboards() (ScreenBoardsResponse, error) {
-	return c.do("GET", "screen", nil)
+func (c Client) GetScreenboards(ctx context.Context) (ScreenBoardsResponse, error) {
+	return c.do(ctx, "GET", "screen", nil)
 }
 
 // UpdateScreenboard updates the screen board from a given raw json message.
-func (c Client) UpdateScreenboard(screen json.RawMessage) error {
-	err := c.genericUpdate(screenboardType, screen)
+func (c Client) UpdateScreenboard(ctx context.Context, screen json.RawMessage) error {
+	err := c.genericUpdate(ctx, screenboardType, screen)
 	if err != nil {
 		if err == errInvalidComponent {
 			return ErrInvalidScreenboard
@@ -68,11 +70,57 @@ func (c Client) UpdateScreenboard(screen json.RawMessage) error {
 }
 
 // GetScreenboard returns a raw json representation of a screen board.
-func (c Client) GetScreenboard(id int) (json.RawMessage, error) {
-	resp, err := c.do("GET", fmt.Sprintf("%s/%d", screenboardType, id), nil)
+func (c Client) GetScreenboard(ctx context.Context, id int) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%d", screenboardType, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	return c.stripJSONFields(resp, c.removeScreenboardFields)
 }
+
+// readOnlyScreenboardFields are set by datadog and must not be sent on create.
+var readOnlyScreenboardFields = []string{"id", "created", "modified", "created_by", "original_title", "resource"}
+
+// CreateScreenboard creates a screen board and returns the new ID.
+func (c Client) CreateScreenboard(ctx context.Context, screen json.RawMessage) (int, error) {
+	resp, err := c.genericCreate(ctx, screenboardType, screen, readOnlyScreenboardFields)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createdID(resp, "id")
+	if err != nil {
+		return 0, ErrInvalidScreenboard
+	}
+
+	return strconv.Atoi(id)
+}
+
+// DeleteScreenboard deletes a screenboard by ID.
+func (c Client) DeleteScreenboard(ctx context.Context, id int) error {
+	return c.genericDelete(ctx, screenboardType, strconv.Itoa(id))
+}
+
+// Summaries returns IDs, titles and creators of the screenboards.
+func (sr ScreenBoardsResponse) Summaries() ([]Summary, error) {
+	var resp struct {
+		Screenboards []struct {
+			ID        int      `json:"id"`
+			Title     string   `json:"title"`
+			CreatedBy *creator `json:"created_by"`
+		} `json:"screenboards"`
+	}
+
+	err := json.Unmarshal(sr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal screenboards response")
+	}
+
+	var summaries []Summary
+	for _, s := range resp.Screenboards {
+		summaries = append(summaries, Summary{ID: strconv.Itoa(s.ID), Title: s.Title, Creator: s.CreatedBy.String()})
+	}
+
+	return summaries, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -92,7 +93,7 @@ func TestClient_GetScreenboards(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetScreenboards()
+	_, err = c.GetScreenboards(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -116,7 +117,7 @@ func TestClient_GetScreenboard(t *testing.T) {
 	}
 	c.baseEndpoint = ts.URL
 
-	_, err = c.GetScreenboard(25)
+	_, err = c.GetScreenboard(context.Background(), 25)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -141,7 +142,7 @@ func TestClient_UpdateScreenboard(t *testing.T) {
 	c.baseEndpoint = ts.URL
 
 	exampleScreen := []byte(`{"id": 25}`)
-	err = c.UpdateScreenboard(exampleScreen)
+	err = c.UpdateScreenboard(context.Background(), exampleScreen)
 	if err != nil {
 		t.Fatal(err)
 	}
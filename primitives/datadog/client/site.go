@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Datadog sites. Each site is a separate Datadog region with its own API host,
+// an organization exists only in one of them.
+// https://docs.datadoghq.com/getting_started/site/
+const (
+	SiteUS1 = "datadoghq.com"
+	SiteUS3 = "us3.datadoghq.com"
+	SiteUS5 = "us5.datadoghq.com"
+	SiteEU  = "datadoghq.eu"
+	SiteGov = "ddog-gov.com"
+)
+
+const apiVersionPath = "/api/v1"
+
+var (
+	// ErrInvalidSite is returned if the given Datadog site is unknown.
+	ErrInvalidSite = errors.New("invalid datadog site")
+
+	// ErrInvalidBaseURL is returned if the given custom API base URL is not an absolute URL.
+	ErrInvalidBaseURL = errors.New("invalid datadog API base URL")
+)
+
+// siteAliases maps short site names as shown in Datadog documentation to site domains.
+var siteAliases = map[string]string{
+	"us1": SiteUS1,
+	"us3": SiteUS3,
+	"us5": SiteUS5,
+	"eu":  SiteEU,
+	"eu1": SiteEU,
+	"gov": SiteGov,
+}
+
+// resolveSite takes a site alias (US1, US3, US5, EU, gov) or a site domain and returns the site domain.
+func resolveSite(site string) (string, error) {
+	site = strings.ToLower(strings.TrimSpace(site))
+	if domain, ok := siteAliases[site]; ok {
+		return domain, nil
+	}
+
+	for _, domain := range siteAliases {
+		if site == domain {
+			return domain, nil
+		}
+	}
+
+	return "", errors.Wrapf(ErrInvalidSite, "site %q", site)
+}
+
+// siteEndpoint returns the API endpoint for the given site domain.
+func siteEndpoint(domain string) string {
+	return fmt.Sprintf("https://api.%s%s", domain, apiVersionPath)
+}
+
+// baseURLEndpoint returns the API endpoint for a custom base URL, e.g. a proxy in front of Datadog.
+func baseURLEndpoint(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidBaseURL, "%s: %s", baseURL, err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.Wrapf(ErrInvalidBaseURL, "%s: scheme and host are required", baseURL)
+	}
+
+	return strings.TrimRight(u.String(), "/") + apiVersionPath, nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestWithSite(t *testing.T) {
+	tests := []struct {
+		site     string
+		endpoint string
+	}{
+		{"US1", "https://api.datadoghq.com/api/v1"},
+		{"us3", "https://api.us3.datadoghq.com/api/v1"},
+		{"US5", "https://api.us5.datadoghq.com/api/v1"},
+		{"EU", "https://api.datadoghq.eu/api/v1"},
+		{"gov", "https://api.ddog-gov.com/api/v1"},
+		{"datadoghq.eu", "https://api.datadoghq.eu/api/v1"},
+	}
+
+	for _, test := range tests {
+		c, err := New("123", "456", WithSite(test.site))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.baseEndpoint != test.endpoint {
+			t.Fatalf("expect endpoint %s for site %s. Got %s", test.endpoint, test.site, c.baseEndpoint)
+		}
+	}
+
+	_, err := New("123", "456", WithSite("mars"))
+	if errors.Cause(err) != ErrInvalidSite {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidSite, err)
+	}
+}
+
+func TestDefaultSite(t *testing.T) {
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.baseEndpoint != "https://api.datadoghq.com/api/v1" {
+		t.Fatalf("expect US1 endpoint by default. Got %s", c.baseEndpoint)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/alert/222" {
+			t.Fatalf("expect url \"/api/v1/alert/222\" Got %s", r.URL.Path)
+		}
+
+		if r.URL.RawQuery != "" {
+			t.Fatalf("expect credentials not to be sent in query. Got %s", r.URL.RawQuery)
+		}
+
+		if r.Header.Get("DD-API-KEY") != "123" {
+			t.Fatalf("expect DD-API-KEY header 123. Got %s", r.Header.Get("DD-API-KEY"))
+		}
+
+		if r.Header.Get("DD-APPLICATION-KEY") != "456" {
+			t.Fatalf("expect DD-APPLICATION-KEY header 456. Got %s", r.Header.Get("DD-APPLICATION-KEY"))
+		}
+
+		fmt.Fprint(w, `{"id": 222}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456", WithBaseURL(ts.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alert, err := c.GetAlert(context.Background(), 222)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(alert) != `{"id": 222}` {
+		t.Fatalf("expect alert {\"id\": 222}. Got %s", alert)
+	}
+
+	_, err = New("123", "456", WithBaseURL("datadog.example.com"))
+	if errors.Cause(err) != ErrInvalidBaseURL {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidBaseURL, err)
+	}
+}
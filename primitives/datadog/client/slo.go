@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SLOsResponse represents a response from calling /api/v1/slo endpoint.
+type SLOsResponse json.RawMessage
+
+// GetModifiedIDsWithin returns a list of SLO IDs if the modified_at field was changed within the given interval.
+// Unlike other components, SLO API returns modified_at as a unix timestamp in seconds.
+func (sr SLOsResponse) GetModifiedIDsWithin(interval time.Duration, fn func(time.Time) time.Duration) ([]string, error) {
+	if fn == nil {
+		fn = time.Since
+	}
+
+	var resp struct {
+		Data []struct {
+			ID         string `json:"id"`
+			ModifiedAt int64  `json:"modified_at"`
+		} `json:"data"`
+	}
+
+	err := json.Unmarshal(sr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal slo response")
+	}
+
+	var ids []string
+	for _, slo := range resp.Data {
+		if slo.ModifiedAt == 0 {
+			return nil, fmt.Errorf("empty modified_at field, full response: %+v", resp)
+		}
+
+		if fn(time.Unix(slo.ModifiedAt, 0)) < interval {
+			ids = append(ids, slo.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetSLOs returns a list of all service level objectives.
+func (c Client) GetSLOs(ctx context.Context) (SLOsResponse, error) {
+	return c.do(ctx, "GET", string(sloType), nil)
+}
+
+// GetSLO returns a raw json of a service level objective. The SLO API wraps an object
+// into the data field, GetSLO returns the unwrapped object so it could be used to update the SLO.
+func (c Client) GetSLO(ctx context.Context, id string) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%s", sloType, url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	slo := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	err = json.Unmarshal(resp, &slo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to unmarshal slo %s", id)
+	}
+
+	if len(slo.Data) == 0 {
+		return nil, errors.Errorf("empty slo %s, full response: %s", id, string(resp))
+	}
+
+	return c.stripJSONFields(slo.Data, c.removeSLOFields)
+}
+
+// UpdateSLO updates a service level objective from raw message.
+func (c Client) UpdateSLO(ctx context.Context, slo json.RawMessage) error {
+	err := c.genericUpdateByStringID(ctx, sloType, "id", slo)
+	if err != nil {
+		if err == errInvalidComponent {
+			return ErrInvalidSLO
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// readOnlySLOFields are set by datadog and must not be sent on create.
+var readOnlySLOFields = []string{"id", "creator", "created_at", "modified_at"}
+
+// CreateSLO creates a service level objective and returns the new ID.
+func (c Client) CreateSLO(ctx context.Context, slo json.RawMessage) (string, error) {
+	resp, err := c.genericCreate(ctx, sloType, slo, readOnlySLOFields)
+	if err != nil {
+		return "", err
+	}
+
+	created := struct {
+		Data []json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(resp, &created); err != nil {
+		return "", errors.Wrap(err, "unable to unmarshal created slo")
+	}
+
+	if len(created.Data) != 1 {
+		return "", ErrInvalidSLO
+	}
+
+	id, err := createdID(created.Data[0], "id")
+	if err != nil {
+		return "", ErrInvalidSLO
+	}
+
+	return id, nil
+}
+
+// DeleteSLO deletes a service level objective by ID.
+func (c Client) DeleteSLO(ctx context.Context, id string) error {
+	return c.genericDelete(ctx, sloType, id)
+}
+
+// Summaries returns IDs, names, tags and creators of the service level objectives.
+func (sr SLOsResponse) Summaries() ([]Summary, error) {
+	var resp struct {
+		Data []struct {
+			ID      string   `json:"id"`
+			Name    string   `json:"name"`
+			Tags    []string `json:"tags"`
+			Creator *creator `json:"creator"`
+		} `json:"data"`
+	}
+
+	err := json.Unmarshal(sr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal slos response")
+	}
+
+	var summaries []Summary
+	for _, slo := range resp.Data {
+		summaries = append(summaries, Summary{ID: slo.ID, Title: slo.Name, Tags: slo.Tags, Creator: slo.Creator.String()})
+	}
+
+	return summaries, nil
+}
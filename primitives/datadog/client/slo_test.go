@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var exampleSLOsResponse = `
+{
+  "data": [
+    {
+      "id": "12341234123412341234123412341234",
+      "name": "api availability",
+      "type": "monitor",
+      "monitor_ids": [3, 4],
+      "thresholds": [{"timeframe": "7d", "target": 99.9}],
+      "created_at": 1554138900,
+      "modified_at": 1554160000
+    },
+    {
+      "id": "56785678567856785678567856785678",
+      "name": "web latency",
+      "type": "metric",
+      "thresholds": [{"timeframe": "30d", "target": 99}],
+      "created_at": 1554138900,
+      "modified_at": 1554170000
+    }
+  ],
+  "errors": []
+}
+`
+
+func TestSLOsResponse_GetModifiedIDsWithin(t *testing.T) {
+	sr := SLOsResponse(json.RawMessage(exampleSLOsResponse))
+	fn := func(modified time.Time) time.Duration {
+		if modified.Unix() == 1554160000 {
+			return time.Millisecond
+		}
+		return time.Hour
+	}
+
+	ids, err := sr.GetModifiedIDsWithin(time.Second, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 {
+		t.Fatalf("expect 1 id. Got %d", len(ids))
+	}
+
+	if ids[0] != "12341234123412341234123412341234" {
+		t.Fatalf("expect id 12341234123412341234123412341234. Got %v", ids)
+	}
+}
+
+func TestClient_GetSLO(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/slo/1234" {
+			t.Fatalf("expect url /slo/1234 Got %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"data":{"id":"1234","name":"api availability","modified_at":1554160000},"errors":[]}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456", WithRemoveSLOFields([]string{"modified_at"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	resp, err := c.GetSLO(context.Background(), "1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slo := map[string]interface{}{}
+	if err := json.Unmarshal(resp, &slo); err != nil {
+		t.Fatal(err)
+	}
+
+	if slo["id"] != "1234" {
+		t.Fatalf("expect unwrapped slo with id 1234. Got %s", resp)
+	}
+
+	if _, ok := slo["modified_at"]; ok {
+		t.Fatalf("expect modified_at field to be removed. Got %s", resp)
+	}
+}
+
+func TestClient_UpdateSLO(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/slo/1234" {
+			t.Fatalf("expect /slo/1234 Got %s", r.URL.Path)
+		}
+
+		if r.Method != "PUT" {
+			t.Fatalf("expect method PUT. Got %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	err = c.UpdateSLO(context.Background(), []byte(`{"id":"1234","name":"api availability"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateSLO(context.Background(), []byte(`{"name":"api availability"}`))
+	if err != ErrInvalidSLO {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidSLO, err)
+	}
+}
+
+func TestClient_CreateSLO(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/slo" {
+			t.Fatalf("expect POST /slo. Got %s %s", r.Method, r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"data":[{"id":"12341234123412341234123412341234","name":"api availability"}],"error":null}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	id, err := c.CreateSLO(context.Background(), json.RawMessage(`{"name":"api availability"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "12341234123412341234123412341234" {
+		t.Fatalf("expect id 12341234123412341234123412341234. Got %s", id)
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+// Summary is a short description of a component from a list endpoint, used to select components
+// by their title and tags and to find their owners.
+type Summary struct {
+	ID      string
+	Title   string
+	Tags    []string
+	Creator string
+}
+
+// creator is a datadog user who created a component.
+type creator struct {
+	Handle string `json:"handle"`
+	Email  string `json:"email"`
+}
+
+// String returns the user handle, or the email if the handle is not set.
+func (c *creator) String() string {
+	if c == nil {
+		return ""
+	}
+
+	if c.Handle != "" {
+		return c.Handle
+	}
+
+	return c.Email
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SyntheticsResponse represents a response from calling /api/v1/synthetics/tests endpoint.
+type SyntheticsResponse json.RawMessage
+
+// GetModifiedIDsWithin returns a list of synthetic test public IDs if the modified_at field was changed within the given interval.
+func (sr SyntheticsResponse) GetModifiedIDsWithin(interval time.Duration, fn func(time.Time) time.Duration) ([]string, error) {
+	if fn == nil {
+		fn = time.Since
+	}
+
+	var resp struct {
+		Tests []struct {
+			PublicID   string `json:"public_id"`
+			ModifiedAt string `json:"modified_at"`
+		} `json:"tests"`
+	}
+
+	err := json.Unmarshal(sr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal synthetics response")
+	}
+
+	var ids []string
+	for _, test := range resp.Tests {
+		if test.ModifiedAt == "" {
+			return nil, fmt.Errorf("empty modified_at field, full response: %+v", resp)
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, test.ModifiedAt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse modified_at field %s", test.ModifiedAt)
+		}
+
+		if fn(t) < interval {
+			ids = append(ids, test.PublicID)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetSyntheticsTests returns a list of all API and browser synthetic tests.
+func (c Client) GetSyntheticsTests(ctx context.Context) (SyntheticsResponse, error) {
+	return c.do(ctx, "GET", string(syntheticsType), nil)
+}
+
+// GetSyntheticsTest returns a raw json of a synthetic test by its public ID.
+func (c Client) GetSyntheticsTest(ctx context.Context, publicID string) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%s", syntheticsType, url.PathEscape(publicID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stripJSONFields(resp, c.removeSyntheticsFields)
+}
+
+// UpdateSyntheticsTest updates a synthetic test from raw message.
+func (c Client) UpdateSyntheticsTest(ctx context.Context, test json.RawMessage) error {
+	err := c.genericUpdateByStringID(ctx, syntheticsType, "public_id", test)
+	if err != nil {
+		if err == errInvalidComponent {
+			return ErrInvalidSynthetics
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// readOnlySyntheticsFields are set by datadog and must not be sent on create.
+var readOnlySyntheticsFields = []string{"public_id", "monitor_id", "creator", "created_at", "modified_at", "created_by", "modified_by"}
+
+// CreateSyntheticsTest creates an API or a browser test, depending on the type field, and returns the new public ID.
+func (c Client) CreateSyntheticsTest(ctx context.Context, test json.RawMessage) (string, error) {
+	t := struct {
+		Type string `json:"type"`
+	}{}
+
+	if err := json.Unmarshal(test, &t); err != nil {
+		return "", errors.Wrap(err, "unable to unmarshal synthetic test")
+	}
+
+	if t.Type != "api" && t.Type != "browser" {
+		return "", ErrInvalidSynthetics
+	}
+
+	resp, err := c.genericCreate(ctx, Component(fmt.Sprintf("%s/%s", syntheticsType, t.Type)), test, readOnlySyntheticsFields)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := createdID(resp, "public_id")
+	if err != nil {
+		return "", ErrInvalidSynthetics
+	}
+
+	return id, nil
+}
+
+// DeleteSyntheticsTest deletes a synthetic test by public ID.
+func (c Client) DeleteSyntheticsTest(ctx context.Context, publicID string) error {
+	body, err := json.Marshal(map[string][]string{"public_ids": {publicID}})
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal synthetic test ids")
+	}
+
+	_, err = c.do(ctx, "POST", fmt.Sprintf("%s/delete", syntheticsType), bytes.NewReader(body))
+	if err != nil && !IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete synthetic test %s", publicID)
+	}
+
+	return nil
+}
+
+// Summaries returns public IDs, names, tags and creators of the synthetic tests.
+func (sr SyntheticsResponse) Summaries() ([]Summary, error) {
+	var resp struct {
+		Tests []struct {
+			PublicID string   `json:"public_id"`
+			Name     string   `json:"name"`
+			Tags     []string `json:"tags"`
+			Creator  *creator `json:"creator"`
+		} `json:"tests"`
+	}
+
+	err := json.Unmarshal(sr, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal synthetics response")
+	}
+
+	var summaries []Summary
+	for _, test := range resp.Tests {
+		summaries = append(summaries, Summary{ID: test.PublicID, Title: test.Name, Tags: test.Tags, Creator: test.Creator.String()})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var exampleSyntheticsResponse = `
+{
+  "tests": [
+    {
+      "status": "live",
+      "public_id": "abc-def-ghi",
+      "tags": ["team:sre"],
+      "locations": ["aws:us-east-2"],
+      "message": "api is down @slack-sre",
+      "modified_at": "2019-03-10T12:00:01.154250+00:00",
+      "name": "health check",
+      "type": "api",
+      "created_at": "2019-03-01T09:10:11.000000+00:00",
+      "monitor_id": 123456
+    },
+    {
+      "status": "paused",
+      "public_id": "jkl-mno-pqr",
+      "tags": [],
+      "locations": ["aws:eu-central-1"],
+      "message": "",
+      "modified_at": "2019-03-11T08:30:00.000000+00:00",
+      "name": "login flow",
+      "type": "browser",
+      "created_at": "2019-03-02T09:10:11.000000+00:00",
+      "monitor_id": 654321
+    }
+  ]
+}
+`
+
+func TestSyntheticsResponse_GetModifiedIDsWithin(t *testing.T) {
+	sr := SyntheticsResponse(json.RawMessage(exampleSyntheticsResponse))
+	fn := func(t time.Time) time.Duration {
+		return time.Millisecond
+	}
+
+	ids, err := sr.GetModifiedIDsWithin(time.Second, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 {
+		t.Fatalf("expect 2 ids. Got %d", len(ids))
+	}
+
+	if ids[0] != "abc-def-ghi" || ids[1] != "jkl-mno-pqr" {
+		t.Fatalf("expect ids abc-def-ghi and jkl-mno-pqr. Got %v", ids)
+	}
+}
+
+func TestClient_GetSyntheticsTest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/synthetics/tests/abc-def-ghi" {
+			t.Fatalf("expect url /synthetics/tests/abc-def-ghi Got %s", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Fatalf("expect method GET. Got %s", r.Method)
+		}
+
+		fmt.Fprint(w, `{"public_id":"abc-def-ghi","name":"health check"}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	_, err = c.GetSyntheticsTest(context.Background(), "abc-def-ghi")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_UpdateSyntheticsTest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/synthetics/tests/abc-def-ghi" {
+			t.Fatalf("expect /synthetics/tests/abc-def-ghi Got %s", r.URL.Path)
+		}
+
+		if r.Method != "PUT" {
+			t.Fatalf("expect method PUT. Got %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	err = c.UpdateSyntheticsTest(context.Background(), []byte(`{"public_id":"abc-def-ghi","name":"health check"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateSyntheticsTest(context.Background(), []byte(`{"name":"health check"}`))
+	if err != ErrInvalidSynthetics {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidSynthetics, err)
+	}
+}
+
+func TestClient_CreateSyntheticsTest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/synthetics/tests/browser" {
+			t.Fatalf("expect POST /synthetics/tests/browser. Got %s %s", r.Method, r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"public_id":"abc-def-ghi","type":"browser"}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	id, err := c.CreateSyntheticsTest(context.Background(), json.RawMessage(`{"type":"browser","name":"login"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "abc-def-ghi" {
+		t.Fatalf("expect public id abc-def-ghi. Got %s", id)
+	}
+
+	if _, err := c.CreateSyntheticsTest(context.Background(), json.RawMessage(`{"name":"no type"}`)); err != ErrInvalidSynthetics {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidSynthetics, err)
+	}
+}
+
+func TestClient_DeleteSyntheticsTest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/synthetics/tests/delete" {
+			t.Fatalf("expect POST /synthetics/tests/delete. Got %s %s", r.Method, r.URL.Path)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != `{"public_ids":["abc-def-ghi"]}` {
+			t.Fatalf("expect public ids in body. Got %s", body)
+		}
+
+		fmt.Fprint(w, `{"deleted_tests":[{"public_id":"abc-def-ghi"}]}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	if err := c.DeleteSyntheticsTest(context.Background(), "abc-def-ghi"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UnifiedDashboardsResponse represents a response from calling /api/v1/dashboard endpoint.
+type UnifiedDashboardsResponse json.RawMessage
+
+// GetModifiedIDsWithin returns a list of dashboard IDs if the modified_at field was changed within the given interval.
+func (ur UnifiedDashboardsResponse) GetModifiedIDsWithin(interval time.Duration, fn func(time.Time) time.Duration) ([]string, error) {
+	if fn == nil {
+		fn = time.Since
+	}
+
+	var resp struct {
+		Dashboards []struct {
+			ID         string `json:"id"`
+			ModifiedAt string `json:"modified_at"`
+		} `json:"dashboards"`
+	}
+
+	err := json.Unmarshal(ur, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal unified dashboards response")
+	}
+
+	var ids []string
+	for _, d := range resp.Dashboards {
+		if d.ModifiedAt == "" {
+			return nil, fmt.Errorf("empty modified_at field, full response: %+v", resp)
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, d.ModifiedAt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse modified_at field %s", d.ModifiedAt)
+		}
+
+		if fn(t) < interval {
+			ids = append(ids, d.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetUnifiedDashboard returns a raw json of a dashboard from the unified dashboard API.
+func (c Client) GetUnifiedDashboard(ctx context.Context, id string) (json.RawMessage, error) {
+	resp, err := c.do(ctx, "GET", fmt.Sprintf("%s/%s", unifiedDashType, url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stripJSONFields(resp, c.removeUnifiedDashboardFields)
+}
+
+// UpdateUnifiedDashboard updates the unified dashboard from json raw message.
+func (c Client) UpdateUnifiedDashboard(ctx context.Context, dashboard json.RawMessage) error {
+	err := c.genericUpdateByStringID(ctx, unifiedDashType, "id", dashboard)
+	if err != nil {
+		if err == errInvalidComponent {
+			return ErrInvalidUnifiedDashboard
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GetUnifiedDashboards returns a list of dashboards from the unified dashboard API.
+func (c Client) GetUnifiedDashboards(ctx context.Context) (UnifiedDashboardsResponse, error) {
+	return c.do(ctx, "GET", string(unifiedDashType), nil)
+}
+
+// readOnlyUnifiedDashboardFields are set by datadog and must not be sent on create.
+var readOnlyUnifiedDashboardFields = []string{"id", "url", "author_handle", "created_at", "modified_at"}
+
+// CreateUnifiedDashboard creates a dashboard and returns the new ID.
+func (c Client) CreateUnifiedDashboard(ctx context.Context, dashboard json.RawMessage) (string, error) {
+	resp, err := c.genericCreate(ctx, unifiedDashType, dashboard, readOnlyUnifiedDashboardFields)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := createdID(resp, "id")
+	if err != nil {
+		return "", ErrInvalidUnifiedDashboard
+	}
+
+	return id, nil
+}
+
+// DeleteUnifiedDashboard deletes a dashboard by ID.
+func (c Client) DeleteUnifiedDashboard(ctx context.Context, id string) error {
+	return c.genericDelete(ctx, unifiedDashType, id)
+}
+
+// Summaries returns IDs, titles and author handles of the dashboards.
+func (ur UnifiedDashboardsResponse) Summaries() ([]Summary, error) {
+	var resp struct {
+		Dashboards []struct {
+			ID           string `json:"id"`
+			Title        string `json:"title"`
+			AuthorHandle string `json:"author_handle"`
+		} `json:"dashboards"`
+	}
+
+	err := json.Unmarshal(ur, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal dashboards response")
+	}
+
+	var summaries []Summary
+	for _, d := range resp.Dashboards {
+		summaries = append(summaries, Summary{ID: d.ID, Title: d.Title, Creator: d.AuthorHandle})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var exampleUnifiedDashboardsResponse = `
+{
+  "dashboards": [
+    {
+      "created_at": "2019-02-05T01:06:36.636295+00:00",
+      "is_read_only": false,
+      "description": "created by foo/bar",
+      "title": "test dashboard",
+      "url": "/dashboard/abc-def-ghi/test-dashboard",
+      "layout_type": "ordered",
+      "modified_at": "2019-02-05T01:35:46.388000+00:00",
+      "author_handle": "foo.bar@test.com",
+      "id": "abc-def-ghi"
+    },
+    {
+      "created_at": "2019-01-10T19:12:08.442041+00:00",
+      "is_read_only": true,
+      "description": null,
+      "title": "another dashboard",
+      "url": "/dashboard/jkl-mno-pqr/another-dashboard",
+      "layout_type": "free",
+      "modified_at": "2019-01-11T10:01:02.000000+00:00",
+      "author_handle": "foo.bar@test.com",
+      "id": "jkl-mno-pqr"
+    }
+  ]
+}
+`
+
+func TestClient_UpdateUnifiedDashboard(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dashboard/abc-def-ghi" {
+			t.Fatalf("expect /dashboard/abc-def-ghi Got %s", r.URL.Path)
+		}
+
+		if r.Method != "PUT" {
+			t.Fatalf("expect method PUT. Got %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	err = c.UpdateUnifiedDashboard(context.Background(), []byte(`{"id": "abc-def-ghi", "title": "test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateUnifiedDashboard(context.Background(), []byte(`{"title": "test"}`))
+	if err != ErrInvalidUnifiedDashboard {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidUnifiedDashboard, err)
+	}
+}
+
+func TestClient_GetUnifiedDashboard(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dashboard/abc-def-ghi" {
+			t.Fatalf("expect url /dashboard/abc-def-ghi Got %s", r.URL.Path)
+		}
+
+		if r.Method != "GET" {
+			t.Fatalf("expect method GET. Got %s", r.Method)
+		}
+
+		fmt.Fprint(w, `{"id":"abc-def-ghi","title":"test","modified_at":"2019-02-05T01:35:46.388000+00:00"}`)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456", WithRemoveUnifiedDashboardFields([]string{"modified_at"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	resp, err := c.GetUnifiedDashboard(context.Background(), "abc-def-ghi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dashboard := map[string]interface{}{}
+	if err := json.Unmarshal(resp, &dashboard); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := dashboard["modified_at"]; ok {
+		t.Fatalf("expect modified_at field to be removed. Got %s", resp)
+	}
+}
+
+func TestUnifiedDashboardsResponse_GetModifiedIDsWithin(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dashboard" {
+			t.Fatalf("expect url /dashboard Got %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, exampleUnifiedDashboardsResponse)
+	}))
+	defer ts.Close()
+
+	c, err := New("123", "456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseEndpoint = ts.URL
+
+	dashboards, err := c.GetUnifiedDashboards(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := dashboards.GetModifiedIDsWithin(time.Second, func(t time.Time) time.Duration {
+		return time.Millisecond
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 {
+		t.Fatalf("expect 2 ids. Got %d", len(ids))
+	}
+
+	if ids[0] != "abc-def-ghi" || ids[1] != "jkl-mno-pqr" {
+		t.Fatalf("expect ids abc-def-ghi and jkl-mno-pqr. Got %v", ids)
+	}
+}
@@ -0,0 +1,714 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+)
+
+// ComponentTypeOption is a functional parameter for NewComponentType.
+type ComponentTypeOption func(ct *componentType)
+
+// WithCreateFn makes a component type creatable from component files. The idFn returns an ID stored
+// in a payload and createFn creates a component and returns the new ID.
+func WithCreateFn(idFn func(json.RawMessage) (string, error), createFn func(context.Context, json.RawMessage) (string, error)) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.idFn = idFn
+		ct.createFn = createFn
+	}
+}
+
+// WithDeleteFn makes a component type deletable when its component file is removed.
+func WithDeleteFn(deleteFn func(context.Context, string) error) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.deleteFn = deleteFn
+	}
+}
+
+// WithListFn makes a component type selectable by selectors in user config. The listFn returns summaries
+// of all components of the type.
+func WithListFn(listFn func(context.Context) ([]types.Summary, error)) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.listFn = listFn
+	}
+}
+
+// WithValidateFn makes a component type validated in pull requests. The validateFn returns the errors
+// found by datadog in a payload.
+func WithValidateFn(validateFn func(context.Context, json.RawMessage) ([]string, error)) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.validateFn = validateFn
+	}
+}
+
+// WithExistsFn sets a function which requests only the component itself, without its dependencies, to check if the
+// component exists. The existsFn returns a client.NotFoundError if the component does not exist. Without it the
+// whole payload is requested.
+func WithExistsFn(existsFn func(context.Context, string) error) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.existsFn = existsFn
+	}
+}
+
+// WithNormalizeFn sets a function which removes the fields datadog changes on its own from a payload before the
+// payloads are compared by content. Without it the payload is compared as it is.
+func WithNormalizeFn(normalizeFn func(json.RawMessage) (json.RawMessage, error)) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.normalizeFn = normalizeFn
+	}
+}
+
+// WithModifiedByFn makes a component type tell who changed a component. It is set only for the types whose
+// payload holds the user who changed it last, the other types do not implement types.Modifier.
+func WithModifiedByFn(modifiedByFn func(context.Context, string) (string, error)) ComponentTypeOption {
+	return func(ct *componentType) {
+		ct.modifiedByFn = modifiedByFn
+	}
+}
+
+// NewComponentType returns an implementation of types.ComponentType based on accessor functions.
+// If modifiedFn is nil, the component type is not polled for changes.
+func NewComponentType(component types.Component, configKey string,
+	getFn func(context.Context, string) (json.RawMessage, error),
+	modifiedFn func(context.Context, time.Duration) ([]string, error),
+	updateFn func(context.Context, json.RawMessage) error, opts ...ComponentTypeOption) types.ComponentType {
+	ct := &componentType{
+		component:  component,
+		configKey:  configKey,
+		getFn:      getFn,
+		modifiedFn: modifiedFn,
+		updateFn:   updateFn,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(ct)
+		}
+	}
+
+	if ct.modifiedByFn != nil {
+		return &modifierComponentType{ct}
+	}
+
+	return ct
+}
+
+// componentType is a generic component type which delegates the calls to accessor functions.
+type componentType struct {
+	component types.Component
+	configKey string
+
+	getFn      func(context.Context, string) (json.RawMessage, error)
+	modifiedFn func(context.Context, time.Duration) ([]string, error)
+	updateFn   func(context.Context, json.RawMessage) error
+
+	idFn     func(json.RawMessage) (string, error)
+	createFn func(context.Context, json.RawMessage) (string, error)
+	deleteFn func(context.Context, string) error
+	listFn   func(context.Context) ([]types.Summary, error)
+
+	validateFn func(context.Context, json.RawMessage) ([]string, error)
+	existsFn   func(context.Context, string) error
+
+	normalizeFn  func(json.RawMessage) (json.RawMessage, error)
+	modifiedByFn func(context.Context, string) (string, error)
+}
+
+// modifierComponentType is a component type which could tell who changed a component.
+type modifierComponentType struct {
+	*componentType
+}
+
+// ModifiedBy returns a handle of the user who changed a component.
+func (ct *modifierComponentType) ModifiedBy(ctx context.Context, id string) (string, error) {
+	return ct.modifiedByFn(ctx, id)
+}
+
+// Type returns a component type.
+func (ct *componentType) Type() types.Component {
+	return ct.component
+}
+
+// ConfigKey returns a key in a user config file.
+func (ct *componentType) ConfigKey() string {
+	return ct.configKey
+}
+
+// Get returns a component payload.
+func (ct *componentType) Get(ctx context.Context, id string) (json.RawMessage, error) {
+	if ct.getFn == nil {
+		return nil, ErrNilFunction
+	}
+
+	return ct.getFn(ctx, id)
+}
+
+// Exists returns false if a component does not exist in datadog.
+func (ct *componentType) Exists(ctx context.Context, id string) (bool, error) {
+	var err error
+	if ct.existsFn != nil {
+		err = ct.existsFn(ctx, id)
+	} else {
+		_, err = ct.Get(ctx, id)
+	}
+
+	if client.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Normalize returns a payload without the fields datadog changes on its own.
+func (ct *componentType) Normalize(payload json.RawMessage) (json.RawMessage, error) {
+	if ct.normalizeFn == nil {
+		return payload, nil
+	}
+
+	return ct.normalizeFn(payload)
+}
+
+// ModifiedIDs returns a list of modified component IDs.
+func (ct *componentType) ModifiedIDs(ctx context.Context, interval time.Duration) ([]string, error) {
+	if ct.modifiedFn == nil {
+		return nil, nil
+	}
+
+	return ct.modifiedFn(ctx, interval)
+}
+
+// Update restores a component from payload.
+func (ct *componentType) Update(ctx context.Context, payload json.RawMessage) error {
+	if ct.updateFn == nil {
+		return ErrNilFunction
+	}
+
+	return ct.updateFn(ctx, payload)
+}
+
+// PayloadID returns a component ID stored in a payload.
+func (ct *componentType) PayloadID(payload json.RawMessage) (string, error) {
+	if ct.idFn == nil {
+		return "", ErrCreateNotSupported
+	}
+
+	return ct.idFn(payload)
+}
+
+// Create creates a component from payload.
+func (ct *componentType) Create(ctx context.Context, payload json.RawMessage) (string, error) {
+	if ct.createFn == nil {
+		return "", ErrCreateNotSupported
+	}
+
+	return ct.createFn(ctx, payload)
+}
+
+// Delete deletes a component by ID.
+func (ct *componentType) Delete(ctx context.Context, id string) error {
+	if ct.deleteFn == nil {
+		return ErrDeleteNotSupported
+	}
+
+	return ct.deleteFn(ctx, id)
+}
+
+// List returns summaries of all components of the type.
+func (ct *componentType) List(ctx context.Context) ([]types.Summary, error) {
+	if ct.listFn == nil {
+		return nil, ErrSelectNotSupported
+	}
+
+	return ct.listFn(ctx)
+}
+
+// Validate returns the errors found in a payload.
+func (ct *componentType) Validate(ctx context.Context, payload json.RawMessage) ([]string, error) {
+	if ct.validateFn == nil {
+		return nil, ErrValidateNotSupported
+	}
+
+	return ct.validateFn(ctx, payload)
+}
+
+// DefaultComponentTypes returns the component types supported by watchdog out of the box.
+func DefaultComponentTypes(c *client.Client) []types.ComponentType {
+	return []types.ComponentType{
+		NewComponentType(types.ComponentDashboard, "dashboards",
+			intID(c.GetDashboard),
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				dashboards, err := c.GetDashboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get dashboards")
+				}
+
+				return toStringIDs(dashboards.GetModifiedIDsWithin(interval, nil))
+			},
+			c.UpdateDashboard,
+			WithCreateFn(idAt("dash", "id"), intCreate(c.CreateDashboard)),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetDashboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get dashboards")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			WithDeleteFn(intDelete(c.DeleteDashboard))),
+
+		NewComponentType(types.ComponentUnifiedDashboard, "unifiedDashboards",
+			c.GetUnifiedDashboard,
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				dashboards, err := c.GetUnifiedDashboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get unified dashboards")
+				}
+
+				return dashboards.GetModifiedIDsWithin(interval, nil)
+			},
+			c.UpdateUnifiedDashboard,
+			WithCreateFn(idAt("id"), c.CreateUnifiedDashboard),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetUnifiedDashboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get unified dashboards")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			WithDeleteFn(c.DeleteUnifiedDashboard)),
+
+		NewComponentType(types.ComponentMonitor, "monitors",
+			func(ctx context.Context, id string) (json.RawMessage, error) {
+				monitorID, err := strconv.Atoi(id)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid monitor id %s", id)
+				}
+
+				monitor, err := c.GetMonitorWithDependencies(ctx, monitorID, true)
+				if err != nil {
+					return nil, err
+				}
+
+				return marshalPayload(monitor)
+			},
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				monitors, err := c.GetMonitors(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get monitors")
+				}
+
+				return toStringIDs(monitors.GetModifiedIDsWithin(interval, nil))
+			},
+			func(ctx context.Context, payload json.RawMessage) error {
+				monitor := &client.MonitorWithDependencies{}
+				if err := json.Unmarshal(payload, monitor); err != nil {
+					return errors.Wrap(err, "unable to unmarshal a monitor")
+				}
+
+				return c.UpdateMonitorWithDependencies(ctx, monitor)
+			},
+			WithCreateFn(idAt("monitor", "id"), func(ctx context.Context, payload json.RawMessage) (string, error) {
+				monitor := &client.MonitorWithDependencies{}
+				if err := json.Unmarshal(payload, monitor); err != nil {
+					return "", errors.Wrap(err, "unable to unmarshal a monitor")
+				}
+
+				id, err := c.CreateMonitorWithDependencies(ctx, monitor)
+				if id == 0 {
+					return "", err
+				}
+
+				return strconv.Itoa(id), err
+			}),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetMonitors(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get monitors")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			WithValidateFn(func(ctx context.Context, payload json.RawMessage) ([]string, error) {
+				monitor := &client.MonitorWithDependencies{}
+				if err := json.Unmarshal(payload, monitor); err != nil {
+					return nil, errors.Wrap(err, "unable to unmarshal a monitor")
+				}
+
+				return c.ValidateMonitor(ctx, monitor.Monitor)
+			}),
+			WithExistsFn(func(ctx context.Context, id string) error {
+				monitorID, err := strconv.Atoi(id)
+				if err != nil {
+					return errors.Wrapf(err, "invalid monitor id %s", id)
+				}
+
+				_, err = c.GetMonitor(ctx, monitorID)
+				return err
+			}),
+			WithNormalizeFn(client.NormalizeMonitorWithDependencies),
+			WithDeleteFn(intDelete(c.DeleteMonitor))),
+
+		NewComponentType(types.ComponentScreenboard, "screenboards",
+			intID(c.GetScreenboard),
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				screenBoards, err := c.GetScreenboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get screenboards")
+				}
+
+				return toStringIDs(screenBoards.GetModifiedIDsWithin(interval, nil))
+			},
+			c.UpdateScreenboard,
+			WithCreateFn(idAt("id"), intCreate(c.CreateScreenboard)),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetScreenboards(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get screenboards")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			WithDeleteFn(intDelete(c.DeleteScreenboard))),
+
+		// downtimes do not have a modified field, the content is compared with the previous poll.
+		NewComponentType(types.ComponentDowntime, "downtimes",
+			intID(c.GetDowntime),
+			modifiedByFingerprints(func(ctx context.Context) (map[string]string, error) {
+				downtimes, err := c.GetDowntimesResponse(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get downtimes")
+				}
+
+				return downtimes.Fingerprints()
+			}),
+			c.UpdateDowntime,
+			WithCreateFn(idAt("id"), intCreate(c.CreateDowntime)),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				downtimes, err := c.GetDowntimesResponse(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get downtimes")
+				}
+
+				return toSummaries(downtimes.Summaries())
+			}),
+			WithNormalizeFn(client.NormalizeDowntime),
+			WithDeleteFn(intDelete(c.CancelDowntime))),
+
+		NewComponentType(types.ComponentSynthetics, "synthetics",
+			c.GetSyntheticsTest,
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				tests, err := c.GetSyntheticsTests(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get synthetic tests")
+				}
+
+				return tests.GetModifiedIDsWithin(interval, nil)
+			},
+			c.UpdateSyntheticsTest,
+			WithCreateFn(idAt("public_id"), c.CreateSyntheticsTest),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetSyntheticsTests(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get synthetic tests")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			// synthetic tests are the only type which holds the user who changed it in the payload.
+			WithModifiedByFn(func(ctx context.Context, id string) (string, error) {
+				payload, err := c.GetSyntheticsTest(ctx, id)
+				if err != nil {
+					return "", err
+				}
+
+				return PayloadModifier(payload), nil
+			}),
+			WithDeleteFn(c.DeleteSyntheticsTest)),
+
+		NewComponentType(types.ComponentSLO, "slos",
+			c.GetSLO,
+			func(ctx context.Context, interval time.Duration) ([]string, error) {
+				slos, err := c.GetSLOs(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get service level objectives")
+				}
+
+				return slos.GetModifiedIDsWithin(interval, nil)
+			},
+			c.UpdateSLO,
+			WithCreateFn(idAt("id"), c.CreateSLO),
+			WithListFn(func(ctx context.Context) ([]types.Summary, error) {
+				list, err := c.GetSLOs(ctx)
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to get service level objectives")
+				}
+
+				return toSummaries(list.Summaries())
+			}),
+			WithDeleteFn(c.DeleteSLO)),
+
+		newLogPipelineComponentType(c),
+	}
+}
+
+// NewRegistry returns a registry with the default component types.
+func NewRegistry(c *client.Client) (*types.Registry, error) {
+	return types.NewRegistry(DefaultComponentTypes(c)...)
+}
+
+// logPipelineComponentType manages log pipelines. Log pipelines do not have a modified field,
+// the fingerprints of pipelines and their positions from the last call are used to detect a change.
+type logPipelineComponentType struct {
+	getPipelineFn    func(context.Context, string) (*client.LogPipeline, error)
+	getPipelinesFn   func(context.Context) (client.LogPipelinesResponse, error)
+	getOrderFn       func(context.Context) ([]string, error)
+	updatePipelineFn func(context.Context, *client.LogPipeline) error
+	createPipelineFn func(context.Context, *client.LogPipeline) (string, error)
+	deletePipelineFn func(context.Context, string) error
+
+	changes *fingerprints
+}
+
+func newLogPipelineComponentType(c *client.Client) *logPipelineComponentType {
+	return &logPipelineComponentType{
+		getPipelineFn:    c.GetLogPipeline,
+		getPipelinesFn:   c.GetLogPipelines,
+		getOrderFn:       c.GetLogPipelineOrder,
+		updatePipelineFn: c.UpdateLogPipeline,
+		createPipelineFn: c.CreateLogPipeline,
+		deletePipelineFn: c.DeleteLogPipeline,
+
+		changes: &fingerprints{},
+	}
+}
+
+// Type returns a log pipeline component type.
+func (lp *logPipelineComponentType) Type() types.Component {
+	return types.ComponentLogPipeline
+}
+
+// ConfigKey returns a key in a user config file.
+func (lp *logPipelineComponentType) ConfigKey() string {
+	return "logPipelines"
+}
+
+// Get returns a log pipeline with its position in the pipeline order.
+func (lp *logPipelineComponentType) Get(ctx context.Context, id string) (json.RawMessage, error) {
+	pipeline, err := lp.getPipelineFn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalPayload(pipeline)
+}
+
+// ModifiedIDs compares fingerprints of pipelines and their positions with the previous call.
+// The first call only remembers the fingerprints.
+func (lp *logPipelineComponentType) ModifiedIDs(ctx context.Context, interval time.Duration) ([]string, error) {
+	pipelines, err := lp.getPipelinesFn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get log pipelines")
+	}
+
+	order, err := lp.getOrderFn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get log pipeline order")
+	}
+
+	current, err := pipelines.Fingerprints(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return lp.changes.update(current), nil
+}
+
+// Update restores a log pipeline and its position in the pipeline order.
+func (lp *logPipelineComponentType) Update(ctx context.Context, payload json.RawMessage) error {
+	pipeline := &client.LogPipeline{}
+	if err := json.Unmarshal(payload, pipeline); err != nil {
+		return errors.Wrap(err, "unable to unmarshal a log pipeline")
+	}
+
+	return lp.updatePipelineFn(ctx, pipeline)
+}
+
+// PayloadID returns a log pipeline ID stored in a payload.
+func (lp *logPipelineComponentType) PayloadID(payload json.RawMessage) (string, error) {
+	return idAt("pipeline", "id")(payload)
+}
+
+// Create creates a log pipeline at the stored position in the pipeline order.
+func (lp *logPipelineComponentType) Create(ctx context.Context, payload json.RawMessage) (string, error) {
+	pipeline := &client.LogPipeline{}
+	if err := json.Unmarshal(payload, pipeline); err != nil {
+		return "", errors.Wrap(err, "unable to unmarshal a log pipeline")
+	}
+
+	return lp.createPipelineFn(ctx, pipeline)
+}
+
+// Delete deletes a log pipeline by ID.
+func (lp *logPipelineComponentType) Delete(ctx context.Context, id string) error {
+	return lp.deletePipelineFn(ctx, id)
+}
+
+// fingerprints holds content hashes of components from the previous poll. It is used for
+// the component types which do not have a modified field.
+type fingerprints struct {
+	sync.Mutex
+	seeded bool
+	hashes map[string]string
+}
+
+// update stores the current fingerprints and returns the IDs which are new or changed since
+// the previous call. The first call only remembers the fingerprints.
+func (f *fingerprints) update(current map[string]string) []string {
+	f.Lock()
+	defer f.Unlock()
+
+	var ids []string
+	if f.seeded {
+		for id, fingerprint := range current {
+			if f.hashes[id] != fingerprint {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	f.hashes = current
+	f.seeded = true
+	return ids
+}
+
+// modifiedByFingerprints returns a function to detect changes by comparing content fingerprints between calls.
+func modifiedByFingerprints(fn func(context.Context) (map[string]string, error)) func(context.Context, time.Duration) ([]string, error) {
+	changes := &fingerprints{}
+	return func(ctx context.Context, interval time.Duration) ([]string, error) {
+		current, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return changes.update(current), nil
+	}
+}
+
+// intID converts an accessor function with integer ID to an accessor function with string ID.
+func intID(fn func(context.Context, int) (json.RawMessage, error)) func(context.Context, string) (json.RawMessage, error) {
+	return func(ctx context.Context, id string) (json.RawMessage, error) {
+		intID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid id %s, expect integer", id)
+		}
+
+		return fn(ctx, intID)
+	}
+}
+
+// toSummaries converts summaries from the client to summaries of component types.
+func toSummaries(summaries []client.Summary, err error) ([]types.Summary, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]types.Summary, 0, len(summaries))
+	for _, s := range summaries {
+		out = append(out, types.Summary{ID: s.ID, Title: s.Title, Tags: s.Tags, Creator: s.Creator})
+	}
+
+	return out, nil
+}
+
+// intDelete converts a delete function which takes an integer ID to a function which takes a string ID.
+func intDelete(fn func(context.Context, int) error) func(context.Context, string) error {
+	return func(ctx context.Context, id string) error {
+		intID, err := strconv.Atoi(id)
+		if err != nil {
+			return errors.Wrapf(err, "invalid id %s, expect integer", id)
+		}
+
+		return fn(ctx, intID)
+	}
+}
+
+// intCreate converts a create function which returns an integer ID to a function which returns a string ID.
+func intCreate(fn func(context.Context, json.RawMessage) (int, error)) func(context.Context, json.RawMessage) (string, error) {
+	return func(ctx context.Context, payload json.RawMessage) (string, error) {
+		id, err := fn(ctx, payload)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.Itoa(id), nil
+	}
+}
+
+// idAt returns a function which reads an ID from a nested field of a payload. Integer and string IDs
+// are returned as strings, a missing or null field is an empty ID.
+func idAt(path ...string) func(json.RawMessage) (string, error) {
+	return func(payload json.RawMessage) (string, error) {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(payload))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return "", errors.Wrap(err, "unable to unmarshal payload")
+		}
+
+		for _, field := range path {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return "", nil
+			}
+
+			v = m[field]
+		}
+
+		switch id := v.(type) {
+		case json.Number:
+			return id.String(), nil
+		case string:
+			return id, nil
+		case nil:
+			return "", nil
+		default:
+			return "", errors.Errorf("invalid id %v at %s", v, strings.Join(path, "."))
+		}
+	}
+}
+
+// toStringIDs converts the integer IDs returned by datadog client to strings.
+func toStringIDs(ids []int, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, id := range ids {
+		out = append(out, strconv.Itoa(id))
+	}
+
+	return out, nil
+}
+
+// marshalPayload marshals a structured payload the same way component files are encoded.
+func marshalPayload(v interface{}) (json.RawMessage, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, errors.Wrap(err, "unable to marshal payload")
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -1,6 +1,7 @@
 package datadog
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 
@@ -25,20 +26,14 @@ func New(apiKey, appKey string, c *client.Client, opts ...Option) (*Datadog, err
 		}
 	}
 
-	dd := &Datadog{
-		Client: c,
-
-		getDashboardFn:   c.GetDashboard,
-		getMonitorFullFn: c.GetMonitorWithDependencies,
-		getAlertFn:       c.GetAlert,
-		getDowntimeFn:    c.GetDowntime,
-		getScreenBoardFn: c.GetScreenboard,
+	registry, err := NewRegistry(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create a component type registry")
+	}
 
-		updateDashboardFn:   c.UpdateDashboard,
-		updateMonitorFn:     c.UpdateMonitorWithDependencies,
-		updateAlertFn:       c.UpdateAlert,
-		updateDowntimeFn:    c.UpdateDowntime,
-		updateScreenBoardFn: c.UpdateScreenboard,
+	dd := &Datadog{
+		Client:   c,
+		Registry: registry,
 	}
 
 	for _, opt := range opts {
@@ -55,37 +50,29 @@ func New(apiKey, appKey string, c *client.Client, opts ...Option) (*Datadog, err
 
 // Datadog is a abstraction over datadog api library.
 // The abstraction provides simplified interface to query datadog API.
+// Supported component types are defined by the registry.
 type Datadog struct {
-	Client *client.Client
-
-	getDashboardFn   func(int) (json.RawMessage, error)
-	getMonitorFullFn func(int, bool) (*client.MonitorWithDependencies, error)
-	getAlertFn       func(int) (json.RawMessage, error)
-	getDowntimeFn    func(int) (json.RawMessage, error)
-	getScreenBoardFn func(int) (json.RawMessage, error)
-
-	updateDashboardFn   func(json.RawMessage) error
-	updateMonitorFn     func(*client.MonitorWithDependencies) error
-	updateAlertFn       func(json.RawMessage) error
-	updateDowntimeFn    func(json.RawMessage) error
-	updateScreenBoardFn func(json.RawMessage) error
+	Client   *client.Client
+	Registry *types.Registry
 }
 
 // Write takes a datadog component type ID (dashboard, monitor etc.), id from a datadog and queries the
 // corresponding datadog API. The the JSON response will be written to io.Writer.
-func (dd *Datadog) Write(component types.Component, id int, to io.Writer) error {
-	switch component {
-	case types.ComponentDashboard:
-		return dd.writeDashboard(id, to)
-	case types.ComponentMonitor:
-		return dd.writeMonitor(id, to)
-	case types.ComponentDowntime:
-		return dd.writeDowntime(id, to)
-	case types.ComponentScreenboard:
-		return dd.writeScreenBoard(id, to)
-	}
-
-	return ErrInvalidComponentTypeID
+func (dd *Datadog) Write(ctx context.Context, component types.Component, id string, to io.Writer) error {
+	ct, ok := dd.Registry.Get(component)
+	if !ok {
+		return ErrInvalidComponentTypeID
+	}
+
+	payload, err := ct.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get %s %s", component, id)
+	}
+
+	return dd.marshalAndWrite(&Component{
+		Type:    component,
+		Payload: payload,
+	}, to)
 }
 
 func (dd *Datadog) marshalAndWrite(component *Component, to io.Writer) error {
@@ -95,106 +82,117 @@ func (dd *Datadog) marshalAndWrite(component *Component, to io.Writer) error {
 	return enc.Encode(component)
 }
 
-func (dd *Datadog) writeDashboard(id int, to io.Writer) error {
-	dashboard, err := dd.getDashboardFn(id)
+// Normalize removes the fields datadog changes on its own from a component payload, e.g. the next occurrence
+// of a recurring downtime, so a component could be compared by content.
+func (dd *Datadog) Normalize(component *Component) error {
+	ct, ok := dd.Registry.Get(component.Type)
+	if !ok {
+		return ErrInvalidComponentTypeID
+	}
+
+	normalizer, ok := ct.(types.Normalizer)
+	if !ok {
+		return nil
+	}
+
+	payload, err := normalizer.Normalize(component.Payload)
 	if err != nil {
-		return errors.Wrapf(err, "unable to get dashboard %d", id)
+		return errors.Wrapf(err, "unable to normalize %s", component.Type)
 	}
 
-	return dd.marshalAndWrite(&Component{
-		Type:      types.ComponentDashboard,
-		Dashboard: dashboard,
-	}, to)
+	component.Payload = payload
+	return nil
 }
 
-func (dd *Datadog) writeMonitor(id int, to io.Writer) error {
-	monitor, err := dd.getMonitorFullFn(id, false)
-	if err != nil {
-		return errors.Wrapf(err, "unable to get monitor %d", id)
+// Update will restore a datadog component from bytes.
+func (dd *Datadog) Update(ctx context.Context, component *Component) error {
+	ct, ok := dd.Registry.Get(component.Type)
+	if !ok {
+		return ErrInvalidComponentTypeID
 	}
 
-	return dd.marshalAndWrite(&Component{
-		Type:    types.ComponentMonitor,
-		Monitor: monitor,
-	}, to)
+	if err := ct.Update(ctx, component.Payload); err != nil {
+		return errors.Wrapf(err, "unable to update %s", component.Type)
+	}
+
+	return nil
 }
 
-func (dd *Datadog) writeDowntime(id int, to io.Writer) error {
-	downtime, err := dd.getDowntimeFn(id)
+// PayloadID returns an ID stored in a component payload, empty if the component has no ID yet.
+func (dd *Datadog) PayloadID(component *Component) (string, error) {
+	creator, err := dd.creator(component.Type)
 	if err != nil {
-		return errors.Wrapf(err, "unable to get downtime %d", id)
+		return "", err
 	}
 
-	return dd.marshalAndWrite(&Component{
-		Type:     types.ComponentDowntime,
-		Downtime: downtime,
-	}, to)
+	return creator.PayloadID(component.Payload)
 }
 
-func (dd *Datadog) writeScreenBoard(id int, to io.Writer) error {
-	sb, err := dd.getScreenBoardFn(id)
+// Create creates a datadog component from a component file and returns the new ID.
+func (dd *Datadog) Create(ctx context.Context, component *Component) (string, error) {
+	creator, err := dd.creator(component.Type)
 	if err != nil {
-		return errors.Wrapf(err, "unable to get a screenboard %d", id)
+		return "", err
 	}
 
-	return dd.marshalAndWrite(&Component{
-		Type:        types.ComponentScreenboard,
-		ScreenBoard: sb,
-	}, to)
-}
+	id, err := creator.Create(ctx, component.Payload)
+	if err != nil {
+		return id, errors.Wrapf(err, "unable to create %s", component.Type)
+	}
 
-// Update will restore a datadog component from bytes.
-func (dd *Datadog) Update(component *Component) error {
-	switch component.Type {
-	case types.ComponentDashboard:
-		return dd.updateDashboard(component.Dashboard)
-	case types.ComponentMonitor:
-		return dd.updateMonitor(component.Monitor)
-	case types.ComponentDowntime:
-		return dd.updateDowntime(component.Downtime)
-	case types.ComponentScreenboard:
-		return dd.updateScreenBoard(component.ScreenBoard)
-	}
-
-	return ErrInvalidComponentTypeID
+	return id, nil
 }
 
-func (dd *Datadog) updateDashboard(dashboard json.RawMessage) error {
-	if err := dd.updateDashboardFn(dashboard); err != nil {
-		return errors.Wrap(err, "unable to update datadog dashboard")
+// Delete deletes a datadog component by ID.
+func (dd *Datadog) Delete(ctx context.Context, component types.Component, id string) error {
+	ct, ok := dd.Registry.Get(component)
+	if !ok {
+		return ErrInvalidComponentTypeID
 	}
 
-	return nil
-}
+	deleter, ok := ct.(types.Deleter)
+	if !ok {
+		return ErrDeleteNotSupported
+	}
 
-func (dd *Datadog) updateMonitor(monitor *client.MonitorWithDependencies) error {
-	if err := dd.updateMonitorFn(monitor); err != nil {
-		return errors.Wrap(err, "unable to update a monitor")
+	if err := deleter.Delete(ctx, id); err != nil {
+		return errors.Wrapf(err, "unable to delete %s %s", component, id)
 	}
 
 	return nil
 }
 
-func (dd *Datadog) updateAlert(alert json.RawMessage) error {
-	if err := dd.updateAlertFn(alert); err != nil {
-		return errors.Wrap(err, "unable to update an alert")
+// Validate validates a component from a component file in datadog and returns the errors found,
+// empty if the component is valid.
+func (dd *Datadog) Validate(ctx context.Context, component *Component) ([]string, error) {
+	ct, ok := dd.Registry.Get(component.Type)
+	if !ok {
+		return nil, ErrInvalidComponentTypeID
 	}
 
-	return nil
-}
+	validator, ok := ct.(types.Validator)
+	if !ok {
+		return nil, ErrValidateNotSupported
+	}
 
-func (dd *Datadog) updateDowntime(downtime json.RawMessage) error {
-	if err := dd.updateDowntimeFn(downtime); err != nil {
-		return errors.Wrap(err, "unable to update a downtime")
+	errs, err := validator.Validate(ctx, component.Payload)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to validate %s", component.Type)
 	}
 
-	return nil
+	return errs, nil
 }
 
-func (dd *Datadog) updateScreenBoard(sb json.RawMessage) error {
-	if err := dd.updateScreenBoardFn(sb); err != nil {
-		return errors.Wrap(err, "unable to update a screenboard")
+func (dd *Datadog) creator(component types.Component) (types.Creator, error) {
+	ct, ok := dd.Registry.Get(component)
+	if !ok {
+		return nil, ErrInvalidComponentTypeID
 	}
 
-	return nil
+	creator, ok := ct.(types.Creator)
+	if !ok {
+		return nil, ErrCreateNotSupported
+	}
+
+	return creator, nil
 }
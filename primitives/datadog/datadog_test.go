@@ -2,22 +2,30 @@ package datadog
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
 )
 
 func TestDatadogWrite(t *testing.T) {
-	dd := &Datadog{
-		getDashboardFn: func(id int) (json.RawMessage, error) {
+	dd, err := New("123", "456", nil, WithComponentType(NewComponentType(types.ComponentDashboard, "dashboards",
+		func(ctx context.Context, id string) (json.RawMessage, error) {
 			return []byte(`{"id":2,"title":"test title", "description":"test description"}`), nil
-		},
+		}, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
 	}
 
 	buf := new(bytes.Buffer)
 
-	err := dd.Write(types.ComponentDashboard, 2, buf)
+	err = dd.Write(context.Background(), types.ComponentDashboard, "2", buf)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,25 +59,325 @@ func TestDatadogWrite(t *testing.T) {
 	if expectedResponse.Dashboard.Description != "test description" {
 		t.Fatalf("expected dashboard description \"test description\". Got %s", expectedResponse.Dashboard.Description)
 	}
+
+	// integer components must reject non integer IDs
+	dd, err = New("123", "456", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dd.Write(context.Background(), types.ComponentDashboard, "abc-def-ghi", buf)
+	if err == nil {
+		t.Fatal("expect an error writing a dashboard with a string id")
+	}
+
+	err = dd.Write(context.Background(), types.Component("unknown"), "1", buf)
+	if err != ErrInvalidComponentTypeID {
+		t.Fatalf("expect error %s. Got %v", ErrInvalidComponentTypeID, err)
+	}
+}
+
+func TestDatadogCreate(t *testing.T) {
+	var created json.RawMessage
+	dd, err := New("123", "456", nil,
+		WithComponentType(NewComponentType(types.ComponentDashboard, "dashboards", nil, nil, nil,
+			WithCreateFn(idAt("dash", "id"), func(ctx context.Context, payload json.RawMessage) (string, error) {
+				created = payload
+				return "10", nil
+			}))),
+		WithComponentType(NewComponentType(types.ComponentMonitor, "monitors", nil, nil, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dash := &Component{Type: types.ComponentDashboard, Payload: []byte(`{"dash":{"title":"test"}}`)}
+	id, err := dd.PayloadID(dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "" {
+		t.Fatalf("expect empty id. Got %s", id)
+	}
+
+	id, err = dd.Create(context.Background(), dash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "10" || string(created) != string(dash.Payload) {
+		t.Fatalf("expect dashboard 10 created from payload. Got %s %s", id, created)
+	}
+
+	_, err = dd.Create(context.Background(), &Component{Type: types.ComponentMonitor, Payload: []byte(`{}`)})
+	if err == nil || errors.Cause(err) != ErrCreateNotSupported {
+		t.Fatalf("expect error %s. Got %v", ErrCreateNotSupported, err)
+	}
+}
+
+func TestIDAt(t *testing.T) {
+	for _, tc := range []struct {
+		payload  string
+		path     []string
+		expected string
+	}{
+		{`{"dash":{"id":12345678901}}`, []string{"dash", "id"}, "12345678901"},
+		{`{"public_id":"abc-def-ghi"}`, []string{"public_id"}, "abc-def-ghi"},
+		{`{"id":null}`, []string{"id"}, ""},
+		{`{"title":"foo"}`, []string{"monitor", "id"}, ""},
+	} {
+		id, err := idAt(tc.path...)([]byte(tc.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != tc.expected {
+			t.Fatalf("expect id %q from %s. Got %q", tc.expected, tc.payload, id)
+		}
+	}
+
+	if _, err := idAt("id")([]byte(`{"id":{}}`)); err == nil {
+		t.Fatal("expect error for an invalid id")
+	}
 }
 
 func TestDatadogUpdate(t *testing.T) {
 	dash := []byte(`{"id":2,"title":"test title","description":"test description"}`)
-	dd := &Datadog{
-		updateDashboardFn: func(dashboard json.RawMessage) error {
+	dd, err := New("123", "456", nil, WithComponentType(NewComponentType(types.ComponentDashboard, "dashboards", nil, nil,
+		func(ctx context.Context, dashboard json.RawMessage) error {
 			if cmp := bytes.Compare(dashboard, dash); cmp != 0 {
 				t.Fatalf("expecte %s. Got %s", string(dash), string(dashboard))
 			}
 			return nil
+		})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dd.Update(context.Background(), &Component{
+		Type:    types.ComponentDashboard,
+		Payload: dash,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComponentFileFormat(t *testing.T) {
+	monitor, err := marshalPayload(&client.MonitorWithDependencies{
+		Monitor: []byte(`{"id":1,"query":"avg(last_5m):foo > 1 && bar < 2"}`),
+		Alert:   []byte(`{"id":1}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	dd := &Datadog{}
+	err = dd.marshalAndWrite(&Component{
+		Type:    types.ComponentMonitor,
+		Payload: monitor,
+	}, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "type": "monitor",
+  "monitor": {
+    "monitor": {
+      "id": 1,
+      "query": "avg(last_5m):foo > 1 && bar < 2"
+    },
+    "alert": {
+      "id": 1
+    },
+    "downtime": null
+  }
+}
+`
+	if buf.String() != expected {
+		t.Fatalf("expect component file %s. Got %s", expected, buf.String())
+	}
+
+	component := &Component{}
+	err = json.Unmarshal(buf.Bytes(), component)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if component.Type != types.ComponentMonitor {
+		t.Fatalf("expect type %s. Got %s", types.ComponentMonitor, component.Type)
+	}
+
+	m := &client.MonitorWithDependencies{}
+	err = json.Unmarshal(component.Payload, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.Monitor) == 0 || len(m.Alert) == 0 {
+		t.Fatalf("expect monitor and alert in payload. Got %s", component.Payload)
+	}
+}
+
+func TestDatadogLogPipeline(t *testing.T) {
+	pipeline := &client.LogPipeline{
+		Pipeline: []byte(`{"id":"aaa","name":"nginx"}`),
+		Position: 2,
+	}
+
+	var restored *client.LogPipeline
+	lp := &logPipelineComponentType{
+		getPipelineFn: func(ctx context.Context, id string) (*client.LogPipeline, error) {
+			return pipeline, nil
+		},
+		updatePipelineFn: func(ctx context.Context, p *client.LogPipeline) error {
+			restored = p
+			return nil
+		},
+	}
+
+	dd, err := New("123", "456", nil, WithComponentType(lp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = dd.Write(context.Background(), types.ComponentLogPipeline, "aaa", buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	component := &Component{}
+	err = json.Unmarshal(buf.Bytes(), component)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dd.Update(context.Background(), component)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restored == nil || restored.Position != 2 {
+		t.Fatalf("expect restored log pipeline with position 2. Got %+v", restored)
+	}
+}
+
+func TestLogPipelineModifiedIDs(t *testing.T) {
+	pipelines := `[{"id":"aaa","name":"nginx"},{"id":"bbb","name":"api"}]`
+	order := []string{"aaa", "bbb"}
+
+	lp := &logPipelineComponentType{
+		getPipelinesFn: func(ctx context.Context) (client.LogPipelinesResponse, error) {
+			return []byte(pipelines), nil
 		},
+		getOrderFn: func(ctx context.Context) ([]string, error) {
+			return order, nil
+		},
+		changes: &fingerprints{},
+	}
+
+	// the first call only remembers the state
+	ids, err := lp.ModifiedIDs(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	err := dd.Update(&Component{
-		Type:      types.ComponentDashboard,
-		Dashboard: dash,
+	if len(ids) != 0 {
+		t.Fatalf("expect no changes on the first call. Got %v", ids)
+	}
+
+	pipelines = `[{"id":"aaa","name":"nginx"},{"id":"bbb","name":"api v2"}]`
+	ids, err = lp.ModifiedIDs(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != "bbb" {
+		t.Fatalf("expect changed pipeline bbb. Got %v", ids)
+	}
+
+	order = []string{"bbb", "aaa"}
+	ids, err = lp.ModifiedIDs(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 2 {
+		t.Fatalf("expect both pipelines to change after reorder. Got %v", ids)
+	}
+}
+
+func TestModifiedByFingerprints(t *testing.T) {
+	current := map[string]string{"1": "a", "2": "b"}
+	modifiedFn := modifiedByFingerprints(func(ctx context.Context) (map[string]string, error) {
+		return current, nil
 	})
 
+	// the first call only remembers the state
+	ids, err := modifiedFn(context.Background(), time.Second)
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	if len(ids) != 0 {
+		t.Fatalf("expect no changes on the first call. Got %v", ids)
+	}
+
+	current = map[string]string{"1": "a", "2": "c", "3": "d"}
+	ids, err = modifiedFn(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(ids)
+	if len(ids) != 2 || ids[0] != "2" || ids[1] != "3" {
+		t.Fatalf("expect changed ids [2 3]. Got %v", ids)
+	}
+
+	ids, err = modifiedFn(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 0 {
+		t.Fatalf("expect no changes. Got %v", ids)
+	}
+}
+
+func TestPayloadModifier(t *testing.T) {
+	for _, tc := range []struct {
+		payload  string
+		expected string
+	}{
+		{`{"monitor":{"id":1,"creator":{"handle":"jane@example.com","email":"jane@example.com"}},"alert":null,"downtime":null}`, ""},
+		{`{"dash":{"id":1,"created_by":{"email":"john@example.com"}}}`, ""},
+		{`{"id":"abc","author_handle":"ann@example.com","creator":{"handle":"bob@example.com"}}`, ""},
+		{`{"public_id":"abc","modified_by":{"handle":"eve@example.com"},"created_by":{"handle":"bob@example.com"}}`, "eve@example.com"},
+		{`{"public_id":"abc","modified_by":{"email":"john@example.com"}}`, "john@example.com"},
+		{`{"public_id":"abc","modified_by":"ann@example.com"}`, "ann@example.com"},
+		{`{"id":1}`, ""},
+		{`[]`, ""},
+	} {
+		if modifier := PayloadModifier([]byte(tc.payload)); modifier != tc.expected {
+			t.Fatalf("expect modifier %q of %s. Got %q", tc.expected, tc.payload, modifier)
+		}
+	}
+}
+
+func TestModifierComponentTypes(t *testing.T) {
+	dd, err := New("123", "456", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ct := range dd.Registry.ComponentTypes() {
+		_, ok := ct.(types.Modifier)
+		if expect := ct.Type() == types.ComponentSynthetics; ok != expect {
+			t.Fatalf("expect %s to implement a modifier %t. Got %t", ct.Type(), expect, ok)
+		}
+	}
 }
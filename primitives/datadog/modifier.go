@@ -0,0 +1,36 @@
+package datadog
+
+import (
+	"encoding/json"
+)
+
+// PayloadModifier returns a handle of the user who changed a component last, read from the modified_by field of
+// a payload. The field is either a handle or a user object with a handle or an email. The creator of a component
+// is not the user who changed it, so the creator fields are not used. Empty is returned if the field is not set.
+func PayloadModifier(payload json.RawMessage) string {
+	var fields struct {
+		ModifiedBy json.RawMessage `json:"modified_by"`
+	}
+	if err := json.Unmarshal(payload, &fields); err != nil || len(fields.ModifiedBy) == 0 {
+		return ""
+	}
+
+	var handle string
+	if err := json.Unmarshal(fields.ModifiedBy, &handle); err == nil {
+		return handle
+	}
+
+	var user struct {
+		Handle string `json:"handle"`
+		Email  string `json:"email"`
+	}
+	if err := json.Unmarshal(fields.ModifiedBy, &user); err != nil {
+		return ""
+	}
+
+	if user.Handle != "" {
+		return user.Handle
+	}
+
+	return user.Email
+}
@@ -1,9 +1,6 @@
 package datadog
 
 import (
-	"encoding/json"
-
-	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
 
 	"github.com/pkg/errors"
@@ -13,78 +10,41 @@ var (
 	// ErrNilFunction is returned if the passed parameter is nil.
 	ErrNilFunction = errors.New("nil argument function")
 
-	// ErrInvalidFunctionType is returned if the function does not have the correct signature.
-	ErrInvalidFunctionType = errors.New("invalid function type")
-)
+	// ErrNilRegistry is returned if the passed registry is nil.
+	ErrNilRegistry = errors.New("nil registry")
 
-// Option is a functional parameter interface for datadog constructor
-type Option func(datadog *Datadog) error
+	// ErrCreateNotSupported is returned if a component type could not be created from a component file.
+	ErrCreateNotSupported = errors.New("component type does not support create")
 
-// WithAccessorGetFn is a functional parameter to set the get functions.
-func WithAccessorGetFn(component types.Component, fn func(int) (json.RawMessage, error)) Option {
-	return func(dd *Datadog) error {
-		if fn == nil {
-			return ErrNilFunction
-		}
+	// ErrDeleteNotSupported is returned if a component type could not be deleted.
+	ErrDeleteNotSupported = errors.New("component type does not support delete")
 
-		switch component {
-		case types.ComponentDashboard:
-			dd.getDashboardFn = fn
-		case types.ComponentDowntime:
-			dd.getDowntimeFn = fn
-		case types.ComponentScreenboard:
-			dd.getScreenBoardFn = fn
-		default:
-			return ErrInvalidFunctionType
-		}
+	// ErrSelectNotSupported is returned if a component type could not be selected by selectors.
+	ErrSelectNotSupported = errors.New("component type does not support selectors")
 
-		return nil
-	}
-}
-
-// WithMonitorGetFn is a functional parameter to set the dashboard get function.
-func WithMonitorGetFn(fn func(int, bool) (*client.MonitorWithDependencies, error)) Option {
-	return func(dd *Datadog) error {
-		if fn == nil {
-			return ErrNilFunction
-		}
-
-		dd.getMonitorFullFn = fn
+	// ErrValidateNotSupported is returned if a component type could not be validated by datadog.
+	ErrValidateNotSupported = errors.New("component type does not support validation")
+)
 
-		return nil
-	}
-}
+// Option is a functional parameter interface for datadog constructor
+type Option func(datadog *Datadog) error
 
-// WithAccessorUpdateFn is a functional parameter to set the update functions.
-func WithAccessorUpdateFn(component types.Component, fn func(json.RawMessage) error) Option {
+// WithRegistry is a functional parameter to use a given component type registry instead of the default one.
+func WithRegistry(registry *types.Registry) Option {
 	return func(dd *Datadog) error {
-		if fn == nil {
-			return ErrNilFunction
-		}
-
-		switch component {
-		case types.ComponentDashboard:
-			dd.updateDashboardFn = fn
-		case types.ComponentDowntime:
-			dd.updateDowntimeFn = fn
-		case types.ComponentScreenboard:
-			dd.updateScreenBoardFn = fn
-		default:
-			return ErrInvalidFunctionType
+		if registry == nil {
+			return ErrNilRegistry
 		}
 
+		dd.Registry = registry
 		return nil
 	}
 }
 
-// WithMonitorSetFn is a functional parameter that sets the update monitor function.
-func WithMonitorSetFn(fn func(*client.MonitorWithDependencies) error) Option {
+// WithComponentType is a functional parameter to register an additional component type or
+// replace a default one.
+func WithComponentType(ct types.ComponentType) Option {
 	return func(dd *Datadog) error {
-		if fn == nil {
-			return ErrNilFunction
-		}
-
-		dd.updateMonitorFn = fn
-		return nil
+		return dd.Registry.Register(ct)
 	}
 }
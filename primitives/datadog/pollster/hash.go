@@ -0,0 +1,210 @@
+package pollster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// WithContentHashes detects the changes by comparing the content hash of every component listed in user config
+// with the hash seen by the previous poll, instead of requesting the components modified within the polling
+// interval. The hashes are persisted to a state file, so the changes made while watchdog was stopped are detected
+// by the first poll after the start. It does not depend on the clock of datadog, but requests every listed
+// component once per hash interval, see WithHashInterval.
+func WithContentHashes(statePath string) SimplePollsterOption {
+	return func(s *simplePoller) {
+		s.hashes = &hashState{path: statePath}
+	}
+}
+
+// WithHashInterval sets an interval to compare the content hashes. Every listed component is requested once
+// per interval, so the interval should be longer than the polling one. Zero compares the hashes on every poll.
+func WithHashInterval(interval time.Duration) SimplePollsterOption {
+	return func(s *simplePoller) {
+		s.hashInterval = interval
+	}
+}
+
+// NewHashPollster returns an instance of a polling scheduler which detects the changes by content hashes.
+func NewHashPollster(registry *types.Registry, interval time.Duration, cfg *config.Config, statePath string,
+	componentFn func(component types.Component, team, project, id string) bool, opts ...SimplePollsterOption) Pollster {
+	return NewSimplePollster(registry, interval, cfg, componentFn, append(opts, WithContentHashes(statePath))...)
+}
+
+// hashState keeps the content hash of every component by type and ID.
+type hashState struct {
+	path   string
+	hashes map[types.Component]map[string]string
+}
+
+// load reads the hashes from the state file, a missing file is an empty state.
+func (h *hashState) load() error {
+	h.hashes = make(map[types.Component]map[string]string)
+
+	body, err := ioutil.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "unable to read state file %s", h.path)
+	}
+
+	if err := json.Unmarshal(body, &h.hashes); err != nil {
+		return errors.Wrapf(err, "unable to decode state file %s", h.path)
+	}
+
+	return nil
+}
+
+// save writes the hashes to a temporary file and renames it, so a state file is never partially written.
+func (h *hashState) save() error {
+	body, err := json.Marshal(h.hashes)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode state")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return errors.Wrapf(err, "unable to create a directory of state file %s", h.path)
+	}
+
+	tmp := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, body, 0644); err != nil {
+		return errors.Wrapf(err, "unable to write state file %s", tmp)
+	}
+
+	return errors.Wrapf(os.Rename(tmp, h.path), "unable to replace state file %s", h.path)
+}
+
+// pollHashes requests every component listed in user config and sends the components whose content hash differs
+// from the stored one. A component seen for the first time is stored without being sent, the components of user
+// config are compared against git when watchdog starts. The hashes of a type are saved once its changes are sent,
+// so a change which was not sent is detected again by the next poll.
+func (s *simplePoller) pollHashes(ctx context.Context, result chan *Response) {
+	managed := s.managedComponents()
+
+	// the types not listed in user config anymore are forgotten.
+	for component := range s.hashes.hashes {
+		if len(managed[component]) == 0 {
+			delete(s.hashes.hashes, component)
+		}
+	}
+
+	for _, ct := range s.registry.ComponentTypes() {
+		component := ct.Type()
+		if len(managed[component]) == 0 {
+			continue
+		}
+
+		hashes := make(map[string]string)
+
+		var changed []string
+		for _, id := range managed[component] {
+			// the hash is kept if the component could not be requested, e.g. deleted components are reported
+			// by the deleted check and compared again once they are available.
+			known, ok := s.hashes.hashes[component][id]
+			if ok {
+				hashes[id] = known
+			}
+
+			if !s.waitRateLimit(ctx) {
+				return
+			}
+
+			payload, err := ct.Get(ctx, id)
+			if err != nil {
+				if !client.IsNotFound(err) {
+					logrus.Errorf("unable to poll %s %s: %s", component, id, err)
+				}
+				continue
+			}
+
+			hash, err := contentHash(ct, payload)
+			if err != nil {
+				logrus.Errorf("unable to hash %s %s: %s", component, id, err)
+				continue
+			}
+			hashes[id] = hash
+
+			if ok && known != hash {
+				changed = append(changed, id)
+			}
+		}
+
+		if !s.sendFilteredResponse(ctx, component, changed, result) {
+			return
+		}
+
+		s.hashes.hashes[component] = hashes
+		if err := s.hashes.save(); err != nil {
+			logrus.Errorf("unable to save polling state: %s", err)
+		}
+	}
+}
+
+// managedComponents returns the sorted IDs of the components listed in user config by type.
+func (s *simplePoller) managedComponents() map[types.Component][]string {
+	seen := make(map[types.Component]map[string]bool)
+	managed := make(map[types.Component][]string)
+	for _, userConfigFile := range s.cfg.UserConfigFiles() {
+		for component, ids := range userConfigFile.Components() {
+			if seen[component] == nil {
+				seen[component] = make(map[string]bool)
+			}
+
+			for _, id := range ids {
+				if !seen[component][id] {
+					seen[component][id] = true
+					managed[component] = append(managed[component], id)
+				}
+			}
+		}
+	}
+
+	for _, ids := range managed {
+		sort.Strings(ids)
+	}
+
+	return managed
+}
+
+// contentHash returns a hash of a payload which does not depend on the order of the JSON object keys. The fields
+// datadog changes on its own are not included, e.g. the next occurrence of a recurring downtime.
+func contentHash(ct types.ComponentType, payload json.RawMessage) (string, error) {
+	if normalizer, ok := ct.(types.Normalizer); ok {
+		var err error
+		payload, err = normalizer.Normalize(payload)
+		if err != nil {
+			return "", errors.Wrap(err, "unable to normalize payload")
+		}
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return "", errors.Wrap(err, "unable to decode payload")
+	}
+
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to encode payload")
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,207 @@
+package pollster
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
+	"github.com/coinbase/watchdog/primitives/datadog/client"
+	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"github.com/pkg/errors"
+)
+
+func TestPollHashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dashboards := map[string]string{
+		"1": `{"dash":{"id":1,"title":"a"}}`,
+		"2": `{"dash":{"id":2,"title":"b"}}`,
+	}
+
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentDashboard, "dashboards", func(ctx context.Context, id string) (json.RawMessage, error) {
+			dashboard, ok := dashboards[id]
+			if !ok {
+				return nil, errors.Wrap(&client.NotFoundError{URL: "dash/" + id, Method: "GET"}, "unable to get dashboard")
+			}
+			return []byte(dashboard), nil
+		}, nil, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		UserConfig: &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{
+			config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar"}, map[types.Component][]string{types.ComponentDashboard: {"1", "2"}}),
+		}},
+	}
+
+	statePath := filepath.Join(dir, "state", "hashes.json")
+	poll := func() []*Response {
+		// a new poller loads the state saved by the previous one, like after a restart.
+		p := NewHashPollster(registry, 0, cfg, statePath, nil).(*simplePoller)
+		if err := p.hashes.load(); err != nil {
+			t.Fatal(err)
+		}
+
+		result := make(chan *Response, 10)
+		p.poll(context.Background(), result)
+		close(result)
+
+		var responses []*Response
+		for response := range result {
+			responses = append(responses, response)
+		}
+		return responses
+	}
+
+	// the components seen for the first time are not sent
+	if responses := poll(); len(responses) != 0 {
+		t.Fatalf("expect no changes on the first poll. Got %d", len(responses))
+	}
+
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("expect the state saved. Got %s", err)
+	}
+
+	// the order of the keys does not change the hash
+	dashboards["1"] = `{"dash":{"id":1,"title":"changed"}}`
+	dashboards["2"] = `{"dash":{"title":"b","id":2}}`
+
+	responses := poll()
+	if len(responses) != 2 {
+		t.Fatalf("expect a change sent for every config file. Got %d", len(responses))
+	}
+
+	for _, response := range responses {
+		if response.Component != types.ComponentDashboard || response.ID != "1" {
+			t.Fatalf("expect dashboard 1 changed. Got %s %s", response.Component, response.ID)
+		}
+	}
+
+	// a deleted component keeps its hash, so it is compared again once it is available
+	delete(dashboards, "2")
+	if responses := poll(); len(responses) != 0 {
+		t.Fatalf("expect no changes. Got %d", len(responses))
+	}
+
+	dashboards["2"] = `{"dash":{"id":2,"title":"restored"}}`
+	if responses := poll(); len(responses) != 2 || responses[0].ID != "2" {
+		t.Fatalf("expect dashboard 2 changed. Got %d responses", len(responses))
+	}
+
+	// a change which was not handed off is not saved, so the next poll sends it
+	dashboards["1"] = `{"dash":{"id":1,"title":"not sent"}}`
+	p := NewHashPollster(registry, 0, cfg, statePath, nil).(*simplePoller)
+	if err := p.hashes.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.poll(ctx, make(chan *Response))
+
+	if responses := poll(); len(responses) != 2 || responses[0].ID != "1" {
+		t.Fatalf("expect dashboard 1 changed. Got %d responses", len(responses))
+	}
+
+	// the hashes are compared once per hash interval
+	dashboards["1"] = `{"dash":{"id":1,"title":"changed again"}}`
+	p = NewHashPollster(registry, 0, cfg, statePath, nil, WithHashInterval(time.Hour)).(*simplePoller)
+	if err := p.hashes.load(); err != nil {
+		t.Fatal(err)
+	}
+	p.lastHashPoll = time.Now()
+
+	result := make(chan *Response, 10)
+	p.poll(context.Background(), result)
+	if len(result) != 0 {
+		t.Fatalf("expect no poll within the hash interval. Got %d responses", len(result))
+	}
+
+	// the components are not requested while the rate limit is exhausted
+	p = NewHashPollster(registry, 0, cfg, statePath, nil, WithRateLimits(func() map[string]client.RateLimit {
+		return map[string]client.RateLimit{"dash": {Limit: 100, Remaining: 0, ResetAt: time.Now().Add(time.Hour)}}
+	})).(*simplePoller)
+	if err := p.hashes.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	p.poll(ctx, result)
+	if len(result) != 0 {
+		t.Fatalf("expect no poll while the rate limit is exhausted. Got %d responses", len(result))
+	}
+
+	if responses := poll(); len(responses) != 2 || responses[0].ID != "1" {
+		t.Fatalf("expect dashboard 1 changed. Got %d responses", len(responses))
+	}
+
+	// a broken state file is reported, polling starts with an empty state
+	if err := ioutil.WriteFile(statePath, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p = NewHashPollster(registry, 0, cfg, statePath, nil).(*simplePoller)
+	if err := p.hashes.load(); err == nil {
+		t.Fatal("expect an error loading a broken state file")
+	}
+}
+
+func TestPollHashesRecurringDowntime(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	monitor := `{"monitor":{"id":1},"alert":null,"downtime":{"id":10,"message":"weekly","start":1000,"end":2000,"recurrence":{"type":"weeks","period":1}}}`
+
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", func(ctx context.Context, id string) (json.RawMessage, error) {
+			return []byte(monitor), nil
+		}, nil, nil, datadog.WithNormalizeFn(client.NormalizeMonitorWithDependencies)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		UserConfig: &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{
+			config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar"}, map[types.Component][]string{types.ComponentMonitor: {"1"}}),
+		}},
+	}
+
+	p := NewHashPollster(registry, 0, cfg, filepath.Join(dir, "hashes.json"), nil).(*simplePoller)
+	if err := p.hashes.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	result := make(chan *Response, 10)
+	p.poll(context.Background(), result)
+
+	// datadog moved the recurring downtime to its next occurrence
+	monitor = `{"monitor":{"id":1},"alert":null,"downtime":{"id":10,"message":"weekly","start":5000,"end":6000,"active":true,"recurrence":{"type":"weeks","period":1}}}`
+	p.poll(context.Background(), result)
+	if len(result) != 0 {
+		t.Fatalf("expect no changes for the next occurrence of a downtime. Got %d responses", len(result))
+	}
+
+	monitor = `{"monitor":{"id":1},"alert":null,"downtime":{"id":10,"message":"changed","start":5000,"end":6000,"active":true,"recurrence":{"type":"weeks","period":1}}}`
+	p.poll(context.Background(), result)
+	if len(result) != 2 {
+		t.Fatalf("expect a changed downtime sent for every config file. Got %d responses", len(result))
+	}
+}
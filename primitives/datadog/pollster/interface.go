@@ -12,7 +12,13 @@ type Response struct {
 	UserConfigFile *config.UserConfigFile
 
 	Component types.Component
-	ID        int
+	ID        string
+
+	// Deleted is set if the component does not exist in datadog anymore.
+	Deleted bool
+
+	// ModifiedBy is a handle of the user who changed the component, empty if it is unknown.
+	ModifiedBy string
 }
 
 // Pollster is the interface for datadog metrics polling.
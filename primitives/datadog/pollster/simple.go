@@ -8,41 +8,82 @@ import (
 	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
 
-	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// lowRateLimitRatio is a share of remaining requests of an endpoint rate limit, below which
+// the pollster waits for the rate limit to reset before polling.
+const lowRateLimitRatio = 0.1
+
+// SimplePollsterOption is a functional option for the simple polling scheduler.
+type SimplePollsterOption func(s *simplePoller)
+
+// WithRateLimits sets a function which returns the datadog rate limit state per endpoint.
+// If any endpoint is close to its limit, polling is delayed until the limit resets.
+func WithRateLimits(fn func() map[string]client.RateLimit) SimplePollsterOption {
+	return func(s *simplePoller) {
+		s.rateLimits = fn
+	}
+}
+
+// WithDeletedCheck enables a periodic check if the components listed in user config still exist in datadog.
+// Every listed component is requested once per interval, so the interval should be much longer than the polling one.
+func WithDeletedCheck(interval time.Duration) SimplePollsterOption {
+	return func(s *simplePoller) {
+		s.deletedCheckInterval = interval
+	}
+}
+
+// WithSelectorRefresh enables a periodic refresh of user config selectors. The components newly matched by
+// selectors are sent to be adopted, even if they have no component files yet.
+func WithSelectorRefresh(interval time.Duration) SimplePollsterOption {
+	return func(s *simplePoller) {
+		s.selectorRefreshInterval = interval
+	}
+}
+
 // NewSimplePollster returns an instance of a simple polling scheduler.
-func NewSimplePollster(client *client.Client, interval time.Duration, cfg *config.Config, componentFn func(component types.Component, team, project string, id int) bool) Pollster {
-	return &simplePoller{
+func NewSimplePollster(registry *types.Registry, interval time.Duration, cfg *config.Config,
+	componentFn func(component types.Component, team, project, id string) bool, opts ...SimplePollsterOption) Pollster {
+	s := &simplePoller{
 		interval:         interval,
 		cfg:              cfg,
-		ca:               newComponentAccessors(client),
+		registry:         registry,
 		componentAllowed: componentFn,
 	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	return s
 }
 
 type simplePoller struct {
-	ca       *componentAccessors
+	registry *types.Registry
 	interval time.Duration
 	cfg      *config.Config
 
-	componentAllowed func(component types.Component, team, project string, id int) bool
-}
+	rateLimits func() map[string]client.RateLimit
 
-func newComponentAccessors(c *client.Client) *componentAccessors {
-	return &componentAccessors{
-		getDashboards:   c.GetDashboards,
-		getMonitors:     c.GetMonitors,
-		getScreenBoards: c.GetScreenboards,
-	}
-}
+	// lastPoll is a time of the last poll, changes are requested since that time,
+	// so nothing is missed when polling is delayed.
+	lastPoll time.Time
+
+	deletedCheckInterval time.Duration
+	lastDeletedCheck     time.Time
+
+	selectorRefreshInterval time.Duration
+	lastSelectorRefresh     time.Time
 
-// ComponentAccessors is a structure contains functions to query datadog to get a list of components.
-type componentAccessors struct {
-	getDashboards   func() (client.DashboardsResponse, error)
-	getMonitors     func() (client.MonitorsResponse, error)
-	getScreenBoards func() (client.ScreenBoardsResponse, error)
+	// hashes are set if the changes are detected by content hashes, see WithContentHashes.
+	hashes       *hashState
+	hashInterval time.Duration
+	lastHashPoll time.Time
+
+	componentAllowed func(component types.Component, team, project, id string) bool
 }
 
 // Do in implementation of pollster interface.
@@ -56,78 +97,267 @@ func (s *simplePoller) start(ctx context.Context, result chan *Response) {
 	ticker := time.Tick(s.interval)
 
 	logrus.Infof("Start polling with interval %s", s.interval)
+	s.lastPoll = time.Now()
+	s.lastDeletedCheck = time.Now()
+	s.lastSelectorRefresh = time.Now()
+
+	// catch up on the changes made since the hashes were saved.
+	if s.hashes != nil {
+		if err := s.hashes.load(); err != nil {
+			logrus.Errorf("unable to load polling state, the changes made while watchdog was stopped are not detected: %s", err)
+		}
+
+		logrus.Infof("Start polling by content hashes with interval %s, state file %s", s.hashInterval, s.hashes.path)
+		s.poll(ctx, result)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			logrus.Warn("Shutting down pollster")
 			return
 		case <-ticker:
+			if !s.waitRateLimit(ctx) {
+				logrus.Warn("Shutting down pollster")
+				return
+			}
+
 			logrus.Debug("Start polling datadog for changes")
-			s.poll(result)
+			s.poll(ctx, result)
+
+			if s.deletedCheckInterval > 0 && time.Since(s.lastDeletedCheck) >= s.deletedCheckInterval {
+				logrus.Debug("Start checking datadog for deleted components")
+				s.lastDeletedCheck = time.Now()
+				s.checkDeleted(ctx, result)
+			}
+
+			if s.selectorRefreshInterval > 0 && time.Since(s.lastSelectorRefresh) >= s.selectorRefreshInterval {
+				logrus.Debug("Start refreshing user config selectors")
+				s.lastSelectorRefresh = time.Now()
+				s.refreshSelectors(ctx, result)
+			}
 		}
 	}
 }
 
-func (s *simplePoller) poll(result chan *Response) {
-	for component, pollFn := range map[types.Component]func() ([]int, error){
-		types.ComponentDashboard:   s.pollDashboards,
-		types.ComponentMonitor:     s.pollMonitors,
-		types.ComponentScreenboard: s.pollScreenBoards,
-	} {
-		ids, err := pollFn()
-		if err != nil {
-			logrus.Error(err)
+// rateLimitWait returns a duration until the rate limits, which are close to be exhausted, reset.
+func (s *simplePoller) rateLimitWait(now time.Time) time.Duration {
+	if s.rateLimits == nil {
+		return 0
+	}
+
+	var wait time.Duration
+	for _, rl := range s.rateLimits() {
+		if float64(rl.Remaining) > float64(rl.Limit)*lowRateLimitRatio {
 			continue
 		}
 
-		s.sendFilteredResponse(component, ids, result)
+		if w := rl.Wait(now); w > wait {
+			wait = w
+		}
 	}
+
+	return wait
 }
 
-func (s *simplePoller) pollDashboards() ([]int, error) {
-	dashboards, err := s.ca.getDashboards()
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to get dashboards")
+// waitRateLimit delays polling while the rate limits are close to be exhausted, it returns false if the context is done.
+func (s *simplePoller) waitRateLimit(ctx context.Context) bool {
+	wait := s.rateLimitWait(time.Now())
+	if wait <= 0 {
+		return true
 	}
 
-	return dashboards.GetModifiedIDsWithin(s.interval, nil)
+	logrus.Warnf("Datadog rate limit is almost exhausted, delaying polling for %s", wait)
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
 }
 
-func (s *simplePoller) pollMonitors() ([]int, error) {
-	monitors, err := s.ca.getMonitors()
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to get monitors")
+func (s *simplePoller) poll(ctx context.Context, result chan *Response) {
+	if s.hashes != nil {
+		if time.Since(s.lastHashPoll) < s.hashInterval {
+			return
+		}
+
+		s.lastHashPoll = time.Now()
+		s.pollHashes(ctx, result)
+		return
+	}
+
+	// poll for changes since the last poll, at least for the polling interval.
+	now := time.Now()
+	window := now.Sub(s.lastPoll)
+	if window < s.interval {
+		window = s.interval
+	}
+	s.lastPoll = now
+
+	for _, ct := range s.registry.ComponentTypes() {
+		ids, err := ct.ModifiedIDs(ctx, window)
+		if err != nil {
+			logrus.Errorf("unable to poll %s: %s", ct.Type(), err)
+			continue
+		}
+
+		if !s.sendFilteredResponse(ctx, ct.Type(), ids, result) {
+			return
+		}
+	}
+}
+
+// checkDeleted requests every component listed in user config and sends the components which do not exist
+// in datadog anymore. A component listed in several user config files is sent once per check.
+func (s *simplePoller) checkDeleted(ctx context.Context, result chan *Response) {
+	checked := make(map[types.Component]map[string]bool)
+	for _, userConfigFile := range s.cfg.UserConfigFiles() {
+		for component, ids := range userConfigFile.Components() {
+			ct, ok := s.registry.Get(component)
+			if !ok {
+				continue
+			}
+
+			if checked[component] == nil {
+				checked[component] = make(map[string]bool)
+			}
+
+			for _, id := range ids {
+				if checked[component][id] {
+					continue
+				}
+				checked[component][id] = true
+
+				exists, err := componentExists(ctx, ct, id)
+				if err != nil {
+					logrus.Errorf("unable to check if %s %s exists: %s", component, id, err)
+					continue
+				}
+
+				if exists {
+					continue
+				}
+
+				// the filter pulls git, so only the components missing in datadog are filtered.
+				if s.componentAllowed != nil && !s.componentAllowed(component, userConfigFile.Meta.Team, userConfigFile.Meta.Project, id) {
+					continue
+				}
+
+				logrus.Infof("Detected a deleted %s id %s", component, id)
+				select {
+				case <-ctx.Done():
+					return
+				case result <- &Response{
+					UserConfigFile: userConfigFile,
+					Component:      component,
+					ID:             id,
+					Deleted:        true,
+				}:
+				}
+			}
+		}
+	}
+}
+
+// componentExists checks if a component exists in datadog. The component types which could not check it
+// without fetching the dependencies of a component are checked by the whole payload.
+func componentExists(ctx context.Context, ct types.ComponentType, id string) (bool, error) {
+	if checker, ok := ct.(types.Checker); ok {
+		return checker.Exists(ctx, id)
+	}
+
+	_, err := ct.Get(ctx, id)
+	if client.IsNotFound(err) {
+		return false, nil
 	}
 
-	return monitors.GetModifiedIDsWithin(s.interval, nil)
+	return err == nil, err
 }
 
-func (s *simplePoller) pollScreenBoards() ([]int, error) {
-	screenBoards, err := s.ca.getScreenBoards()
+// refreshSelectors resolves user config selectors and sends the newly matched components.
+func (s *simplePoller) refreshSelectors(ctx context.Context, result chan *Response) {
+	newMatches, err := s.cfg.RefreshSelectors(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "unable to get screenboards")
+		// the components of other types could be matched, so they are still sent.
+		logrus.Errorf("unable to refresh user config selectors: %s", err)
 	}
 
-	return screenBoards.GetModifiedIDsWithin(s.interval, nil)
+	for userConfigFile, components := range newMatches {
+		for component, ids := range components {
+			for _, id := range ids {
+				logrus.Infof("Detected a new %s id %s matched by selectors of %s", component, id, userConfigFile.Meta.FilePath)
+				select {
+				case <-ctx.Done():
+					return
+				case result <- &Response{
+					UserConfigFile: userConfigFile,
+					Component:      component,
+					ID:             id,
+				}:
+				}
+			}
+		}
+	}
 }
 
-func (s *simplePoller) sendFilteredResponse(component types.Component, ids []int, result chan *Response) {
+// sendFilteredResponse sends the allowed changes to the result channel, it returns false if the context is done.
+func (s *simplePoller) sendFilteredResponse(ctx context.Context, component types.Component, ids []string, result chan *Response) bool {
 	for _, id := range ids {
 		userConfigFiles := s.cfg.UserConfigFilesByComponentID(component, id)
 
+		// the modifier is requested once per change and only if the change is sent.
+		var (
+			modifiedBy string
+			requested  bool
+		)
+
 		// send one event per user file
 		for _, userConfigFile := range userConfigFiles {
-			logrus.Debugf("Detected a change %s id %d", component, id)
+			logrus.Debugf("Detected a change %s id %s", component, id)
 			if s.componentAllowed != nil && !s.componentAllowed(component, userConfigFile.Meta.Team, userConfigFile.Meta.Project, id) {
 				logrus.Debugf("Change is not allowed. Skipping")
 				continue
 			}
 
-			result <- &Response{
+			if !requested {
+				modifiedBy = s.modifiedBy(ctx, component, id)
+				requested = true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case result <- &Response{
 				UserConfigFile: userConfigFile,
 				Component:      component,
 				ID:             id,
+				ModifiedBy:     modifiedBy,
+			}:
 			}
 		}
 	}
+
+	return true
+}
+
+// modifiedBy returns a handle of the user who changed a component, empty if the component type could not tell.
+func (s *simplePoller) modifiedBy(ctx context.Context, component types.Component, id string) string {
+	ct, ok := s.registry.Get(component)
+	if !ok {
+		return ""
+	}
+
+	modifier, ok := ct.(types.Modifier)
+	if !ok {
+		return ""
+	}
+
+	modifiedBy, err := modifier.ModifiedBy(ctx, id)
+	if err != nil {
+		logrus.Debugf("unable to get the modifier of %s %s: %s", component, id, err)
+		return ""
+	}
+
+	return modifiedBy
 }
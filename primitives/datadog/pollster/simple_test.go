@@ -2,19 +2,23 @@ package pollster
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/coinbase/watchdog/config"
+	"github.com/coinbase/watchdog/primitives/datadog"
 	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
+	"github.com/pkg/errors"
 )
 
 type fakeUserConfig struct {
+	userConfigFiles []*config.UserConfigFile
+	newMatches      map[*config.UserConfigFile]map[types.Component][]string
 }
 
-func (c fakeUserConfig) UserConfigFilesByComponentID(component types.Component, id int) []*config.UserConfigFile {
+func (c fakeUserConfig) UserConfigFilesByComponentID(component types.Component, id string) []*config.UserConfigFile {
 	return []*config.UserConfigFile{
 		&config.UserConfigFile{
 			Meta: config.MetaData{
@@ -33,12 +37,16 @@ func (c fakeUserConfig) GetUserConfigBasePath() string {
 	return ""
 }
 
-func (c fakeUserConfig) Reload() error {
+func (c fakeUserConfig) Reload(ctx context.Context) error {
 	return nil
 }
 
+func (c fakeUserConfig) RefreshSelectors(ctx context.Context) (map[*config.UserConfigFile]map[types.Component][]string, error) {
+	return c.newMatches, nil
+}
+
 func (c fakeUserConfig) UserConfigFiles() []*config.UserConfigFile {
-	return nil
+	return c.userConfigFiles
 }
 
 func (c fakeUserConfig) UserConfigFromFile(path string, a bool) (*config.UserConfigFile, error) {
@@ -46,25 +54,23 @@ func (c fakeUserConfig) UserConfigFromFile(path string, a bool) (*config.UserCon
 }
 
 func TestNewSimplePollster(t *testing.T) {
-	modified := time.Now().Add(time.Second).Format(time.RFC3339Nano)
-
-	dashboard := fmt.Sprintf(`{"dashes":[{"id":"1","modified":"%s"}]}`, modified)
-	monitors := fmt.Sprintf(`[{"id":2,"modified":"%s"}]`, modified)
-	screenboards := fmt.Sprintf(`{"screenboards":[{"id":3,"modified":"%s"}]}`, modified)
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentSynthetics, "synthetics", nil, func(ctx context.Context, interval time.Duration) ([]string, error) {
+			return []string{"abc"}, nil
+		}, nil, datadog.WithModifiedByFn(func(ctx context.Context, id string) (string, error) {
+			return datadog.PayloadModifier([]byte(`{"public_id":"` + id + `","modified_by":{"handle":"jane@example.com"}}`)), nil
+		})),
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", nil, func(ctx context.Context, interval time.Duration) ([]string, error) {
+			return nil, errors.New("monitors are not available")
+		}, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	p := &simplePoller{
 		interval: time.Millisecond * 100,
-		ca: &componentAccessors{
-			getDashboards: func() (client.DashboardsResponse, error) {
-				return []byte(dashboard), nil
-			},
-			getMonitors: func() (client.MonitorsResponse, error) {
-				return []byte(monitors), nil
-			},
-			getScreenBoards: func() (client.ScreenBoardsResponse, error) {
-				return []byte(screenboards), nil
-			},
-		},
+		registry: registry,
 
 		cfg: &config.Config{
 			UserConfig: &fakeUserConfig{},
@@ -76,6 +82,10 @@ func TestNewSimplePollster(t *testing.T) {
 	for {
 		select {
 		case value := <-ch:
+			if value.ModifiedBy != "jane@example.com" {
+				t.Fatalf("expect modified by jane@example.com. Got %q", value.ModifiedBy)
+			}
+
 			cfgFiles = append(cfgFiles, value.UserConfigFile)
 			if len(cfgFiles) == 2 {
 				if cfgFiles[0].Meta.FilePath != "foo/bar" {
@@ -94,3 +104,130 @@ func TestNewSimplePollster(t *testing.T) {
 	}
 
 }
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Now()
+	p := &simplePoller{
+		rateLimits: func() map[string]client.RateLimit {
+			return map[string]client.RateLimit{
+				"monitor": {Limit: 100, Remaining: 50, ResetAt: now.Add(time.Minute)},
+				"dash":    {Limit: 100, Remaining: 5, ResetAt: now.Add(time.Second * 10)},
+				"slo":     {Limit: 100, Remaining: 0, ResetAt: now.Add(-time.Second)},
+			}
+		},
+	}
+
+	if wait := p.rateLimitWait(now); wait != time.Second*10 {
+		t.Fatalf("expect to wait 10s. Got %s", wait)
+	}
+
+	p.rateLimits = nil
+	if wait := p.rateLimitWait(now); wait != 0 {
+		t.Fatalf("expect no wait without rate limits. Got %s", wait)
+	}
+}
+
+func TestCheckDeleted(t *testing.T) {
+	registry, err := types.NewRegistry(
+		datadog.NewComponentType(types.ComponentDashboard, "dashboards", func(ctx context.Context, id string) (json.RawMessage, error) {
+			switch id {
+			case "2":
+				return nil, errors.Wrap(&client.NotFoundError{URL: "dash/2", Method: "GET"}, "unable to get dashboard")
+			case "3":
+				return nil, errors.New("dashboards are not available")
+			}
+			return []byte(`{}`), nil
+		}, nil, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var filtered []string
+	p := &simplePoller{
+		registry: registry,
+		cfg: &config.Config{
+			UserConfig: &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{
+				config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar"}, map[types.Component][]string{types.ComponentDashboard: {"1", "2", "3"}}),
+				config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar2"}, map[types.Component][]string{types.ComponentDashboard: {"2"}}),
+			}},
+		},
+		componentAllowed: func(component types.Component, team, project, id string) bool {
+			filtered = append(filtered, id)
+			return true
+		},
+	}
+
+	result := make(chan *Response, 10)
+	p.checkDeleted(context.Background(), result)
+	close(result)
+
+	if len(filtered) != 1 || filtered[0] != "2" {
+		t.Fatalf("expect only the deleted dashboard 2 filtered. Got %v", filtered)
+	}
+
+	var responses []*Response
+	for response := range result {
+		responses = append(responses, response)
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("expect 1 deleted component. Got %d", len(responses))
+	}
+
+	if r := responses[0]; r.ID != "2" || !r.Deleted || r.UserConfigFile.Meta.FilePath != "foo/bar" {
+		t.Fatalf("expect deleted dashboard 2 from foo/bar. Got %+v", r)
+	}
+
+	// a monitor is checked by the monitor itself, a missing dependency does not make it deleted
+	registry, err = types.NewRegistry(
+		datadog.NewComponentType(types.ComponentMonitor, "monitors", func(ctx context.Context, id string) (json.RawMessage, error) {
+			return nil, errors.Wrap(&client.NotFoundError{URL: "downtime/5", Method: "GET"}, "unable to get downtime")
+		}, nil, nil, datadog.WithExistsFn(func(ctx context.Context, id string) error {
+			return nil
+		})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.registry = registry
+	p.cfg = &config.Config{
+		UserConfig: &fakeUserConfig{userConfigFiles: []*config.UserConfigFile{
+			config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar"}, map[types.Component][]string{types.ComponentMonitor: {"1"}}),
+		}},
+	}
+
+	result = make(chan *Response, 10)
+	p.checkDeleted(context.Background(), result)
+	if len(result) != 0 {
+		t.Fatalf("expect no deleted monitors. Got %d", len(result))
+	}
+}
+
+func TestRefreshSelectors(t *testing.T) {
+	cfgFile := config.NewUserConfigFile(config.MetaData{FilePath: "foo/bar"}, nil)
+	p := &simplePoller{
+		cfg: &config.Config{
+			UserConfig: &fakeUserConfig{newMatches: map[*config.UserConfigFile]map[types.Component][]string{
+				cfgFile: {types.ComponentMonitor: {"1", "2"}},
+			}},
+		},
+	}
+
+	result := make(chan *Response, 10)
+	p.refreshSelectors(context.Background(), result)
+	close(result)
+
+	var ids []string
+	for response := range result {
+		if response.UserConfigFile != cfgFile || response.Component != types.ComponentMonitor || response.Deleted {
+			t.Fatalf("expect a monitor matched by foo/bar selectors. Got %+v", response)
+		}
+		ids = append(ids, response.ID)
+	}
+
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("expect monitors 1 and 2. Got %v", ids)
+	}
+}
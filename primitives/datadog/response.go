@@ -1,19 +1,58 @@
 package datadog
 
 import (
+	"bytes"
 	"encoding/json"
 
-	"github.com/coinbase/watchdog/primitives/datadog/client"
 	"github.com/coinbase/watchdog/primitives/datadog/types"
+
+	"github.com/pkg/errors"
 )
 
 // Component represents a structure of a watchdog component which holds
 // one of datadog component (dashboard, monitor etc.)
+// The payload is stored under a key named after the component type, for example
+// {"type": "dashboard", "dashboard": {...}}
 type Component struct {
-	Type types.Component `json:"type"`
+	Type    types.Component
+	Payload json.RawMessage
+}
+
+// MarshalJSON encodes a component with a payload under the component type key.
+func (c Component) MarshalJSON() ([]byte, error) {
+	componentType, err := json.Marshal(c.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(`{"type":`)
+	buf.Write(componentType)
+
+	if len(c.Payload) > 0 {
+		buf.WriteString(",")
+		buf.Write(componentType)
+		buf.WriteString(":")
+		buf.Write(c.Payload)
+	}
+
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a component and its payload.
+func (c *Component) UnmarshalJSON(b []byte) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	var componentType types.Component
+	if err := json.Unmarshal(fields["type"], &componentType); err != nil {
+		return errors.Wrap(err, "unable to unmarshal component type")
+	}
 
-	Dashboard   json.RawMessage                 `json:"dashboard,omitempty"`
-	Monitor     *client.MonitorWithDependencies `json:"monitor,omitempty"`
-	Downtime    json.RawMessage                 `json:"downtime,omitempty"`
-	ScreenBoard json.RawMessage                 `json:"screenboard,omitempty"`
+	c.Type = componentType
+	c.Payload = fields[string(componentType)]
+	return nil
 }
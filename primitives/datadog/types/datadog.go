@@ -7,6 +7,10 @@ var (
 	// ComponentDashboard stands for dashboard or timeboard.
 	ComponentDashboard = Component("dashboard")
 
+	// ComponentUnifiedDashboard stands for a dashboard managed by the unified /api/v1/dashboard API.
+	// Unlike other components it uses string IDs like "abc-def-ghi".
+	ComponentUnifiedDashboard = Component("unified_dashboard")
+
 	// ComponentMonitor stands for monitor.
 	ComponentMonitor = Component("monitor")
 
@@ -15,4 +19,13 @@ var (
 
 	// ComponentDowntime stands for downtime.
 	ComponentDowntime = Component("downtime")
+
+	// ComponentSLO stands for a service level objective, identified by a string ID.
+	ComponentSLO = Component("slo")
+
+	// ComponentLogPipeline stands for a log pipeline with its processors and position in the pipeline order.
+	ComponentLogPipeline = Component("log_pipeline")
+
+	// ComponentSynthetics stands for an API or browser synthetic test, identified by a string public ID.
+	ComponentSynthetics = Component("synthetics")
 )
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNilComponentType is returned if a nil component type is registered.
+	ErrNilComponentType = errors.New("nil component type")
+
+	// ErrDuplicateConfigKey is returned if two different component types use the same user config key.
+	ErrDuplicateConfigKey = errors.New("duplicate user config key")
+)
+
+// ComponentType is implemented by every datadog component type managed by watchdog.
+// A new component type could be added by registering the implementation in a Registry,
+// all watchdog subsystems iterate over the registry.
+type ComponentType interface {
+	// Type returns a unique component type. It is used in component files and file names.
+	Type() Component
+
+	// ConfigKey returns a key in a user config file, which holds a list of component IDs.
+	ConfigKey() string
+
+	// Get fetches a component by ID and returns a payload stored in a component file.
+	Get(ctx context.Context, id string) (json.RawMessage, error)
+
+	// ModifiedIDs returns a list of component IDs modified within the given interval.
+	ModifiedIDs(ctx context.Context, interval time.Duration) ([]string, error)
+
+	// Update restores a component from a payload stored in a component file.
+	Update(ctx context.Context, payload json.RawMessage) error
+}
+
+// Creator is implemented by component types which could be created from a component file.
+type Creator interface {
+	// PayloadID returns a component ID stored in a payload, empty if the payload has no ID.
+	PayloadID(payload json.RawMessage) (string, error)
+
+	// Create creates a component from a payload stored in a component file and returns the new ID.
+	Create(ctx context.Context, payload json.RawMessage) (string, error)
+}
+
+// Deleter is implemented by component types which could be deleted when a component file is removed.
+type Deleter interface {
+	// Delete deletes a component by ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// Validator is implemented by component types which could be validated by datadog without being saved.
+type Validator interface {
+	// Validate returns the errors found in a payload stored in a component file, empty if the payload is valid.
+	Validate(ctx context.Context, payload json.RawMessage) ([]string, error)
+}
+
+// Checker is implemented by component types which could check if a component exists without fetching its payload,
+// e.g. without the dependencies of a monitor.
+type Checker interface {
+	// Exists returns false if a component does not exist in datadog.
+	Exists(ctx context.Context, id string) (bool, error)
+}
+
+// Normalizer is implemented by component types whose payload has fields datadog changes on its own.
+type Normalizer interface {
+	// Normalize returns a payload without the fields datadog changes on its own, so the payloads could be
+	// compared by content.
+	Normalize(payload json.RawMessage) (json.RawMessage, error)
+}
+
+// Modifier is implemented by component types which could tell who changed a component.
+type Modifier interface {
+	// ModifiedBy returns a handle of the user who changed a component, empty if it is unknown.
+	ModifiedBy(ctx context.Context, id string) (string, error)
+}
+
+// NewRegistry returns a new registry with the given component types.
+func NewRegistry(componentTypes ...ComponentType) (*Registry, error) {
+	r := &Registry{
+		byType: make(map[Component]ComponentType),
+	}
+
+	for _, ct := range componentTypes {
+		if err := r.Register(ct); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Registry holds component types managed by watchdog. The order of registration is preserved.
+type Registry struct {
+	sync.RWMutex
+
+	order  []Component
+	byType map[Component]ComponentType
+}
+
+// Register adds a component type to the registry. A component type with the same type
+// replaces the registered one, this could be used to customize the default component types.
+func (r *Registry) Register(ct ComponentType) error {
+	if ct == nil {
+		return ErrNilComponentType
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, registered := range r.byType {
+		if registered.Type() != ct.Type() && registered.ConfigKey() == ct.ConfigKey() {
+			return errors.Wrapf(ErrDuplicateConfigKey, "key %s is used by %s and %s", ct.ConfigKey(), registered.Type(), ct.Type())
+		}
+	}
+
+	if _, ok := r.byType[ct.Type()]; !ok {
+		r.order = append(r.order, ct.Type())
+	}
+
+	r.byType[ct.Type()] = ct
+	return nil
+}
+
+// Get returns a component type.
+func (r *Registry) Get(component Component) (ComponentType, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	ct, ok := r.byType[component]
+	return ct, ok
+}
+
+// ComponentTypes returns all registered component types in the order of registration.
+func (r *Registry) ComponentTypes() []ComponentType {
+	r.RLock()
+	defer r.RUnlock()
+
+	var out []ComponentType
+	for _, component := range r.order {
+		out = append(out, r.byType[component])
+	}
+
+	return out
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type fakeComponentType struct {
+	component Component
+	configKey string
+}
+
+func (f fakeComponentType) Type() Component {
+	return f.component
+}
+
+func (f fakeComponentType) ConfigKey() string {
+	return f.configKey
+}
+
+func (f fakeComponentType) Get(ctx context.Context, id string) (json.RawMessage, error) {
+	return nil, nil
+}
+
+func (f fakeComponentType) ModifiedIDs(ctx context.Context, interval time.Duration) ([]string, error) {
+	return nil, nil
+}
+
+func (f fakeComponentType) Update(ctx context.Context, payload json.RawMessage) error {
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	r, err := NewRegistry(
+		fakeComponentType{ComponentDashboard, "dashboards"},
+		fakeComponentType{ComponentMonitor, "monitors"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the dashboard component type
+	err = r.Register(fakeComponentType{ComponentDashboard, "dashboards"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.Register(fakeComponentType{Component("custom"), "customComponents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	componentTypes := r.ComponentTypes()
+	if len(componentTypes) != 3 {
+		t.Fatalf("expect 3 component types. Got %d", len(componentTypes))
+	}
+
+	for i, expected := range []Component{ComponentDashboard, ComponentMonitor, Component("custom")} {
+		if componentTypes[i].Type() != expected {
+			t.Fatalf("expect component type %s at position %d. Got %s", expected, i, componentTypes[i].Type())
+		}
+	}
+
+	if _, ok := r.Get(Component("custom")); !ok {
+		t.Fatal("expect custom component type to be registered")
+	}
+
+	err = r.Register(fakeComponentType{Component("other"), "monitors"})
+	if errors.Cause(err) != ErrDuplicateConfigKey {
+		t.Fatalf("expect error %s. Got %v", ErrDuplicateConfigKey, err)
+	}
+
+	if err := r.Register(nil); err != ErrNilComponentType {
+		t.Fatalf("expect error %s. Got %v", ErrNilComponentType, err)
+	}
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"context"
+	"strings"
+)
+
+// Selector selects components by datadog tags and a title prefix. A component matches a selector
+// if it has all the tags and its title starts with the prefix.
+type Selector struct {
+	Tags        []string `yaml:"tags"`
+	TitlePrefix string   `yaml:"titlePrefix"`
+}
+
+// Empty returns true if the selector has no criteria. An empty selector would match every component.
+func (s Selector) Empty() bool {
+	return len(s.Tags) == 0 && s.TitlePrefix == ""
+}
+
+// Matches returns true if a component summary matches the selector.
+func (s Selector) Matches(summary Summary) bool {
+	if s.Empty() || !strings.HasPrefix(summary.Title, s.TitlePrefix) {
+		return false
+	}
+
+	tags := make(map[string]bool)
+	for _, tag := range summary.Tags {
+		tags[tag] = true
+	}
+
+	for _, tag := range s.Tags {
+		if !tags[tag] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Summary is a short description of a component used to match selectors and to report unmanaged components.
+type Summary struct {
+	ID      string
+	Title   string
+	Tags    []string
+	Creator string
+}
+
+// Selectable is implemented by component types which could be selected by selectors in user config.
+type Selectable interface {
+	// List returns summaries of all components of the type.
+	List(ctx context.Context) ([]Summary, error)
+}
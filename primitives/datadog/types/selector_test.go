@@ -0,0 +1,26 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	summary := Summary{ID: "1", Title: "[payments] latency", Tags: []string{"team:payments", "env:prod"}}
+
+	for _, tc := range []struct {
+		selector Selector
+		expected bool
+	}{
+		{Selector{Tags: []string{"team:payments"}}, true},
+		{Selector{Tags: []string{"team:payments", "env:prod"}}, true},
+		{Selector{Tags: []string{"team:payments", "env:staging"}}, false},
+		{Selector{TitlePrefix: "[payments]"}, true},
+		{Selector{TitlePrefix: "[payments]", Tags: []string{"env:staging"}}, false},
+		{Selector{TitlePrefix: "[infra]"}, false},
+		{Selector{}, false},
+	} {
+		if matches := tc.selector.Matches(summary); matches != tc.expected {
+			t.Fatalf("expect selector %+v to match %v. Got %v", tc.selector, tc.expected, matches)
+		}
+	}
+}
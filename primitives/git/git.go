@@ -47,6 +47,33 @@ func New(opts ...Option) (Client, error) {
 	return g, nil
 }
 
+// Open returns a new instance of Git object for an existing local checkout, e.g. a repository checked out in CI.
+// The files are read from the checkout directory as they are, nothing is cloned.
+func Open(path string, opts ...Option) (Client, error) {
+	g := &Git{}
+	for _, opt := range opts {
+		if opt != nil {
+			if err := opt(g); err != nil {
+				return nil, errors.Wrap(err, "invalid option used")
+			}
+		}
+	}
+
+	var err error
+	g.repository, err = git.PlainOpen(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open a repository %s", path)
+	}
+
+	g.worktree, err = g.repository.Worktree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get a worktree for %s", path)
+	}
+	g.fs = g.worktree.Filesystem
+
+	return g, nil
+}
+
 // Git represents an abstraction over git.
 type Git struct {
 	auth    transport.AuthMethod
@@ -302,6 +329,16 @@ func (g *Git) Add(path string) error {
 	return nil
 }
 
+// Remove similar to git rm.
+func (g *Git) Remove(path string) error {
+	_, err := g.worktree.Remove(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to remove %s", path)
+	}
+
+	return nil
+}
+
 // Commit makes a new git commit.
 func (g *Git) Commit(msg string) (string, string, error) {
 	commit, err := g.worktree.Commit(msg, &git.CommitOptions{
@@ -323,7 +360,13 @@ func (g *Git) Commit(msg string) (string, string, error) {
 	return obj.String(), obj.Hash.String(), nil
 }
 
-// Push to remote repo.
+// UpdateRefSpec returns a refspec which force-pushes a local branch to a remote branch of another name.
+func UpdateRefSpec(branch, remoteBranch string) string {
+	return fmt.Sprintf("+%s:%s", branch, remoteBranch)
+}
+
+// Push to remote repo. A branch is pushed to the remote branch of the same name, a refspec like
+// the one of UpdateRefSpec updates a remote branch from a local branch of another name.
 func (g *Git) Push(branches ...string) error {
 	if len(branches) == 0 {
 		return errors.New("empty branch")
@@ -331,7 +374,15 @@ func (g *Git) Push(branches ...string) error {
 
 	var refSpecs []config.RefSpec
 	for _, branch := range branches {
-		refSpecs = append(refSpecs, config.RefSpec(fmt.Sprintf("%s:%s", branch, branch)))
+		refSpec := config.RefSpec(branch)
+		if !strings.Contains(branch, ":") {
+			refSpec = config.RefSpec(fmt.Sprintf("%s:%s", branch, branch))
+		}
+
+		if err := refSpec.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid branch %s", branch)
+		}
+		refSpecs = append(refSpecs, refSpec)
 	}
 
 	err := g.repository.Push(&git.PushOptions{
@@ -43,10 +43,14 @@ type Client interface {
 	// Add works like "git add" to add files to a commit.
 	Add(path string) error
 
+	// Remove works like "git rm" to remove a file from the workspace and the index.
+	Remove(path string) error
+
 	// Commit makes a new commit.
 	Commit(msg string) (string, string, error)
 
-	// Push branches to remote repo.
+	// Push branches to remote repo. A refspec like +branch:remoteBranch force-pushes a branch to a remote
+	// branch of another name.
 	Push(branches ...string) error
 
 	// Clean returns true if the workspace is clean, similar to "git status"
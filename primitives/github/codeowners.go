@@ -0,0 +1,111 @@
+package github
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CodeownersPaths are the locations of a CODEOWNERS file in a repository, in the order github looks them up.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Codeowners is a parsed CODEOWNERS file.
+type Codeowners struct {
+	rules []codeownersRule
+}
+
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// ParseCodeowners parses a CODEOWNERS file. Comments, empty lines and invalid patterns are skipped.
+func ParseCodeowners(body []byte) *Codeowners {
+	c := &Codeowners{}
+	for _, line := range strings.Split(string(body), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, err := regexp.Compile(codeownersPattern(fields[0]))
+		if err != nil {
+			continue
+		}
+
+		c.rules = append(c.rules, codeownersRule{pattern: pattern, owners: fields[1:]})
+	}
+
+	return c
+}
+
+// Owners returns the github logins and the team slugs which own a file. As in github, the last matching
+// pattern takes precedence. The owners identified by an email are skipped.
+func (c *Codeowners) Owners(file string) (reviewers, teamReviewers []string) {
+	file = strings.TrimPrefix(file, "/")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if !c.rules[i].pattern.MatchString(file) {
+			continue
+		}
+
+		for _, owner := range c.rules[i].owners {
+			if !strings.HasPrefix(owner, "@") {
+				continue
+			}
+
+			// teams are referenced as @org/team-slug
+			if parts := strings.SplitN(owner[1:], "/", 2); len(parts) == 2 {
+				teamReviewers = append(teamReviewers, parts[1])
+			} else {
+				reviewers = append(reviewers, owner[1:])
+			}
+		}
+
+		return reviewers, teamReviewers
+	}
+
+	return nil, nil
+}
+
+// codeownersPattern converts a gitignore style pattern to a regular expression. A pattern with a slash
+// other than a trailing one is relative to the root of the repository, otherwise it matches at any level.
+// A pattern matches a file and the content of a directory.
+func codeownersPattern(pattern string) string {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		expr.WriteString(".*$")
+	} else {
+		expr.WriteString("(/.*)?$")
+	}
+
+	return expr.String()
+}
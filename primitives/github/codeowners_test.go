@@ -0,0 +1,43 @@
+package github
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeowners(t *testing.T) {
+	codeowners := ParseCodeowners([]byte(strings.Join([]string{
+		"# default owners",
+		"*                     @org/platform",
+		"*.json                @json-owner",
+		"/data/infra/          @org/sre jane ops@example.com",
+		"data/payments/**      @john # payments",
+		"config/*.yml          @config-owner",
+	}, "\n")))
+
+	for _, tc := range []struct {
+		file          string
+		reviewers     []string
+		teamReviewers []string
+	}{
+		{file: "README.md", teamReviewers: []string{"platform"}},
+		{file: "data/web/dashboard-1.json", reviewers: []string{"json-owner"}},
+		{file: "data/infra/sre/monitor-1.json", teamReviewers: []string{"sre"}},
+		{file: "data/payments/monitor-1.json", reviewers: []string{"john"}},
+		{file: "/config/team.yml", reviewers: []string{"config-owner"}},
+		{file: "config/sub/team.yml", teamReviewers: []string{"platform"}},
+	} {
+		reviewers, teamReviewers := codeowners.Owners(tc.file)
+		if strings.Join(reviewers, ",") != strings.Join(tc.reviewers, ",") {
+			t.Fatalf("expect %s reviewers %v. Got %v", tc.file, tc.reviewers, reviewers)
+		}
+
+		if strings.Join(teamReviewers, ",") != strings.Join(tc.teamReviewers, ",") {
+			t.Fatalf("expect %s team reviewers %v. Got %v", tc.file, tc.teamReviewers, teamReviewers)
+		}
+	}
+
+	if reviewers, teamReviewers := ParseCodeowners(nil).Owners("data/a.json"); reviewers != nil || teamReviewers != nil {
+		t.Fatalf("expect no owners. Got %v %v", reviewers, teamReviewers)
+	}
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -36,18 +37,9 @@ type Github struct {
 // this pull request.
 // Example diff url: https://github.com/owner/project/pull/95.diff
 func (gh *Github) PullRequestFiles(ctx context.Context, number int) (created, removed, modified []string, err error) {
-	patch, _, err := gh.client.PullRequests.GetRaw(ctx, gh.owner, gh.repositoryName, number, github.RawOptions{
-		Type: github.Diff,
-	})
-	if err != nil {
-		return nil, nil, nil, errors.Wrapf(err, "unable to get raw patch for PR %d", number)
-	}
-
-	logrus.Debugf("Detected a patch: %s", patch)
-
-	diff, err := diffparser.Parse(patch)
+	diff, err := gh.pullRequestDiff(ctx, number)
 	if err != nil {
-		return nil, nil, nil, errors.Wrapf(err, "unable to parse patch for PR with number %d", number)
+		return nil, nil, nil, err
 	}
 
 	for _, file := range diff.Files {
@@ -69,6 +61,24 @@ func (gh *Github) PullRequestFiles(ctx context.Context, number int) (created, re
 	return
 }
 
+func (gh *Github) pullRequestDiff(ctx context.Context, number int) (*diffparser.Diff, error) {
+	patch, _, err := gh.client.PullRequests.GetRaw(ctx, gh.owner, gh.repositoryName, number, github.RawOptions{
+		Type: github.Diff,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get raw patch for PR %d", number)
+	}
+
+	logrus.Debugf("Detected a patch: %s", patch)
+
+	diff, err := diffparser.Parse(patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse patch for PR with number %d", number)
+	}
+
+	return diff, nil
+}
+
 // CreatePullRequest creates a new pull request.
 func (gh *Github) CreatePullRequest(ctx context.Context, title, head, base, body string) (string, int, error) {
 	newPR := &github.NewPullRequest{
@@ -87,6 +97,19 @@ func (gh *Github) CreatePullRequest(ctx context.Context, title, head, base, body
 	return pr.GetHTMLURL(), pr.GetNumber(), nil
 }
 
+// EditPullRequest updates the title and the body of a pull request.
+func (gh *Github) EditPullRequest(ctx context.Context, number int, title, body string) error {
+	_, _, err := gh.client.PullRequests.Edit(ctx, gh.owner, gh.repositoryName, number, &github.PullRequest{
+		Title: github.String(title),
+		Body:  github.String(body),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to edit a PR %d", number)
+	}
+
+	return nil
+}
+
 // ClosePullRequests closes a list of pull requests.
 func (gh *Github) ClosePullRequests(prs []int, removeRemoteBranch bool) error {
 	for _, pr := range prs {
@@ -127,6 +150,9 @@ type PullRequest struct {
 	CreatedFiles  []string
 	RemovedFiles  []string
 	ModifiedFiles []string
+
+	// Merged is set if a closed pull request was merged.
+	Merged bool
 }
 
 // AllFiles returns a one slice for all files in pull requested (created, removed and modified)
@@ -172,12 +198,71 @@ func (gh *Github) FindPullRequests(ctx context.Context, owner, titleMatch string
 	return
 }
 
-// RequestReviewers add reviewers to a PR.
-func (gh *Github) RequestReviewers(pr int, names []string) error {
-	_, _, err := gh.client.PullRequests.RequestReviewers(context.Background(), gh.owner, gh.repositoryName, pr, github.ReviewersRequest{
-		Reviewers: names,
+// FindClosedPullRequests searches and returns a list of closed pull requests with a title. The files of
+// the pull requests are not listed.
+func (gh *Github) FindClosedPullRequests(ctx context.Context, owner, titleMatch string) (prs []*PullRequest, err error) {
+	opts := &github.PullRequestListOptions{
+		Head:        owner,
+		State:       "closed",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		pullRequests, resp, err := gh.client.PullRequests.List(ctx, gh.owner, gh.repositoryName, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list closed pull requests for user %s", owner)
+		}
+
+		for _, pr := range pullRequests {
+			if pr.GetTitle() != titleMatch {
+				continue
+			}
+
+			prs = append(prs, &PullRequest{
+				Number:    pr.GetNumber(),
+				Branch:    "refs/heads/" + pr.GetHead().GetRef(),
+				CreatedAt: pr.CreatedAt,
+				SHA:       pr.GetHead().GetSHA(),
+				Merged:    pr.MergedAt != nil,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			return prs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// RequestReviewers add reviewers and team reviewers to a PR.
+func (gh *Github) RequestReviewers(ctx context.Context, pr int, reviewers, teamReviewers []string) error {
+	if len(reviewers) == 0 && len(teamReviewers) == 0 {
+		return nil
+	}
+
+	_, _, err := gh.client.PullRequests.RequestReviewers(ctx, gh.owner, gh.repositoryName, pr, github.ReviewersRequest{
+		Reviewers:     reviewers,
+		TeamReviewers: teamReviewers,
 	})
-	return err
+	if err != nil {
+		return errors.Wrapf(err, "unable to request reviewers of PR %d", pr)
+	}
+
+	return nil
+}
+
+// AddLabels adds labels to a PR, the labels which do not exist in the repository are created by github.
+func (gh *Github) AddLabels(ctx context.Context, pr int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	_, _, err := gh.client.Issues.AddLabelsToIssue(ctx, gh.owner, gh.repositoryName, pr, labels)
+	if err != nil {
+		return errors.Wrapf(err, "unable to add labels to PR %d", pr)
+	}
+
+	return nil
 }
 
 // CreatePullRequestComment creates a new comment on a given pull request.
@@ -191,3 +276,132 @@ func (gh *Github) CreatePullRequestComment(ctx context.Context, id int, text str
 
 	return nil
 }
+
+// GetFileContent returns the content of a file at a git reference.
+func (gh *Github) GetFileContent(ctx context.Context, path, ref string) ([]byte, error) {
+	file, _, _, err := gh.client.Repositories.GetContents(ctx, gh.owner, gh.repositoryName, path, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get file %s at %s", path, ref)
+	}
+
+	if file == nil {
+		return nil, errors.Errorf("%s at %s is not a file", path, ref)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decode file %s at %s", path, ref)
+	}
+
+	return []byte(content), nil
+}
+
+// maxStatusDescription is a max length of a commit status description accepted by github.
+const maxStatusDescription = 140
+
+// CreateCommitStatus sets a status of a commit under a status context, e.g. watchdog/lint.
+func (gh *Github) CreateCommitStatus(ctx context.Context, sha, state, statusContext, description string) error {
+	if len(description) > maxStatusDescription {
+		description = description[:maxStatusDescription-3] + "..."
+	}
+
+	_, _, err := gh.client.Repositories.CreateStatus(ctx, gh.owner, gh.repositoryName, sha, &github.RepoStatus{
+		State:       github.String(state),
+		Context:     github.String(statusContext),
+		Description: github.String(description),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to create %s status of commit %s", statusContext, sha)
+	}
+
+	return nil
+}
+
+// CheckRun is a completed check run of a commit.
+type CheckRun struct {
+	Name       string
+	HeadBranch string
+	HeadSHA    string
+
+	// Conclusion is one of success, failure or neutral.
+	Conclusion string
+	Title      string
+	Summary    string
+}
+
+// CreateCheckRun creates a completed check run.
+func (gh *Github) CreateCheckRun(ctx context.Context, run CheckRun) error {
+	_, _, err := gh.client.Checks.CreateCheckRun(ctx, gh.owner, gh.repositoryName, github.CreateCheckRunOptions{
+		Name:        run.Name,
+		HeadBranch:  run.HeadBranch,
+		HeadSHA:     run.HeadSHA,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(run.Conclusion),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:   github.String(run.Title),
+			Summary: github.String(run.Summary),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to create check run %s of commit %s", run.Name, run.HeadSHA)
+	}
+
+	return nil
+}
+
+// ReviewComment is a review comment on a line of a file changed in a pull request.
+type ReviewComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+// CreateReview creates a pull request review of a commit with comments on file lines. A comment on a line
+// which is not a part of the pull request diff is added to the review body.
+func (gh *Github) CreateReview(ctx context.Context, number int, sha, body string, comments []ReviewComment) error {
+	diff, err := gh.pullRequestDiff(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	// github comments on a position in the diff of a file rather than on a line of the file.
+	positions := make(map[string]map[int]int)
+	for _, file := range diff.Files {
+		lines := make(map[int]int)
+		for _, hunk := range file.Hunks {
+			for _, line := range hunk.NewRange.Lines {
+				lines[line.Number] = line.Position
+			}
+		}
+		positions[file.NewName] = lines
+	}
+
+	review := &github.PullRequestReviewRequest{
+		CommitID: github.String(sha),
+		Event:    github.String("COMMENT"),
+	}
+	for _, comment := range comments {
+		position, ok := positions[comment.Path][comment.Line]
+		if !ok {
+			body += fmt.Sprintf("\n\n%s line %d: %s", comment.Path, comment.Line, comment.Body)
+			continue
+		}
+
+		review.Comments = append(review.Comments, &github.DraftReviewComment{
+			Path:     github.String(comment.Path),
+			Position: github.Int(position),
+			Body:     github.String(comment.Body),
+		})
+	}
+	review.Body = github.String(body)
+
+	_, _, err = gh.client.PullRequests.CreateReview(ctx, gh.owner, gh.repositoryName, number, review)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create a review of pull request %d", number)
+	}
+
+	return nil
+}
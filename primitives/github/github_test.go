@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+// newTestGithub returns a github client of the owner/repo repository which sends the requests to a fake
+// github API. The fake records the request bodies by method and path.
+func newTestGithub(t *testing.T, requests map[string]string) (*Github, func()) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			t.Errorf("unable to decode a request body: %s", err)
+		}
+		requests[r.Method+" "+r.URL.Path] = string(body)
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1/labels":
+			w.Write([]byte(`[{"name":"datadog"}]`))
+		case "/repos/owner/repo/pulls":
+			if r.URL.Query().Get("state") != "closed" {
+				t.Errorf("expect closed pull requests. Got %s", r.URL.RawQuery)
+			}
+
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				w.Write([]byte(`[{"number":1,"title":"a"},{"number":2,"title":"b"}]`))
+				return
+			}
+			w.Write([]byte(`[{"number":3,"title":"a","merged_at":"2019-01-01T00:00:00Z"}]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+
+	baseURL, err := url.Parse(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := github.NewClient(nil)
+	client.BaseURL = baseURL
+
+	return &Github{client: client, owner: "owner", repositoryName: "repo"}, ts.Close
+}
+
+func TestRequestReviewers(t *testing.T) {
+	requests := make(map[string]string)
+	gh, closeFn := newTestGithub(t, requests)
+	defer closeFn()
+
+	if err := gh.RequestReviewers(context.Background(), 1, []string{"jane"}, []string{"sre"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"reviewers":["jane"],"team_reviewers":["sre"]}`
+	if body := requests["POST /repos/owner/repo/pulls/1/requested_reviewers"]; body != expected {
+		t.Fatalf("expect request %s. Got %v", expected, requests)
+	}
+
+	// nothing is requested without reviewers
+	delete(requests, "POST /repos/owner/repo/pulls/1/requested_reviewers")
+	if err := gh.RequestReviewers(context.Background(), 1, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 0 {
+		t.Fatalf("expect no requests. Got %v", requests)
+	}
+}
+
+func TestAddLabels(t *testing.T) {
+	requests := make(map[string]string)
+	gh, closeFn := newTestGithub(t, requests)
+	defer closeFn()
+
+	if err := gh.AddLabels(context.Background(), 1, []string{"datadog", "team:sre"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `["datadog","team:sre"]`
+	if body := requests["POST /repos/owner/repo/issues/1/labels"]; body != expected {
+		t.Fatalf("expect request %s. Got %v", expected, requests)
+	}
+
+	delete(requests, "POST /repos/owner/repo/issues/1/labels")
+	if err := gh.AddLabels(context.Background(), 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 0 {
+		t.Fatalf("expect no requests. Got %v", requests)
+	}
+}
+
+func TestEditPullRequest(t *testing.T) {
+	requests := make(map[string]string)
+	gh, closeFn := newTestGithub(t, requests)
+	defer closeFn()
+
+	if err := gh.EditPullRequest(context.Background(), 1, "title", "body"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"title":"title","body":"body"}`
+	if body := requests["PATCH /repos/owner/repo/pulls/1"]; body != expected {
+		t.Fatalf("expect request %s. Got %v", expected, requests)
+	}
+}
+
+func TestFindClosedPullRequests(t *testing.T) {
+	requests := make(map[string]string)
+	gh, closeFn := newTestGithub(t, requests)
+	defer closeFn()
+
+	prs, err := gh.FindClosedPullRequests(context.Background(), "bot", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prs) != 2 || prs[0].Number != 1 || prs[0].Merged || prs[1].Number != 3 || !prs[1].Merged {
+		t.Fatalf("expect closed pull request 1 and merged pull request 3. Got %+v", prs)
+	}
+}
@@ -10,18 +10,39 @@ type Client interface {
 	// CreatePullRequest creates a new pull request.
 	CreatePullRequest(ctx context.Context, title, head, base, body string) (string, int, error)
 
+	// EditPullRequest updates the title and the body of a pull request.
+	EditPullRequest(ctx context.Context, number int, title, body string) error
+
 	// ClosePullRequests closes pull requests.
 	ClosePullRequests(prs []int, removeRemoteBranch bool) error
 
 	// FindPullRequests searches pull requests with an owner and title.
 	FindPullRequests(ctx context.Context, owner, titleMatch string) (prs []*PullRequest, err error)
 
-	// RequestReviewers assigns the reviewers to a pull request.
-	RequestReviewers(pr int, names []string) error
+	// FindClosedPullRequests searches closed pull requests with an owner and title.
+	FindClosedPullRequests(ctx context.Context, owner, titleMatch string) (prs []*PullRequest, err error)
+
+	// RequestReviewers assigns the reviewers and the team reviewers to a pull request.
+	RequestReviewers(ctx context.Context, pr int, reviewers, teamReviewers []string) error
+
+	// AddLabels adds labels to a pull request.
+	AddLabels(ctx context.Context, pr int, labels []string) error
 
 	// RemoveRemoveRef removes a reference from remote git repository.
 	RemoveRemoveRef(ctx context.Context, ref string) error
 
 	// CreatePullRequestComment creates a new comment on a pull request.
 	CreatePullRequestComment(ctx context.Context, id int, text string) error
+
+	// GetFileContent returns the content of a file at a git reference, e.g. a commit SHA.
+	GetFileContent(ctx context.Context, path, ref string) ([]byte, error)
+
+	// CreateCommitStatus sets a status of a commit: pending, success, error or failure.
+	CreateCommitStatus(ctx context.Context, sha, state, statusContext, description string) error
+
+	// CreateCheckRun creates a completed check run of a commit.
+	CreateCheckRun(ctx context.Context, run CheckRun) error
+
+	// CreateReview creates a pull request review of a commit with comments on file lines.
+	CreateReview(ctx context.Context, number int, sha, body string, comments []ReviewComment) error
 }
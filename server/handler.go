@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -24,7 +25,9 @@ func (r *Router) handlerGithubWebHook(w http.ResponseWriter, req *http.Request)
 		return
 	case github.PullRequestPayload:
 		pr := payload.(github.PullRequestPayload)
-		err = r.c.HandlePullRequestWebhook(pr)
+		// github closes the connection after a timeout, the request context is not used,
+		// so restoring components is not interrupted halfway.
+		err = r.c.HandlePullRequestWebhook(context.Background(), pr)
 		if err != nil {
 			logrus.Errorf("Error handling pull request payload: %s", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -37,7 +40,7 @@ func (r *Router) reloadConfig(w http.ResponseWriter, req *http.Request) {
 	fn := r.c.ReloadUserConfigsAndPoll
 
 	if sync := req.URL.Query().Get("sync"); sync == "1" {
-		if err := fn(nil); err != nil {
+		if err := fn(req.Context(), nil); err != nil {
 			logrus.Errorf("Error polling user config: %s", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -46,12 +49,37 @@ func (r *Router) reloadConfig(w http.ResponseWriter, req *http.Request) {
 	}
 
 	go func() {
-		if err := fn(nil); err != nil {
+		if err := fn(context.Background(), nil); err != nil {
 			logrus.Errorf("Error reloading user config from web handler: %s", err)
 		}
 	}()
 }
 
+// handlerDiscovery reports every datadog component as managed or unmanaged, as JSON or as CSV with format=csv.
+func (r *Router) handlerDiscovery(w http.ResponseWriter, req *http.Request) {
+	report, err := r.c.Discover(req.Context())
+	if err != nil {
+		logrus.Errorf("Error discovering datadog components: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.URL.Query().Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=discovery.csv")
+		if err := report.WriteCSV(w); err != nil {
+			logrus.Errorf("Error writing discovery report as csv: %s", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logrus.Errorf("Error encoding discovery report: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (r *Router) handlerVersion(w http.ResponseWriter, req *http.Request) {
 	if r.version == nil {
 		logrus.Warn("Version was not set")
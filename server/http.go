@@ -33,6 +33,7 @@ func New(cfg *config.Config, opts ...Option) (*Router, error) {
 	// protect exposed http endpoints with simple secret, the client is supposed to include
 	// "Authorization: <secret>" header to access endpoints.
 	sub.Handle("/watchdog/config/reload", simpleAuth(cfg.GetHTTPSecret(), http.HandlerFunc(r.reloadConfig))).Methods("POST")
+	sub.Handle("/watchdog/discovery", simpleAuth(cfg.GetHTTPSecret(), http.HandlerFunc(r.handlerDiscovery))).Methods("GET")
 	sub.HandleFunc("/version", r.handlerVersion)
 
 	for _, opt := range opts {